@@ -0,0 +1,134 @@
+// Package gracefulshutdown decorates an *http.Server so that it stops accepting new requests and
+// finishes the ones in flight when the program is asked to stop, before running any clean-up
+// hooks such as flushing stores or closing database pools.
+package gracefulshutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultTimeout is how long in-flight requests and hooks are given to finish.
+const DefaultTimeout = 30 * time.Second
+
+// HTTPServer is the part of *http.Server we need to start and stop it.
+type HTTPServer interface {
+	ListenAndServe() error
+	Shutdown(ctx context.Context) error
+}
+
+// Hook is run after the server has stopped serving requests.
+type Hook struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Server wraps an HTTPServer, shutting it down when a signal arrives.
+type Server struct {
+	delegate HTTPServer
+	timeout  time.Duration
+	signals  []os.Signal
+	shutdown <-chan os.Signal
+	hooks    []Hook
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithTimeout sets how long shutting down, including running hooks, may take.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.timeout = timeout
+	}
+}
+
+// WithSignals replaces the signals which trigger a shutdown, which default to os.Interrupt and SIGTERM.
+func WithSignals(signals ...os.Signal) Option {
+	return func(s *Server) {
+		s.signals = signals
+	}
+}
+
+// WithShutdownSignal triggers a shutdown when anything is sent on shutdown, rather than listening
+// for signals from the operating system. It is mostly useful for tests.
+func WithShutdownSignal(shutdown <-chan os.Signal) Option {
+	return func(s *Server) {
+		s.shutdown = shutdown
+	}
+}
+
+// WithHook adds a hook to run once the server has stopped. Hooks run in the order they were added.
+func WithHook(name string, run func(ctx context.Context) error) Option {
+	return func(s *Server) {
+		s.hooks = append(s.hooks, Hook{Name: name, Run: run})
+	}
+}
+
+// NewServer decorates server with graceful shutdown.
+func NewServer(server HTTPServer, options ...Option) *Server {
+	s := &Server{
+		delegate: server,
+		timeout:  DefaultTimeout,
+		signals:  []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+// ListenAndServe serves until a shutdown signal arrives or ctx is cancelled, then shuts the
+// server down and runs the hooks. It returns an error if the server could not start, did not
+// shut down in time, or any hook failed; every hook is run regardless, even if the server never
+// started, so anything opened for it is still cleaned up.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	shutdown := s.shutdown
+	if shutdown == nil {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, s.signals...)
+		defer signal.Stop(signals)
+		shutdown = signals
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.delegate.ListenAndServe()
+	}()
+
+	var errs []error
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs = append(errs, err)
+		}
+	case <-shutdown:
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.timeout)
+	defer cancel()
+
+	// a server which failed to serve has nothing to shut down
+	if len(errs) == 0 {
+		if err := s.delegate.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("shutting down server: %w", err))
+		}
+	}
+
+	for _, hook := range s.hooks {
+		if err := hook.Run(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("running shutdown hook %q: %w", hook.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
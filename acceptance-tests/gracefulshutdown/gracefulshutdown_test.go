@@ -0,0 +1,194 @@
+package gracefulshutdown_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/acceptance-tests/gracefulshutdown"
+)
+
+func TestServer(t *testing.T) {
+	t.Run("shuts down the server then runs hooks in order when signalled", func(t *testing.T) {
+		var (
+			server   = newSpyServer()
+			signals  = make(chan os.Signal, 1)
+			calls    = &callLog{}
+			graceful = gracefulshutdown.NewServer(server,
+				gracefulshutdown.WithShutdownSignal(signals),
+				gracefulshutdown.WithHook("flush store", calls.hook("flush store", server, nil)),
+				gracefulshutdown.WithHook("close db", calls.hook("close db", server, nil)),
+			)
+		)
+
+		signals <- os.Interrupt
+		assertNoError(t, graceful.ListenAndServe(context.Background()))
+
+		want := []string{"flush store after shutdown", "close db after shutdown"}
+		if got := calls.get(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got calls %v want %v", got, want)
+		}
+	})
+
+	t.Run("shuts down when the context is cancelled", func(t *testing.T) {
+		server := newSpyServer()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		graceful := gracefulshutdown.NewServer(server, gracefulshutdown.WithShutdownSignal(make(chan os.Signal)))
+
+		assertNoError(t, graceful.ListenAndServe(ctx))
+		if !server.wasShutdown() {
+			t.Error("expected server to be shut down")
+		}
+	})
+
+	t.Run("returns the error if the server fails to start", func(t *testing.T) {
+		server := newSpyServer()
+		server.listenErr = errors.New("address in use")
+
+		graceful := gracefulshutdown.NewServer(server, gracefulshutdown.WithShutdownSignal(make(chan os.Signal)))
+
+		err := graceful.ListenAndServe(context.Background())
+		if !errors.Is(err, server.listenErr) {
+			t.Errorf("got error %v want %v", err, server.listenErr)
+		}
+		if server.wasShutdown() {
+			t.Error("did not expect a server which never started to be shut down")
+		}
+	})
+
+	t.Run("still runs the hooks if the server fails to start", func(t *testing.T) {
+		var (
+			server   = newSpyServer()
+			calls    = &callLog{}
+			graceful = gracefulshutdown.NewServer(server,
+				gracefulshutdown.WithShutdownSignal(make(chan os.Signal)),
+				gracefulshutdown.WithHook("close db", calls.hook("close db", server, nil)),
+			)
+		)
+		server.listenErr = errors.New("address in use")
+
+		err := graceful.ListenAndServe(context.Background())
+
+		if !errors.Is(err, server.listenErr) {
+			t.Errorf("got error %v want %v", err, server.listenErr)
+		}
+		want := []string{"close db before shutdown"}
+		if got := calls.get(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got calls %v want %v", got, want)
+		}
+	})
+
+	t.Run("runs every hook even if one fails, reporting the failure", func(t *testing.T) {
+		var (
+			server   = newSpyServer()
+			signals  = make(chan os.Signal, 1)
+			calls    = &callLog{}
+			flushErr = errors.New("disk full")
+			graceful = gracefulshutdown.NewServer(server,
+				gracefulshutdown.WithShutdownSignal(signals),
+				gracefulshutdown.WithHook("flush store", calls.hook("flush store", server, flushErr)),
+				gracefulshutdown.WithHook("close db", calls.hook("close db", server, nil)),
+			)
+		)
+
+		signals <- os.Interrupt
+		err := graceful.ListenAndServe(context.Background())
+
+		if !errors.Is(err, flushErr) {
+			t.Errorf("got error %v want it to wrap %v", err, flushErr)
+		}
+		if got := len(calls.get()); got != 2 {
+			t.Errorf("expected both hooks to run but %d did", got)
+		}
+	})
+
+	t.Run("hooks are given a context which expires after the timeout", func(t *testing.T) {
+		signals := make(chan os.Signal, 1)
+		graceful := gracefulshutdown.NewServer(newSpyServer(),
+			gracefulshutdown.WithShutdownSignal(signals),
+			gracefulshutdown.WithTimeout(10*time.Millisecond),
+			gracefulshutdown.WithHook("slow", func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}),
+		)
+
+		signals <- os.Interrupt
+		err := graceful.ListenAndServe(context.Background())
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %v want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+type spyServer struct {
+	listenErr error
+	stopped   chan struct{}
+	once      sync.Once
+}
+
+func newSpyServer() *spyServer {
+	return &spyServer{stopped: make(chan struct{})}
+}
+
+func (s *spyServer) ListenAndServe() error {
+	if s.listenErr != nil {
+		return s.listenErr
+	}
+	<-s.stopped
+	return http.ErrServerClosed
+}
+
+func (s *spyServer) Shutdown(ctx context.Context) error {
+	s.once.Do(func() { close(s.stopped) })
+	return nil
+}
+
+func (s *spyServer) wasShutdown() bool {
+	select {
+	case <-s.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+type callLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (c *callLog) hook(name string, server *spyServer, err error) func(context.Context) error {
+	return func(context.Context) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		when := "before shutdown"
+		if server.wasShutdown() {
+			when = "after shutdown"
+		}
+		c.calls = append(c.calls, name+" "+when)
+		return err
+	}
+}
+
+func (c *callLog) get() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.calls...)
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("didn't expect an error but got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package gracetest
+
+import (
+	"testing"
+	"time"
+)
+
+const requestTimeout = 3 * time.Second
+
+// CanGet fails the test if a GET to url does not succeed within 3 seconds.
+func CanGet(t testing.TB, url string) {
+	t.Helper()
+
+	select {
+	case err := <-get(url):
+		if err != nil {
+			t.Errorf("expected to be able to GET %s but got %v", url, err)
+		}
+	case <-time.After(requestTimeout):
+		t.Errorf("timed out waiting for request to %s", url)
+	}
+}
+
+// CantGet fails the test if a GET to url succeeds.
+func CantGet(t testing.TB, url string) {
+	t.Helper()
+
+	select {
+	case err := <-get(url):
+		if err == nil {
+			t.Errorf("expected GET %s to fail but it succeeded", url)
+		}
+	case <-time.After(requestTimeout):
+	}
+}
+
+func get(url string) <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		res, err := client.Get(url)
+		if err != nil {
+			errs <- err
+			return
+		}
+		res.Body.Close()
+		errs <- nil
+	}()
+	return errs
+}
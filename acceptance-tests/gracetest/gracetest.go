@@ -0,0 +1,247 @@
+// Package gracetest helps write acceptance tests for servers which should shut down gracefully.
+// It builds a real binary, runs it on a free port, waits for it to be ready and lets the test
+// send it SIGTERM, capturing everything the program logs along the way.
+package gracetest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// BuildBinary compiles the main package found at pkgPath (as you would pass to go build) into a
+// temporary directory, which is removed when the test finishes.
+func BuildBinary(t testing.TB, pkgPath string) string {
+	t.Helper()
+
+	binPath := filepath.Join(t.TempDir(), "test-binary")
+	build := exec.Command("go", "build", "-o", binPath, pkgPath)
+
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("cannot build %s: %v\n%s", pkgPath, err, out)
+	}
+
+	return binPath
+}
+
+// FreePort returns a port which nothing was listening on when it was called.
+func FreePort(t testing.TB) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot find a free port: %v", err)
+	}
+	defer listener.Close()
+
+	return fmt.Sprint(listener.Addr().(*net.TCPAddr).Port)
+}
+
+// Option configures how a Program is run.
+type Option func(*exec.Cmd)
+
+// WithArgs passes command line arguments to the program.
+func WithArgs(args ...string) Option {
+	return func(cmd *exec.Cmd) {
+		cmd.Args = append(cmd.Args, args...)
+	}
+}
+
+// WithDir runs the program in dir rather than the test's working directory.
+func WithDir(dir string) Option {
+	return func(cmd *exec.Cmd) {
+		cmd.Dir = dir
+	}
+}
+
+// WithEnv adds environment variables, in the form "KEY=value", to the program's environment.
+func WithEnv(env ...string) Option {
+	return func(cmd *exec.Cmd) {
+		cmd.Env = append(os.Environ(), env...)
+	}
+}
+
+// Program is a running binary under test.
+type Program struct {
+	cmd    *exec.Cmd
+	logs   *syncBuffer
+	exited chan struct{}
+	err    error
+}
+
+// Start runs the binary at binPath. The program is killed when the test finishes if it is still
+// running, and its output is logged if the test failed.
+func Start(t testing.TB, binPath string, options ...Option) *Program {
+	t.Helper()
+
+	logs := &syncBuffer{}
+	cmd := exec.Command(binPath)
+	cmd.Stdout = logs
+	cmd.Stderr = logs
+
+	for _, option := range options {
+		option(cmd)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("cannot start %s: %v", binPath, err)
+	}
+
+	p := &Program{cmd: cmd, logs: logs, exited: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		close(p.exited)
+	}()
+
+	t.Cleanup(func() {
+		select {
+		case <-p.exited:
+		default:
+			cmd.Process.Kill()
+			<-p.exited
+		}
+		if t.Failed() {
+			t.Logf("output of %s:\n%s", binPath, p.Logs())
+		}
+	})
+
+	return p
+}
+
+// Interrupt sends the program SIGTERM, as an orchestrator would before stopping it.
+func (p *Program) Interrupt() error {
+	return p.cmd.Process.Signal(syscall.SIGTERM)
+}
+
+// WaitForExit waits up to timeout for the program to exit, returning the error it exited with.
+func (p *Program) WaitForExit(timeout time.Duration) error {
+	select {
+	case <-p.exited:
+		return p.err
+	case <-time.After(timeout):
+		return fmt.Errorf("program did not exit within %v", timeout)
+	}
+}
+
+// Logs returns everything the program has written to stdout and stderr so far.
+func (p *Program) Logs() string {
+	return p.logs.String()
+}
+
+// WaitForLog waits up to timeout for the program to write text to its output.
+func (p *Program) WaitForLog(text string, timeout time.Duration) error {
+	return p.WaitUntilReady(timeout, func() error {
+		if !strings.Contains(p.Logs(), text) {
+			return fmt.Errorf("%q not logged yet", text)
+		}
+		return nil
+	})
+}
+
+// ErrNotReady is returned by WaitUntilReady when the probe never succeeded.
+var ErrNotReady = errors.New("program did not become ready")
+
+// WaitUntilReady calls probe until it returns nil, backing off exponentially between attempts
+// starting at 10ms, giving up after timeout or if the program exits.
+func (p *Program) WaitUntilReady(timeout time.Duration, probe func() error) error {
+	deadline := time.Now().Add(timeout)
+	backoff := 10 * time.Millisecond
+
+	for {
+		err := probe()
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("%w within %v, last error: %v", ErrNotReady, timeout, err)
+		}
+
+		select {
+		case <-p.exited:
+			return fmt.Errorf("%w, it exited: %v", ErrNotReady, p.err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+	}
+}
+
+// HTTPProbe succeeds once url responds with a status below 500.
+func HTTPProbe(url string) func() error {
+	return func() error {
+		res, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		res.Body.Close()
+
+		if res.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("got status %d from %s", res.StatusCode, url)
+		}
+		return nil
+	}
+}
+
+// ReadinessProbe succeeds once url, a readiness endpoint like /readyz, responds 200 OK. A server
+// can be up and answering requests before it is ready, so unlike HTTPProbe any other status is a
+// failure, and its body, which should say what isn't ready, is included in the error.
+func ReadinessProbe(url string) func() error {
+	return func() error {
+		res, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+			return fmt.Errorf("got status %d from %s: %s", res.StatusCode, url, strings.TrimSpace(string(body)))
+		}
+		return nil
+	}
+}
+
+// TCPProbe succeeds once something is listening on address.
+func TCPProbe(address string) func() error {
+	return func() error {
+		conn, err := net.Dial("tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// client doesn't keep connections alive, as the server would wait for an idle one we dialled but
+// never used before it could finish shutting down.
+var client = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+// syncBuffer lets the program's output be read while it is still being written.
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.buf.String()
+}
@@ -0,0 +1,84 @@
+package gracetest_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/acceptance-tests/gracetest"
+)
+
+func TestGracefulShutdownKit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs a binary")
+	}
+
+	binPath := gracetest.BuildBinary(t, "./testdata/slowserver")
+
+	t.Run("in-flight requests complete after SIGTERM", func(t *testing.T) {
+		port := gracetest.FreePort(t)
+		url := "http://localhost:" + port
+		program := gracetest.Start(t, binPath, gracetest.WithArgs("-port", port))
+
+		if err := program.WaitUntilReady(5*time.Second, gracetest.TCPProbe("localhost:"+port)); err != nil {
+			t.Fatal(err)
+		}
+
+		go func() {
+			if err := program.WaitForLog("handling request", 5*time.Second); err != nil {
+				t.Error(err)
+			}
+			if err := program.Interrupt(); err != nil {
+				t.Error(err)
+			}
+		}()
+		gracetest.CanGet(t, url)
+
+		if err := program.WaitForExit(5 * time.Second); err != nil {
+			t.Errorf("expected a clean exit but got %v", err)
+		}
+		gracetest.CantGet(t, url)
+
+		if logs := program.Logs(); !strings.Contains(logs, "shutting down") || !strings.Contains(logs, "bye") {
+			t.Errorf("expected shutdown to be logged, got %q", logs)
+		}
+	})
+
+	t.Run("readiness probing gives up if the program never becomes ready", func(t *testing.T) {
+		program := gracetest.Start(t, binPath, gracetest.WithArgs("-port", gracetest.FreePort(t)))
+
+		err := program.WaitUntilReady(50*time.Millisecond, func() error { return errors.New("not yet") })
+
+		if !errors.Is(err, gracetest.ErrNotReady) {
+			t.Errorf("got error %v want %v", err, gracetest.ErrNotReady)
+		}
+	})
+}
+
+func TestReadinessProbe(t *testing.T) {
+	var ready atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"status":"failing"}`)
+		}
+	}))
+	defer server.Close()
+
+	probe := gracetest.ReadinessProbe(server.URL)
+
+	err := probe()
+	if err == nil || !strings.Contains(err.Error(), `{"status":"failing"}`) {
+		t.Errorf("expected an error saying why it wasn't ready, got %v", err)
+	}
+
+	ready.Store(true)
+	if err := probe(); err != nil {
+		t.Errorf("expected it to be ready, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+// A server with a slow handler which shuts down gracefully, used to test gracetest itself.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var port = flag.String("port", "8080", "port to listen on")
+
+func main() {
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr: ":" + *port,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			log.Println("handling request")
+			time.Sleep(500 * time.Millisecond)
+			w.Write([]byte("done"))
+		}),
+	}
+
+	go func() {
+		log.Println("listening on", *port)
+		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("shutting down")
+	if err := server.Shutdown(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	log.Println("bye")
+}
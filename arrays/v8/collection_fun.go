@@ -1,5 +1,7 @@
 package main
 
+import "github.com/quii/learn-go-with-tests/generics"
+
 func Find[A any](items []A, predicate func(A) bool) (value A, found bool) {
 	for _, v := range items {
 		if predicate(v) {
@@ -9,10 +11,7 @@ func Find[A any](items []A, predicate func(A) bool) (value A, found bool) {
 	return
 }
 
+// Reduce is the generics package's Reduce, which combines every item in collection into one value.
 func Reduce[A, B any](collection []A, f func(B, A) B, initialValue B) B {
-	var result = initialValue
-	for _, x := range collection {
-		result = f(result, x)
-	}
-	return result
+	return generics.Reduce(collection, f, initialValue)
 }
@@ -1,21 +1,20 @@
 package main
 
+import "github.com/quii/learn-go-with-tests/generics"
+
 // Sum calculates the total from a slice of numbers.
 func Sum(numbers []int) int {
-	add := func(acc, x int) int { return acc + x }
-	return Reduce(numbers, add, 0)
+	return generics.Sum(numbers)[0]
 }
 
 // SumAllTails calculates the sums of all but the first number given a collection of slices.
 func SumAllTails(numbers ...[]int) []int {
-	sumTail := func(acc, x []int) []int {
+	tail := func(tails [][]int, x []int) [][]int {
 		if len(x) == 0 {
-			return append(acc, 0)
-		} else {
-			tail := x[1:]
-			return append(acc, Sum(tail))
+			return append(tails, x)
 		}
+		return append(tails, x[1:])
 	}
 
-	return Reduce(numbers, sumTail, []int{})
+	return generics.Sum(Reduce(numbers, tail, [][]int{})...)
 }
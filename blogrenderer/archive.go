@@ -0,0 +1,100 @@
+package blogrenderer
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Undated is the year, and month, that posts without a date are archived under.
+const Undated = 0
+
+// Archive groups posts by the year and month they were published. Within a month the newest
+// posts come first, and posts from the same day are in order of title. Posts without a date are
+// under Archive(posts)[Undated][Undated], in order of title.
+func Archive(posts []Post) map[int]map[time.Month][]Post {
+	return archive(posts, func(p Post) Post { return p })
+}
+
+func archive[T any](items []T, post func(T) Post) map[int]map[time.Month][]T {
+	grouped := map[int]map[time.Month][]T{}
+
+	for _, item := range items {
+		year, month := Undated, time.Month(Undated)
+		if date := post(item).Date; !date.IsZero() {
+			year, month = date.Year(), date.Month()
+		}
+
+		if grouped[year] == nil {
+			grouped[year] = map[time.Month][]T{}
+		}
+		grouped[year][month] = append(grouped[year][month], item)
+	}
+
+	for _, months := range grouped {
+		for _, month := range months {
+			slices.SortStableFunc(month, func(a, b T) int {
+				return cmp.Or(post(b).Date.Compare(post(a).Date), cmp.Compare(post(a).Title, post(b).Title))
+			})
+		}
+	}
+
+	return grouped
+}
+
+// ArchiveYear is a year of the archive page, with the posts from each month of it. The undated
+// posts come as a year of their own, with a single month.
+type ArchiveYear struct {
+	Year   int
+	Months []ArchiveMonth
+}
+
+// ArchiveMonth is the posts from one month of the archive page.
+type ArchiveMonth struct {
+	Month time.Month
+	Links []PostLink
+}
+
+// Label is how the year is headed on the archive page.
+func (y ArchiveYear) Label() string {
+	if y.Year == Undated {
+		return "undated"
+	}
+	return strconv.Itoa(y.Year)
+}
+
+// Count is how many posts there are in the year.
+func (y ArchiveYear) Count() int {
+	count := 0
+	for _, m := range y.Months {
+		count += len(m.Links)
+	}
+	return count
+}
+
+// ArchiveYears lays out the archive page, newest year and month first with the undated posts last.
+func ArchiveYears(links []PostLink) []ArchiveYear {
+	grouped := archive(links, func(l PostLink) Post { return l.Post })
+
+	years := make([]ArchiveYear, 0, len(grouped))
+	for year, months := range grouped {
+		archiveYear := ArchiveYear{Year: year}
+		for month, links := range months {
+			archiveYear.Months = append(archiveYear.Months, ArchiveMonth{Month: month, Links: links})
+		}
+		slices.SortFunc(archiveYear.Months, func(a, b ArchiveMonth) int { return cmp.Compare(b.Month, a.Month) })
+		years = append(years, archiveYear)
+	}
+
+	slices.SortFunc(years, func(a, b ArchiveYear) int {
+		switch {
+		case a.Year == Undated:
+			return 1
+		case b.Year == Undated:
+			return -1
+		}
+		return cmp.Compare(b.Year, a.Year)
+	})
+	return years
+}
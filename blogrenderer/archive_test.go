@@ -0,0 +1,90 @@
+package blogrenderer_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/blogrenderer"
+)
+
+func TestArchive(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	var (
+		early    = blogrenderer.Post{Title: "Early", Date: date(2023, time.March, 2)}
+		late     = blogrenderer.Post{Title: "Late", Date: date(2023, time.March, 30)}
+		sameDayA = blogrenderer.Post{Title: "A same day", Date: date(2023, time.March, 15)}
+		sameDayB = blogrenderer.Post{Title: "B same day", Date: date(2023, time.March, 15)}
+		december = blogrenderer.Post{Title: "December", Date: date(2023, time.December, 25)}
+		nextYear = blogrenderer.Post{Title: "Next year", Date: date(2024, time.January, 1)}
+		undatedZ = blogrenderer.Post{Title: "Z undated"}
+		undatedA = blogrenderer.Post{Title: "A undated"}
+	)
+
+	t.Run("groups posts by year and month, newest first within a month", func(t *testing.T) {
+		got := blogrenderer.Archive([]blogrenderer.Post{early, nextYear, sameDayB, late, december, sameDayA})
+
+		want := map[int]map[time.Month][]blogrenderer.Post{
+			2023: {
+				time.March:    {late, sameDayA, sameDayB, early},
+				time.December: {december},
+			},
+			2024: {
+				time.January: {nextYear},
+			},
+		}
+		assertArchive(t, got, want)
+	})
+
+	t.Run("posts missing dates are undated, in order of title", func(t *testing.T) {
+		got := blogrenderer.Archive([]blogrenderer.Post{undatedZ, early, undatedA})
+
+		want := map[int]map[time.Month][]blogrenderer.Post{
+			2023:                 {time.March: {early}},
+			blogrenderer.Undated: {blogrenderer.Undated: {undatedA, undatedZ}},
+		}
+		assertArchive(t, got, want)
+	})
+
+	t.Run("no posts make an empty archive", func(t *testing.T) {
+		assertArchive(t, blogrenderer.Archive(nil), map[int]map[time.Month][]blogrenderer.Post{})
+	})
+
+	t.Run("the archive page has the newest years and months first and the undated posts last", func(t *testing.T) {
+		links := blogrenderer.Links([]blogrenderer.Post{undatedZ, early, december, nextYear, late})
+
+		var got []string
+		for _, year := range blogrenderer.ArchiveYears(links) {
+			for _, month := range year.Months {
+				for _, link := range month.Links {
+					got = append(got, year.Label()+" "+link.Title)
+				}
+			}
+		}
+
+		want := []string{"2024 Next year", "2023 December", "2023 Late", "2023 Early", "undated Z undated"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("the archive page counts the posts in each year", func(t *testing.T) {
+		years := blogrenderer.ArchiveYears(blogrenderer.Links([]blogrenderer.Post{early, december, late, undatedA}))
+
+		for i, want := range []int{3, 1} {
+			if got := years[i].Count(); got != want {
+				t.Errorf("got %d posts in %s want %d", got, years[i].Label(), want)
+			}
+		}
+	})
+}
+
+func assertArchive(t testing.TB, got, want map[int]map[time.Month][]blogrenderer.Post) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+// Serves a blog from a directory of markdown posts, picking up edits as they are made.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/blogrenderer"
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+)
+
+var (
+	addr = flag.String("addr", ":8080", "address to serve the blog on")
+	dir  = flag.String("dir", "posts", "directory containing the blog posts")
+	site = flag.String("site", "http://localhost:8080", "base URL the blog is published at, used in sitemap.xml and robots.txt")
+	wpm  = flag.Int("wpm", blogposts.DefaultWordsPerMinute, "reading speed used to estimate how long posts take to read")
+
+	postsPerPage = flag.Int("per-page", 10, "how many posts to show on each page of the index, or 0 to show them all on one")
+)
+
+// relatedPostsShown is how many posts are suggested at the end of each post.
+const relatedPostsShown = 3
+
+func main() {
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	posts, err := blogposts.NewLivePosts(*dir)
+	if err != nil {
+		log.Fatalf("problem reading posts from %s, %v", *dir, err)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	go posts.Watch(ctx, ticker.C, func(err error) {
+		log.Printf("problem reloading posts from %s, %v", *dir, err)
+	})
+
+	renderer, err := blogrenderer.NewPostRenderer()
+	if err != nil {
+		log.Fatalf("problem creating post renderer, %v", err)
+	}
+
+	server := NewBlogServer(renderer, blogrenderer.Site{BaseURL: *site, PostsPerPage: *postsPerPage}, func() []blogrenderer.Post {
+		return toRendererPosts(posts.Posts(), *wpm)
+	})
+
+	log.Printf("serving %s on %s", *dir, *addr)
+	if err := ListenAndServe(ctx, *addr, server); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func toRendererPosts(posts []blogposts.Post, wordsPerMinute int) []blogrenderer.Post {
+	converted := make([]blogrenderer.Post, 0, len(posts))
+	for _, p := range posts {
+		p = p.WithWordsPerMinute(wordsPerMinute)
+		converted = append(converted, blogrenderer.Post{
+			Title:       p.Title,
+			Description: p.Description,
+			Body:        p.Body,
+			Tags:        p.Tags,
+			Date:        p.Date,
+			WordCount:   p.WordCount,
+			ReadingTime: p.ReadingTime,
+		})
+	}
+
+	// link to related posts by the slugs the server will find them at
+	links := blogrenderer.Links(converted)
+	for i := range posts {
+		for _, j := range blogposts.RelatedPostIndexes(posts, i, relatedPostsShown) {
+			converted[i].Related = append(converted[i].Related, links[j])
+		}
+	}
+
+	return converted
+}
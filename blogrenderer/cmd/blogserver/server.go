@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/acceptance-tests/gracefulshutdown"
+	"github.com/quii/learn-go-with-tests/blogrenderer"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// BlogServer serves the index, posts, tag and archive pages of a blog. The index is split into pages of
+// the site's PostsPerPage.
+type BlogServer struct {
+	renderer *blogrenderer.PostRenderer
+	site     blogrenderer.Site
+	posts    func() []blogrenderer.Post
+	http.Handler
+}
+
+// NewBlogServer creates a BlogServer for site which asks posts for the current posts on every
+// request, so edits show up without a restart.
+func NewBlogServer(renderer *blogrenderer.PostRenderer, site blogrenderer.Site, posts func() []blogrenderer.Post) *BlogServer {
+	s := &BlogServer{renderer: renderer, site: site, posts: posts}
+
+	router := http.NewServeMux()
+	router.HandleFunc("GET /{$}", s.index)
+	router.HandleFunc("GET /page/{number}", s.page)
+	router.HandleFunc("GET /posts/{slug}", s.post)
+	router.HandleFunc("GET /tags/{tag}", s.tag)
+	router.HandleFunc("GET /archive", s.archive)
+	router.HandleFunc("GET /sitemap.xml", s.sitemap)
+	router.HandleFunc("GET /robots.txt", s.robots)
+
+	s.Handler = router
+	return s
+}
+
+func (s *BlogServer) index(w http.ResponseWriter, r *http.Request) {
+	s.renderPage(w, r, 1)
+}
+
+// page serves the pages of the index after the first, which is the home page.
+func (s *BlogServer) page(w http.ResponseWriter, r *http.Request) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if number == 1 {
+		http.Redirect(w, r, blogrenderer.PagePath(1), http.StatusMovedPermanently)
+		return
+	}
+
+	s.renderPage(w, r, number)
+}
+
+func (s *BlogServer) renderPage(w http.ResponseWriter, r *http.Request, number int) {
+	pages := blogrenderer.Paginate(blogrenderer.Links(s.posts()), s.site.PostsPerPage)
+	if number < 1 || number > len(pages) {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := s.renderer.RenderPage(w, pages[number-1]); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *BlogServer) post(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+
+	for _, link := range blogrenderer.Links(s.posts()) {
+		if link.Slug == slug {
+			if err := s.renderer.Render(w, link.Post); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+
+	http.NotFound(w, r)
+}
+
+func (s *BlogServer) tag(w http.ResponseWriter, r *http.Request) {
+	tag := r.PathValue("tag")
+
+	var tagged []blogrenderer.PostLink
+	for _, link := range blogrenderer.Links(s.posts()) {
+		if hasTag(link.Post, tag) {
+			tagged = append(tagged, link)
+		}
+	}
+
+	if len(tagged) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := s.renderer.RenderLinks(w, tagged); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *BlogServer) archive(w http.ResponseWriter, r *http.Request) {
+	if err := s.renderer.RenderArchive(w, blogrenderer.Links(s.posts())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *BlogServer) sitemap(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/xml")
+	if err := blogrenderer.WriteSitemap(w, s.site, blogrenderer.Links(s.posts())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *BlogServer) robots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "text/plain; charset=utf-8")
+	if err := blogrenderer.WriteRobots(w, s.site); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func hasTag(p blogrenderer.Post, tag string) bool {
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ListenAndServe serves handler on addr until ctx is cancelled, then lets in-flight requests
+// finish before returning.
+func ListenAndServe(ctx context.Context, addr string, handler http.Handler) error {
+	server := gracefulshutdown.NewServer(
+		&http.Server{Addr: addr, Handler: handler},
+		gracefulshutdown.WithTimeout(shutdownTimeout),
+	)
+	return server.ListenAndServe(ctx)
+}
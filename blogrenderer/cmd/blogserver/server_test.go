@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/blogrenderer"
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+)
+
+var testSite = blogrenderer.Site{BaseURL: "https://example.com"}
+
+func TestBlogServer(t *testing.T) {
+	posts := []blogrenderer.Post{
+		{Title: "Hello World", Body: "first", Tags: []string{"go"}},
+		{Title: "Hello World", Body: "second", Tags: []string{"tdd"}},
+		{Title: "Rust", Body: "borrow checker", Tags: []string{"rust", "go"}},
+	}
+	server := mustMakeBlogServer(t, posts)
+
+	t.Run("the index links to every post by slug", func(t *testing.T) {
+		response := get(server, "/")
+
+		assertStatus(t, response, http.StatusOK)
+		assertBodyContains(t, response, `href="/posts/hello-world"`, `href="/posts/hello-world-2"`, `href="/posts/rust"`)
+	})
+
+	t.Run("posts with clashing titles get their own slugs", func(t *testing.T) {
+		first := get(server, "/posts/hello-world")
+		second := get(server, "/posts/hello-world-2")
+
+		assertStatus(t, first, http.StatusOK)
+		assertBodyContains(t, first, "first")
+		assertStatus(t, second, http.StatusOK)
+		assertBodyContains(t, second, "second")
+	})
+
+	t.Run("tag pages only list tagged posts, keeping their slugs", func(t *testing.T) {
+		response := get(server, "/tags/go")
+
+		assertStatus(t, response, http.StatusOK)
+		assertBodyContains(t, response, `href="/posts/hello-world"`, `href="/posts/rust"`)
+		if strings.Contains(response.Body.String(), "hello-world-2") {
+			t.Errorf("did not expect the tdd post to be listed under go, got %s", response.Body)
+		}
+	})
+
+	t.Run("unknown posts and tags are not found", func(t *testing.T) {
+		assertStatus(t, get(server, "/posts/nope"), http.StatusNotFound)
+		assertStatus(t, get(server, "/tags/nope"), http.StatusNotFound)
+		assertStatus(t, get(server, "/nope"), http.StatusNotFound)
+	})
+
+	t.Run("the index is split into pages", func(t *testing.T) {
+		site := blogrenderer.Site{BaseURL: testSite.BaseURL, PostsPerPage: 2}
+		server := NewBlogServer(mustMakeRenderer(t), site, func() []blogrenderer.Post { return posts })
+
+		first := get(server, "/")
+		assertStatus(t, first, http.StatusOK)
+		assertBodyContains(t, first, `href="/posts/hello-world"`, `href="/posts/hello-world-2"`, `href="/page/2"`)
+
+		second := get(server, "/page/2")
+		assertStatus(t, second, http.StatusOK)
+		assertBodyContains(t, second, `href="/posts/rust"`, `href="/"`)
+		if strings.Contains(second.Body.String(), `href="/posts/hello-world"`) {
+			t.Errorf("did not expect the first page's posts on the second, got %s", second.Body)
+		}
+
+		assertStatus(t, get(server, "/page/1"), http.StatusMovedPermanently)
+		assertStatus(t, get(server, "/page/3"), http.StatusNotFound)
+		assertStatus(t, get(server, "/page/0"), http.StatusNotFound)
+		assertStatus(t, get(server, "/page/two"), http.StatusNotFound)
+	})
+
+	t.Run("posts show how long they take to read", func(t *testing.T) {
+		server := mustMakeBlogServer(t, []blogrenderer.Post{{Title: "Long", WordCount: 450, ReadingTime: 2*time.Minute + 15*time.Second}})
+
+		response := get(server, "/posts/long")
+
+		assertStatus(t, response, http.StatusOK)
+		assertBodyContains(t, response, "450 words, 3 min read")
+	})
+
+	t.Run("posts suggest related posts", func(t *testing.T) {
+		server := mustMakeBlogServer(t, toRendererPosts([]blogposts.Post{
+			{Title: "Mocking", Tags: []string{"go", "tdd"}},
+			{Title: "Spies", Tags: []string{"go", "tdd"}},
+			{Title: "Recipes", Tags: []string{"cooking"}},
+		}, blogposts.DefaultWordsPerMinute))
+
+		response := get(server, "/posts/mocking")
+
+		assertStatus(t, response, http.StatusOK)
+		assertBodyContains(t, response, "You may also like", `<li><a href="/posts/spies">Spies</a></li>`)
+		if strings.Contains(response.Body.String(), "/posts/recipes") {
+			t.Errorf("did not expect an unrelated post to be suggested, got %s", response.Body)
+		}
+	})
+
+	t.Run("the archive lists posts by year and month", func(t *testing.T) {
+		server := mustMakeBlogServer(t, []blogrenderer.Post{
+			{Title: "Dated", Date: time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC)},
+			{Title: "Undated"},
+		})
+
+		response := get(server, "/archive")
+
+		assertStatus(t, response, http.StatusOK)
+		assertBodyContains(t, response, "2023 (1)", "March (1)", `href="/posts/dated"`, "undated (1)", `href="/posts/undated"`)
+	})
+
+	t.Run("serves a sitemap of every post", func(t *testing.T) {
+		response := get(server, "/sitemap.xml")
+
+		assertStatus(t, response, http.StatusOK)
+		assertBodyContains(t, response, "<loc>https://example.com/posts/hello-world-2</loc>", "<loc>https://example.com/posts/rust</loc>")
+		if got := response.Header().Get("content-type"); got != "application/xml" {
+			t.Errorf("got content-type %q want application/xml", got)
+		}
+	})
+
+	t.Run("posts whose titles have characters which can't be in a path can still be found", func(t *testing.T) {
+		server := mustMakeBlogServer(t, []blogrenderer.Post{{Title: "Why? Because/Reasons", Body: "reasons"}})
+
+		index := get(server, "/")
+		assertBodyContains(t, index, `href="/posts/why-becausereasons"`)
+
+		response := get(server, "/posts/why-becausereasons")
+		assertStatus(t, response, http.StatusOK)
+		assertBodyContains(t, response, "reasons")
+		assertBodyContains(t, get(server, "/sitemap.xml"), "<loc>https://example.com/posts/why-becausereasons</loc>")
+	})
+
+	t.Run("serves a robots.txt pointing at the sitemap", func(t *testing.T) {
+		response := get(server, "/robots.txt")
+
+		assertStatus(t, response, http.StatusOK)
+		assertBodyContains(t, response, "Sitemap: https://example.com/sitemap.xml")
+	})
+
+	t.Run("posts are fetched on every request so edits show up", func(t *testing.T) {
+		current := []blogrenderer.Post{{Title: "Before"}}
+		server := NewBlogServer(mustMakeRenderer(t), testSite, func() []blogrenderer.Post { return current })
+
+		current = []blogrenderer.Post{{Title: "After"}}
+
+		assertStatus(t, get(server, "/posts/after"), http.StatusOK)
+	})
+}
+
+func TestToRendererPosts(t *testing.T) {
+	posts := []blogposts.Post{{Title: "T", WordCount: 300, ReadingTime: blogposts.ReadingTime(300, blogposts.DefaultWordsPerMinute)}}
+
+	got := toRendererPosts(posts, 100)
+
+	if got[0].WordCount != 300 || got[0].ReadingTime != 3*time.Minute {
+		t.Errorf("got %+v, want 300 words read in 3m at 100 words a minute", got[0])
+	}
+
+	t.Run("related posts link to the slugs posts are served at", func(t *testing.T) {
+		posts := []blogposts.Post{
+			{Title: "Hello World", Body: "first", Tags: []string{"go"}},
+			{Title: "Hello World", Body: "second", Tags: []string{"go"}},
+		}
+
+		got := toRendererPosts(posts, blogposts.DefaultWordsPerMinute)
+
+		if len(got[0].Related) != 1 || got[0].Related[0].Slug != "hello-world-2" {
+			t.Errorf("got %+v, want the first post to suggest hello-world-2", got[0].Related)
+		}
+		if len(got[1].Related) != 1 || got[1].Related[0].Slug != "hello-world" {
+			t.Errorf("got %+v, want the second post to suggest hello-world", got[1].Related)
+		}
+	})
+}
+
+func TestListenAndServe(t *testing.T) {
+	t.Run("lets in-flight requests finish when the context is cancelled", func(t *testing.T) {
+		addr := freeAddr(t)
+		started := make(chan struct{})
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			w.Write([]byte("finished"))
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		served := make(chan error, 1)
+		go func() { served <- ListenAndServe(ctx, addr, handler) }()
+
+		responses := make(chan *http.Response, 1)
+		go func() {
+			response, err := retryGet("http://" + addr)
+			if err != nil {
+				t.Error(err)
+			}
+			responses <- response
+		}()
+
+		<-started
+		cancel()
+
+		response := <-responses
+		if response == nil {
+			t.FailNow()
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			t.Errorf("got status %d want %d", response.StatusCode, http.StatusOK)
+		}
+
+		if err := <-served; err != nil {
+			t.Errorf("didn't expect an error from a graceful shutdown but got %v", err)
+		}
+	})
+}
+
+func mustMakeRenderer(t testing.TB) *blogrenderer.PostRenderer {
+	t.Helper()
+	renderer, err := blogrenderer.NewPostRenderer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return renderer
+}
+
+func mustMakeBlogServer(t testing.TB, posts []blogrenderer.Post) *BlogServer {
+	t.Helper()
+	return NewBlogServer(mustMakeRenderer(t), testSite, func() []blogrenderer.Post { return posts })
+}
+
+func get(handler http.Handler, path string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func freeAddr(t testing.TB) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func retryGet(url string) (*http.Response, error) {
+	var err error
+	for i := 0; i < 50; i++ {
+		var response *http.Response
+		if response, err = http.Get(url); err == nil {
+			return response, nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return nil, err
+}
+
+func assertStatus(t testing.TB, response *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if response.Code != want {
+		t.Errorf("did not get correct status, got %d, want %d", response.Code, want)
+	}
+}
+
+func assertBodyContains(t testing.TB, response *httptest.ResponseRecorder, want ...string) {
+	t.Helper()
+	body := response.Body.String()
+	for _, w := range want {
+		if !strings.Contains(body, w) {
+			t.Errorf("expected body to contain %q, got %s", w, body)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package blogrenderer
+
+import "strconv"
+
+// Page is one page of an index which has been split up so it doesn't get too long.
+type Page struct {
+	Number, Total int
+	Links         []PostLink
+}
+
+// Paginate splits links into pages of perPage links, numbered from 1. There is always at least
+// one page, even if it is empty, and a perPage of 0 or less puts every link on it.
+func Paginate(links []PostLink, perPage int) []Page {
+	if perPage <= 0 || len(links) <= perPage {
+		return []Page{{Number: 1, Total: 1, Links: links}}
+	}
+
+	total := (len(links) + perPage - 1) / perPage
+	pages := make([]Page, 0, total)
+	for start := 0; start < len(links); start += perPage {
+		end := min(start+perPage, len(links))
+		pages = append(pages, Page{Number: len(pages) + 1, Total: total, Links: links[start:end]})
+	}
+	return pages
+}
+
+// PagePath is where page n of the index is served. The first page is the home page.
+func PagePath(n int) string {
+	if n == 1 {
+		return "/"
+	}
+	return "/page/" + strconv.Itoa(n)
+}
+
+// Prev returns the path of the page before this one, or "" on the first page.
+func (p Page) Prev() string {
+	if p.Number <= 1 {
+		return ""
+	}
+	return PagePath(p.Number - 1)
+}
+
+// Next returns the path of the page after this one, or "" on the last page.
+func (p Page) Next() string {
+	if p.Number >= p.Total {
+		return ""
+	}
+	return PagePath(p.Number + 1)
+}
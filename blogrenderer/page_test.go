@@ -0,0 +1,88 @@
+package blogrenderer_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/blogrenderer"
+)
+
+func TestPaginate(t *testing.T) {
+	links := blogrenderer.Links([]blogrenderer.Post{{Title: "a"}, {Title: "b"}, {Title: "c"}, {Title: "d"}, {Title: "e"}})
+
+	cases := []struct {
+		name    string
+		links   []blogrenderer.PostLink
+		perPage int
+		want    []blogrenderer.Page
+	}{
+		{
+			name:    "an exact multiple of the page size fills every page",
+			links:   links[:4],
+			perPage: 2,
+			want: []blogrenderer.Page{
+				{Number: 1, Total: 2, Links: links[0:2]},
+				{Number: 2, Total: 2, Links: links[2:4]},
+			},
+		},
+		{
+			name:    "the remainder goes on the last page",
+			links:   links,
+			perPage: 2,
+			want: []blogrenderer.Page{
+				{Number: 1, Total: 3, Links: links[0:2]},
+				{Number: 2, Total: 3, Links: links[2:4]},
+				{Number: 3, Total: 3, Links: links[4:5]},
+			},
+		},
+		{
+			name:    "fewer posts than fit on a page",
+			links:   links,
+			perPage: 10,
+			want:    []blogrenderer.Page{{Number: 1, Total: 1, Links: links}},
+		},
+		{
+			name:    "no page size puts everything on one page",
+			links:   links,
+			perPage: 0,
+			want:    []blogrenderer.Page{{Number: 1, Total: 1, Links: links}},
+		},
+		{
+			name:    "no posts is still a page",
+			links:   nil,
+			perPage: 2,
+			want:    []blogrenderer.Page{{Number: 1, Total: 1}},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			got := blogrenderer.Paginate(test.links, test.perPage)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPageNeighbours(t *testing.T) {
+	cases := []struct {
+		page       blogrenderer.Page
+		prev, next string
+	}{
+		{blogrenderer.Page{Number: 1, Total: 1}, "", ""},
+		{blogrenderer.Page{Number: 1, Total: 3}, "", "/page/2"},
+		{blogrenderer.Page{Number: 2, Total: 3}, "/", "/page/3"},
+		{blogrenderer.Page{Number: 3, Total: 3}, "/page/2", ""},
+	}
+
+	for _, test := range cases {
+		if got := test.page.Prev(); got != test.prev {
+			t.Errorf("page %d of %d got prev %q want %q", test.page.Number, test.page.Total, got, test.prev)
+		}
+		if got := test.page.Next(); got != test.next {
+			t.Errorf("page %d of %d got next %q want %q", test.page.Number, test.page.Total, got, test.next)
+		}
+	}
+}
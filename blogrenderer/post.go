@@ -1,14 +1,60 @@
 package blogrenderer
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Post is a representation of a post
 type Post struct {
 	Title, Description, Body string
 	Tags                     []string
+	Date                     time.Time
+	WordCount                int
+	ReadingTime              time.Duration
+
+	// Related are other posts someone who read this one may also like.
+	Related []PostLink
 }
 
-// SanitisedTitle returns the title of the post with spaces replaced by dashes for pleasant URLs
+// SanitisedTitle returns the title of the post with spaces replaced by dashes for pleasant URLs.
+// Characters which would end the path or escape part of it, such as / and ?, are left out
 func (p Post) SanitisedTitle() string {
-	return strings.ToLower(strings.Replace(p.Title, " ", "-", -1))
+	dashed := strings.ToLower(strings.Replace(p.Title, " ", "-", -1))
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\?#%`, r) {
+			return -1
+		}
+		return r
+	}, dashed)
+}
+
+// ReadingMinutes is the post's ReadingTime rounded up to whole minutes, so even a short post takes a minute to read
+func (p Post) ReadingMinutes() int {
+	return int((p.ReadingTime + time.Minute - 1) / time.Minute)
+}
+
+// PostLink is a post along with the unique slug it can be found at.
+type PostLink struct {
+	Post
+	Slug string
+}
+
+// Links gives every post a unique slug. Posts whose sanitised titles clash are numbered in order,
+// so the second "hello-world" becomes "hello-world-2".
+func Links(posts []Post) []PostLink {
+	links := make([]PostLink, 0, len(posts))
+	taken := map[string]bool{}
+
+	for _, p := range posts {
+		slug := p.SanitisedTitle()
+		for n := 2; taken[slug]; n++ {
+			slug = fmt.Sprintf("%s-%d", p.SanitisedTitle(), n)
+		}
+		taken[slug] = true
+		links = append(links, PostLink{Post: p, Slug: slug})
+	}
+
+	return links
 }
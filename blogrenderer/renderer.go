@@ -15,8 +15,7 @@ var (
 
 // PostRenderer renders data into HTML
 type PostRenderer struct {
-	templ    *template.Template
-	mdParser *parser.Parser
+	templ *template.Template
 }
 
 // NewPostRenderer creates a new PostRenderer
@@ -26,10 +25,7 @@ func NewPostRenderer() (*PostRenderer, error) {
 		return nil, err
 	}
 
-	extensions := parser.CommonExtensions | parser.AutoHeadingIDs
-	parser := parser.NewWithExtensions(extensions)
-
-	return &PostRenderer{templ: templ, mdParser: parser}, nil
+	return &PostRenderer{templ: templ}, nil
 }
 
 // Render renders post into HTML
@@ -39,7 +35,23 @@ func (r *PostRenderer) Render(w io.Writer, p Post) error {
 
 // RenderIndex creates an HTML index page given a collection of posts
 func (r *PostRenderer) RenderIndex(w io.Writer, posts []Post) error {
-	return r.templ.ExecuteTemplate(w, "index.gohtml", posts)
+	return r.RenderLinks(w, Links(posts))
+}
+
+// RenderLinks creates an HTML index page of links to posts, useful when the slugs were worked out from a larger collection
+func (r *PostRenderer) RenderLinks(w io.Writer, links []PostLink) error {
+	return r.RenderPage(w, Page{Number: 1, Total: 1, Links: links})
+}
+
+// RenderPage creates an HTML index page for one page of posts, with links to the pages either side of it
+func (r *PostRenderer) RenderPage(w io.Writer, page Page) error {
+	return r.templ.ExecuteTemplate(w, "index.gohtml", page)
+}
+
+// RenderArchive creates an HTML archive page listing links by the year and month they were
+// published, with how many posts there are in each
+func (r *PostRenderer) RenderArchive(w io.Writer, links []PostLink) error {
+	return r.templ.ExecuteTemplate(w, "archive.gohtml", ArchiveYears(links))
 }
 
 type postViewModel struct {
@@ -49,6 +61,12 @@ type postViewModel struct {
 
 func newPostVM(p Post, r *PostRenderer) postViewModel {
 	vm := postViewModel{Post: p}
-	vm.HTMLBody = template.HTML(markdown.ToHTML([]byte(p.Body), r.mdParser, nil))
+	vm.HTMLBody = template.HTML(markdown.ToHTML([]byte(p.Body), newMarkdownParser(), nil))
 	return vm
 }
+
+// a markdown parser keeps state from the last document it parsed, so we need a new one every time
+func newMarkdownParser() *parser.Parser {
+	extensions := parser.CommonExtensions | parser.AutoHeadingIDs
+	return parser.NewWithExtensions(extensions)
+}
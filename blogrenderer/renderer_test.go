@@ -2,10 +2,12 @@ package blogrenderer_test
 
 import (
 	"bytes"
+	"fmt"
 	approvals "github.com/approvals/go-approval-tests"
 	"github.com/quii/learn-go-with-tests/blogrenderer"
 	"io"
 	"testing"
+	"time"
 )
 
 func TestRender(t *testing.T) {
@@ -45,6 +47,60 @@ Welcome to my **amazing blog**. I am going to write about my family recipes, and
 
 		approvals.VerifyString(t, buf.String())
 	})
+
+	pages := func(n, perPage int) []blogrenderer.Page {
+		var posts []blogrenderer.Post
+		for i := 1; i <= n; i++ {
+			posts = append(posts, blogrenderer.Post{Title: fmt.Sprintf("Post %d", i)})
+		}
+		return blogrenderer.Paginate(blogrenderer.Links(posts), perPage)
+	}
+
+	t.Run("it renders a full last page when the posts fit the pages exactly", func(t *testing.T) {
+		buf := bytes.Buffer{}
+
+		if err := postRenderer.RenderPage(&buf, pages(4, 2)[1]); err != nil {
+			t.Fatal(err)
+		}
+
+		approvals.VerifyString(t, buf.String())
+	})
+
+	t.Run("it renders the remaining posts on the last page", func(t *testing.T) {
+		buf := bytes.Buffer{}
+
+		if err := postRenderer.RenderPage(&buf, pages(5, 2)[2]); err != nil {
+			t.Fatal(err)
+		}
+
+		approvals.VerifyString(t, buf.String())
+	})
+
+	t.Run("it links both ways from a page in the middle", func(t *testing.T) {
+		buf := bytes.Buffer{}
+
+		if err := postRenderer.RenderPage(&buf, pages(5, 2)[1]); err != nil {
+			t.Fatal(err)
+		}
+
+		approvals.VerifyString(t, buf.String())
+	})
+
+	t.Run("it renders an archive of posts by year and month with counts", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		posts := []blogrenderer.Post{
+			{Title: "Hello World", Date: time.Date(2023, time.March, 2, 0, 0, 0, 0, time.UTC)},
+			{Title: "Hello World", Date: time.Date(2023, time.March, 30, 0, 0, 0, 0, time.UTC)},
+			{Title: "Happy New Year", Date: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			{Title: "Drafts"},
+		}
+
+		if err := postRenderer.RenderArchive(&buf, blogrenderer.Links(posts)); err != nil {
+			t.Fatal(err)
+		}
+
+		approvals.VerifyString(t, buf.String())
+	})
 }
 
 func BenchmarkRender(b *testing.B) {
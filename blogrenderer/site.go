@@ -0,0 +1,83 @@
+package blogrenderer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SitemapNamespace is the XML namespace every sitemap.xml must declare.
+const SitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+const sitemapDateLayout = "2006-01-02"
+
+// Site describes where the blog is published, which sitemap.xml and robots.txt need for absolute URLs,
+// and how it is laid out
+type Site struct {
+	BaseURL string
+
+	// PostsPerPage is how many posts the index shows before continuing on another page. If it is 0
+	// every post is on the home page.
+	PostsPerPage int
+}
+
+// URL returns the absolute URL of path on the site
+func (s Site) URL(path string) string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// Sitemap is the urlset of a sitemap.xml
+type Sitemap struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL is a page listed in a sitemap, with the date it was last modified if we know it
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// WriteSitemap writes a sitemap.xml listing the home page and every post. A post's lastmod is its
+// date, and the home page's is the date of the newest post. Slugs are escaped, as a sitemap's
+// URLs must be.
+func WriteSitemap(w io.Writer, site Site, links []PostLink) error {
+	var newest time.Time
+	posts := make([]SitemapURL, 0, len(links))
+
+	for _, link := range links {
+		posts = append(posts, SitemapURL{Loc: site.URL("posts/" + url.PathEscape(link.Slug)), LastMod: lastMod(link.Date)})
+		if link.Date.After(newest) {
+			newest = link.Date
+		}
+	}
+
+	sitemap := Sitemap{
+		XMLNS: SitemapNamespace,
+		URLs:  append([]SitemapURL{{Loc: site.URL("/"), LastMod: lastMod(newest)}}, posts...),
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(sitemap)
+}
+
+// WriteRobots writes a robots.txt which lets crawlers see everything and tells them where the sitemap is
+func WriteRobots(w io.Writer, site Site) error {
+	_, err := fmt.Fprintf(w, "User-agent: *\nAllow: /\n\nSitemap: %s\n", site.URL("sitemap.xml"))
+	return err
+}
+
+func lastMod(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(sitemapDateLayout)
+}
@@ -0,0 +1,95 @@
+package blogrenderer_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/blogrenderer"
+)
+
+func TestWriteSitemap(t *testing.T) {
+	site := blogrenderer.Site{BaseURL: "https://example.com/blog/"}
+
+	t.Run("lists the home page and every post with their dates", func(t *testing.T) {
+		posts := []blogrenderer.Post{
+			{Title: "Hello World", Date: time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)},
+			{Title: "Hello World", Date: time.Date(2024, time.May, 2, 0, 0, 0, 0, time.UTC)},
+			{Title: "Undated"},
+		}
+
+		got := writeSitemap(t, site, blogrenderer.Links(posts))
+
+		want := []blogrenderer.SitemapURL{
+			{Loc: "https://example.com/blog/", LastMod: "2024-05-02"},
+			{Loc: "https://example.com/blog/posts/hello-world", LastMod: "2024-03-17"},
+			{Loc: "https://example.com/blog/posts/hello-world-2", LastMod: "2024-05-02"},
+			{Loc: "https://example.com/blog/posts/undated"},
+		}
+		if !reflect.DeepEqual(got.URLs, want) {
+			t.Errorf("got urls %+v want %+v", got.URLs, want)
+		}
+	})
+
+	t.Run("escapes slugs, leaving out characters which can't be in one", func(t *testing.T) {
+		posts := []blogrenderer.Post{{Title: "Why? Because/Reasons"}, {Title: "Café Society"}}
+
+		got := writeSitemap(t, site, blogrenderer.Links(posts))
+
+		want := []blogrenderer.SitemapURL{
+			{Loc: "https://example.com/blog/"},
+			{Loc: "https://example.com/blog/posts/why-becausereasons"},
+			{Loc: "https://example.com/blog/posts/caf%C3%A9-society"},
+		}
+		if !reflect.DeepEqual(got.URLs, want) {
+			t.Errorf("got urls %+v want %+v", got.URLs, want)
+		}
+	})
+
+	t.Run("declares the sitemap namespace", func(t *testing.T) {
+		got := writeSitemap(t, site, nil)
+
+		if got.XMLName.Space != blogrenderer.SitemapNamespace {
+			t.Errorf("got namespace %q want %q", got.XMLName.Space, blogrenderer.SitemapNamespace)
+		}
+		if len(got.URLs) != 1 || got.URLs[0].LastMod != "" {
+			t.Errorf("expected only an undated home page, got %+v", got.URLs)
+		}
+	})
+}
+
+func TestWriteRobots(t *testing.T) {
+	buf := bytes.Buffer{}
+
+	if err := blogrenderer.WriteRobots(&buf, blogrenderer.Site{BaseURL: "https://example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"User-agent: *", "Allow: /", "Sitemap: https://example.com/sitemap.xml"} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("expected robots.txt to contain %q, got\n%s", line, buf.String())
+		}
+	}
+}
+
+func writeSitemap(t testing.TB, site blogrenderer.Site, links []blogrenderer.PostLink) blogrenderer.Sitemap {
+	t.Helper()
+	buf := bytes.Buffer{}
+
+	if err := blogrenderer.WriteSitemap(&buf, site, links); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("expected the sitemap to start with the XML header, got %q", buf.String())
+	}
+
+	var sitemap blogrenderer.Sitemap
+	if err := xml.Unmarshal(buf.Bytes(), &sitemap); err != nil {
+		t.Fatalf("sitemap is not valid XML, %v\n%s", err, buf.String())
+	}
+	return sitemap
+}
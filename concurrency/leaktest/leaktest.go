@@ -0,0 +1,95 @@
+// Package leaktest fails tests which leave goroutines running once they have finished. A
+// goroutine that is never going to return holds on to everything it refers to for as long as the
+// program runs, so a leak in a server handler or a race that nobody waits for adds up.
+package leaktest
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// TB is the part of testing.TB that Check needs.
+type TB interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...any)
+}
+
+// settleTime is how long goroutines get to finish once a test has. Code which has been told to
+// stop often takes a moment to get around to it.
+var settleTime = time.Second
+
+// alwaysAllowed are goroutines which come and go without being leaks: other tests, which may be
+// running in parallel, and the one that delivers signals once anything has asked for them.
+var alwaysAllowed = []string{
+	"created by testing.(*T).Run",
+	"os/signal.loop",
+}
+
+// Check takes a note of the goroutines that are running and, once t has finished, fails it if
+// any new ones are still running. Goroutines whose stacks mention any of allow, such as
+// "net/http.(*persistConn)" for a client's idle connections, aren't counted as leaks.
+//
+// Call it at the start of a test; the check runs after the test's deferred calls, so servers
+// closed with defer have been closed by then.
+func Check(t TB, allow ...string) {
+	t.Helper()
+	before := goroutines()
+
+	t.Cleanup(func() {
+		var leaked []string
+		deadline := time.Now().Add(settleTime)
+		for {
+			leaked = leaked[:0]
+			for id, stack := range goroutines() {
+				if _, existed := before[id]; !existed && !allowed(stack, allow) {
+					leaked = append(leaked, stack)
+				}
+			}
+			if len(leaked) == 0 || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		if len(leaked) > 0 {
+			t.Errorf("%d goroutine(s) leaked:\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+		}
+	})
+}
+
+func allowed(stack string, allow []string) bool {
+	for _, list := range [][]string{allow, alwaysAllowed} {
+		for _, a := range list {
+			if strings.Contains(stack, a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goroutines returns the stack of every goroutine other than the caller's, by goroutine ID.
+func goroutines() map[string]string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	stacks := map[string]string{}
+	// the first stack is always the caller's
+	for _, stack := range bytes.Split(buf, []byte("\n\n"))[1:] {
+		header, _, _ := strings.Cut(string(stack), "\n")
+		// headers look like "goroutine 7 [chan receive]:"
+		id, _, _ := strings.Cut(strings.TrimPrefix(header, "goroutine "), " ")
+		stacks[id] = string(stack)
+	}
+	return stacks
+}
@@ -0,0 +1,105 @@
+package leaktest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// spyTB runs its cleanups when asked, rather than when a test finishes, and records failures.
+type spyTB struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (s *spyTB) Helper()                   {}
+func (s *spyTB) Cleanup(f func())          { s.cleanups = append(s.cleanups, f) }
+func (s *spyTB) Errorf(f string, a ...any) { s.errors = append(s.errors, fmt.Sprintf(f, a...)) }
+
+func (s *spyTB) finish() {
+	for i := len(s.cleanups) - 1; i >= 0; i-- {
+		s.cleanups[i]()
+	}
+}
+
+func TestCheck(t *testing.T) {
+	settleTime = 50 * time.Millisecond
+	t.Cleanup(func() { settleTime = time.Second })
+
+	t.Run("passes when every goroutine has finished", func(t *testing.T) {
+		spy := &spyTB{}
+		Check(spy)
+
+		done := make(chan struct{})
+		go func() { close(done) }()
+		<-done
+
+		spy.finish()
+
+		if len(spy.errors) > 0 {
+			t.Errorf("did not expect a leak but got %v", spy.errors)
+		}
+	})
+
+	t.Run("waits for goroutines which are finishing", func(t *testing.T) {
+		spy := &spyTB{}
+		Check(spy)
+
+		go time.Sleep(10 * time.Millisecond)
+
+		spy.finish()
+
+		if len(spy.errors) > 0 {
+			t.Errorf("did not expect a leak but got %v", spy.errors)
+		}
+	})
+
+	t.Run("reports goroutines which are still running", func(t *testing.T) {
+		spy := &spyTB{}
+		Check(spy)
+
+		stuck := make(chan struct{})
+		defer close(stuck)
+		go blockUntil(stuck)
+
+		spy.finish()
+
+		if len(spy.errors) != 1 || !strings.Contains(spy.errors[0], "leaktest.blockUntil") {
+			t.Errorf("expected blockUntil to be reported as a leak, got %v", spy.errors)
+		}
+	})
+
+	t.Run("ignores goroutines that were already running", func(t *testing.T) {
+		stuck := make(chan struct{})
+		defer close(stuck)
+		go blockUntil(stuck)
+
+		spy := &spyTB{}
+		Check(spy)
+		spy.finish()
+
+		if len(spy.errors) > 0 {
+			t.Errorf("did not expect a leak but got %v", spy.errors)
+		}
+	})
+
+	t.Run("allows goroutines it is told to", func(t *testing.T) {
+		spy := &spyTB{}
+		Check(spy, "leaktest.blockUntil")
+
+		stuck := make(chan struct{})
+		defer close(stuck)
+		go blockUntil(stuck)
+
+		spy.finish()
+
+		if len(spy.errors) > 0 {
+			t.Errorf("did not expect a leak but got %v", spy.errors)
+		}
+	})
+}
+
+func blockUntil(done chan struct{}) {
+	<-done
+}
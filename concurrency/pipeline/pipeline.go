@@ -0,0 +1,106 @@
+// Package pipeline checks websites in stages connected by channels: urls are generated, checked,
+// enriched with extra information and finally collected. Each stage fans out to a bounded number of
+// goroutines, and every stage stops early when its context is cancelled.
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Result is what the pipeline has found out about a url.
+type Result struct {
+	URL     string
+	Healthy bool
+	Details string
+}
+
+// Checker reports whether a url is healthy.
+type Checker func(ctx context.Context, url string) bool
+
+// Enricher adds extra information to a Result.
+type Enricher func(ctx context.Context, r Result) Result
+
+// Run takes urls through every stage of the pipeline using up to workers goroutines per stage.
+// Results are returned in the order they finished, not the order of urls.
+func Run(ctx context.Context, urls []string, checker Checker, enricher Enricher, workers int) ([]Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	generated := Generate(ctx, urls...)
+	checked := Check(ctx, generated, checker, workers)
+	enriched := Enrich(ctx, checked, enricher, workers)
+	return Collect(ctx, enriched)
+}
+
+// Generate sends each url down the returned channel, closing it when done or when ctx is cancelled.
+func Generate(ctx context.Context, urls ...string) <-chan string {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for _, url := range urls {
+			select {
+			case out <- url:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Check checks every url received from in using up to workers goroutines.
+func Check(ctx context.Context, in <-chan string, checker Checker, workers int) <-chan Result {
+	return fanOut(ctx, in, workers, func(ctx context.Context, url string) Result {
+		return Result{URL: url, Healthy: checker(ctx, url)}
+	})
+}
+
+// Enrich enriches every Result received from in using up to workers goroutines.
+func Enrich(ctx context.Context, in <-chan Result, enricher Enricher, workers int) <-chan Result {
+	return fanOut(ctx, in, workers, enricher)
+}
+
+// Collect gathers every Result from in. If ctx is cancelled first it returns what it has so far along with ctx's error.
+func Collect(ctx context.Context, in <-chan Result) ([]Result, error) {
+	var results []Result
+	for {
+		select {
+		case r, ok := <-in:
+			if !ok {
+				return results, nil
+			}
+			results = append(results, r)
+		case <-ctx.Done():
+			return results, ctx.Err()
+		}
+	}
+}
+
+// fanOut runs process over everything from in with up to workers goroutines, closing the returned
+// channel once they have all finished.
+func fanOut[In, Out any](ctx context.Context, in <-chan In, workers int, process func(context.Context, In) Out) <-chan Out {
+	out := make(chan Out)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range in {
+				select {
+				case out <- process(ctx, item):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
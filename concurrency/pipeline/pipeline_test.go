@@ -0,0 +1,129 @@
+package pipeline_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/concurrency/pipeline"
+)
+
+func healthyUnlessBroken(_ context.Context, url string) bool {
+	return !strings.Contains(url, "broken")
+}
+
+func addHost(_ context.Context, r pipeline.Result) pipeline.Result {
+	r.Details = strings.TrimPrefix(r.URL, "http://")
+	return r
+}
+
+func TestRun(t *testing.T) {
+	t.Run("checks and enriches every url", func(t *testing.T) {
+		urls := []string{"http://google.com", "http://broken.example", "http://quii.dev"}
+
+		got, err := pipeline.Run(context.Background(), urls, healthyUnlessBroken, addHost, 2)
+		assertNoError(t, err)
+
+		sort.Slice(got, func(i, j int) bool { return got[i].URL < got[j].URL })
+		want := []pipeline.Result{
+			{URL: "http://broken.example", Healthy: false, Details: "broken.example"},
+			{URL: "http://google.com", Healthy: true, Details: "google.com"},
+			{URL: "http://quii.dev", Healthy: true, Details: "quii.dev"},
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("results come out as they finish, so slow urls don't hold up fast ones", func(t *testing.T) {
+		slowFirst := func(_ context.Context, url string) bool {
+			if url == "slow" {
+				time.Sleep(50 * time.Millisecond)
+			}
+			return true
+		}
+
+		got, err := pipeline.Run(context.Background(), []string{"slow", "fast"}, slowFirst, addHost, 2)
+		assertNoError(t, err)
+
+		if len(got) != 2 || got[0].URL != "fast" {
+			t.Errorf("expected fast to finish before slow, got %v", got)
+		}
+	})
+
+	t.Run("stages work concurrently up to the number of workers", func(t *testing.T) {
+		var running, maxRunning int32
+		checker := func(_ context.Context, _ string) bool {
+			now := atomic.AddInt32(&running, 1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if now <= max || atomic.CompareAndSwapInt32(&maxRunning, max, now) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return true
+		}
+
+		urls := make([]string, 20)
+		for i := range urls {
+			urls[i] = fmt.Sprintf("http://%d.example", i)
+		}
+
+		_, err := pipeline.Run(context.Background(), urls, checker, addHost, 4)
+		assertNoError(t, err)
+
+		if got := atomic.LoadInt32(&maxRunning); got < 2 || got > 4 {
+			t.Errorf("expected between 2 and 4 checks to run at once, got %d", got)
+		}
+	})
+
+	t.Run("cancelling the context stops every stage", func(t *testing.T) {
+		goroutinesBefore := runtime.NumGoroutine()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		checker := func(_ context.Context, url string) bool {
+			if url == "http://2.example" {
+				cancel()
+			}
+			return true
+		}
+
+		urls := make([]string, 100)
+		for i := range urls {
+			urls[i] = fmt.Sprintf("http://%d.example", i)
+		}
+
+		got, err := pipeline.Run(ctx, urls, checker, addHost, 2)
+
+		if err != context.Canceled {
+			t.Errorf("got error %v want %v", err, context.Canceled)
+		}
+		if len(got) == len(urls) {
+			t.Error("expected the pipeline to stop before processing every url")
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > goroutinesBefore && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		if after := runtime.NumGoroutine(); after > goroutinesBefore {
+			t.Errorf("pipeline leaked goroutines, had %d before and %d after", goroutinesBefore, after)
+		}
+	})
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("didn't expect an error but got one, %v", err)
+	}
+}
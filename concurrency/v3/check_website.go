@@ -8,6 +8,7 @@ func CheckWebsite(url string) bool {
 	if err != nil {
 		return false
 	}
+	defer response.Body.Close()
 
 	return response.StatusCode == http.StatusOK
 }
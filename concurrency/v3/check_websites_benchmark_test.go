@@ -20,3 +20,25 @@ func BenchmarkCheckWebsites(b *testing.B) {
 		CheckWebsites(slowStubWebsiteChecker, urls)
 	}
 }
+
+func BenchmarkCheckWebsitesWithWorkers(b *testing.B) {
+	urls := make([]string, 100)
+	for i := 0; i < len(urls); i++ {
+		urls[i] = "a url"
+	}
+
+	for i := 0; i < b.N; i++ {
+		CheckWebsitesWithWorkers(slowStubWebsiteChecker, urls, 10)
+	}
+}
+
+func BenchmarkCheckWebsitesWithCache(b *testing.B) {
+	urls := make([]string, 100)
+	for i := 0; i < len(urls); i++ {
+		urls[i] = "a url"
+	}
+
+	for i := 0; i < b.N; i++ {
+		CheckWebsites(NewCachingChecker(slowStubWebsiteChecker, 0).Check, urls)
+	}
+}
@@ -0,0 +1,75 @@
+package concurrency
+
+import (
+	"sync"
+	"time"
+)
+
+// CachingChecker wraps a WebsiteChecker so each url is only checked once, however many times it
+// turns up. Checks of a url which is already being checked wait for that check to finish and
+// share its result, rather than starting another.
+type CachingChecker struct {
+	check WebsiteChecker
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu       sync.Mutex
+	checked  map[string]checkedURL
+	checking map[string]*checkingURL
+}
+
+type checkedURL struct {
+	up bool
+	at time.Time
+}
+
+type checkingURL struct {
+	done chan struct{}
+	up   bool
+}
+
+// NewCachingChecker remembers the result of checking a url with wc for ttl, so it can be shared
+// between batches of CheckWebsites. A ttl of 0 remembers results for as long as the
+// CachingChecker is used, which suits checking a single batch.
+func NewCachingChecker(wc WebsiteChecker, ttl time.Duration) *CachingChecker {
+	return &CachingChecker{
+		check:    wc,
+		ttl:      ttl,
+		now:      time.Now,
+		checked:  make(map[string]checkedURL),
+		checking: make(map[string]*checkingURL),
+	}
+}
+
+// Check is a WebsiteChecker, so it can be passed to CheckWebsites and CheckWebsitesWithWorkers.
+func (c *CachingChecker) Check(url string) bool {
+	c.mu.Lock()
+
+	if result, ok := c.checked[url]; ok {
+		if c.ttl == 0 || c.now().Sub(result.at) < c.ttl {
+			c.mu.Unlock()
+			return result.up
+		}
+		delete(c.checked, url)
+	}
+
+	if inFlight, ok := c.checking[url]; ok {
+		c.mu.Unlock()
+		<-inFlight.done
+		return inFlight.up
+	}
+
+	inFlight := &checkingURL{done: make(chan struct{})}
+	c.checking[url] = inFlight
+	c.mu.Unlock()
+
+	inFlight.up = c.check(url)
+
+	c.mu.Lock()
+	delete(c.checking, url)
+	c.checked[url] = checkedURL{inFlight.up, c.now()}
+	c.mu.Unlock()
+
+	close(inFlight.done)
+	return inFlight.up
+}
@@ -1,8 +1,14 @@
 package concurrency
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/concurrency/leaktest"
 )
 
 func mockWebsiteChecker(url string) bool {
@@ -13,6 +19,8 @@ func mockWebsiteChecker(url string) bool {
 }
 
 func TestCheckWebsites(t *testing.T) {
+	leaktest.Check(t)
+
 	websites := []string{
 		"http://google.com",
 		"http://blog.gypsydave5.com",
@@ -31,3 +39,151 @@ func TestCheckWebsites(t *testing.T) {
 		t.Fatalf("wanted %v, got %v", want, got)
 	}
 }
+
+func TestCheckWebsitesWithWorkers(t *testing.T) {
+	leaktest.Check(t)
+
+	websites := []string{
+		"http://google.com",
+		"http://blog.gypsydave5.com",
+		"waat://furhurterwe.geds",
+	}
+
+	want := map[string]bool{
+		"http://google.com":          true,
+		"http://blog.gypsydave5.com": true,
+		"waat://furhurterwe.geds":    false,
+	}
+
+	for _, workers := range []int{2, 0} {
+		got := CheckWebsitesWithWorkers(mockWebsiteChecker, websites, workers)
+
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("wanted %v, got %v with %d workers", want, got, workers)
+		}
+	}
+}
+
+func TestCheckWebsite(t *testing.T) {
+	leaktest.Check(t)
+
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	if !CheckWebsite(up.URL) {
+		t.Errorf("expected %s to be up", up.URL)
+	}
+	if CheckWebsite(down.URL) {
+		t.Errorf("expected %s to be down", down.URL)
+	}
+}
+
+// countingChecker is a WebsiteChecker which counts how many times each url is checked.
+type countingChecker struct {
+	mu      sync.Mutex
+	checks  map[string]int
+	started chan struct{}
+	wait    chan struct{}
+}
+
+func (c *countingChecker) check(url string) bool {
+	c.mu.Lock()
+	if c.checks == nil {
+		c.checks = make(map[string]int)
+	}
+	c.checks[url]++
+	c.mu.Unlock()
+
+	if c.started != nil {
+		c.started <- struct{}{}
+	}
+	if c.wait != nil {
+		<-c.wait
+	}
+	return mockWebsiteChecker(url)
+}
+
+func (c *countingChecker) assertChecks(t testing.TB, want map[string]int) {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !reflect.DeepEqual(c.checks, want) {
+		t.Errorf("got checks %v want %v", c.checks, want)
+	}
+}
+
+func TestCachingChecker(t *testing.T) {
+	t.Run("checks a url repeated in a batch once", func(t *testing.T) {
+		leaktest.Check(t)
+
+		websites := []string{
+			"http://google.com",
+			"waat://furhurterwe.geds",
+			"http://google.com",
+			"http://google.com",
+			"waat://furhurterwe.geds",
+		}
+		checker := &countingChecker{}
+
+		got := CheckWebsitesWithWorkers(NewCachingChecker(checker.check, 0).Check, websites, 1)
+
+		want := map[string]bool{
+			"http://google.com":       true,
+			"waat://furhurterwe.geds": false,
+		}
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("wanted %v, got %v", want, got)
+		}
+		checker.assertChecks(t, map[string]int{"http://google.com": 1, "waat://furhurterwe.geds": 1})
+	})
+
+	t.Run("checks of a url already being checked wait for its result", func(t *testing.T) {
+		leaktest.Check(t)
+
+		checker := &countingChecker{started: make(chan struct{}), wait: make(chan struct{})}
+		cache := NewCachingChecker(checker.check, 0)
+
+		const checks = 5
+		results := make(chan bool, checks)
+		check := func() {
+			results <- cache.Check("http://google.com")
+		}
+
+		go check()
+		<-checker.started
+		for range checks - 1 {
+			go check()
+		}
+		close(checker.wait)
+
+		for range checks {
+			if !<-results {
+				t.Error("expected every check to find the url up")
+			}
+		}
+		checker.assertChecks(t, map[string]int{"http://google.com": 1})
+	})
+
+	t.Run("checks a url again once its result is older than the ttl", func(t *testing.T) {
+		leaktest.Check(t)
+
+		now := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+		checker := &countingChecker{}
+		cache := NewCachingChecker(checker.check, time.Minute)
+		cache.now = func() time.Time { return now }
+
+		CheckWebsites(cache.Check, []string{"http://google.com"})
+		now = now.Add(59 * time.Second)
+		CheckWebsites(cache.Check, []string{"http://google.com"})
+		checker.assertChecks(t, map[string]int{"http://google.com": 1})
+
+		now = now.Add(time.Second)
+		CheckWebsites(cache.Check, []string{"http://google.com"})
+		checker.assertChecks(t, map[string]int{"http://google.com": 2})
+	})
+}
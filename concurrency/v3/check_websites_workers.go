@@ -0,0 +1,29 @@
+package concurrency
+
+import (
+	"sync"
+
+	"github.com/quii/learn-go-with-tests/sync/pool"
+)
+
+// CheckWebsitesWithWorkers is like CheckWebsites but checks at most workers urls at once,
+// which is kinder to the network when there are a lot of urls. Fewer than 1 worker is treated as 1.
+func CheckWebsitesWithWorkers(wc WebsiteChecker, urls []string, workers int) map[string]bool {
+	workers = max(workers, 1)
+	results := make(map[string]bool)
+	var mu sync.Mutex
+
+	p := pool.New(workers, len(urls))
+	for _, url := range urls {
+		p.Submit(func() {
+			ok := wc(url)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[url] = ok
+		})
+	}
+	p.Stop()
+
+	return results
+}
@@ -1,15 +1,22 @@
 package context2
 
 import (
+	"sync"
 	"testing"
 	"time"
 )
 
 // SpyStore allows you to simulate a store and see how its used.
 type SpyStore struct {
-	response  string
-	cancelled bool
-	t         *testing.T
+	response string
+
+	once      sync.Once
+	cancelled chan struct{}
+}
+
+// NewSpyStore returns a SpyStore that will respond with response.
+func NewSpyStore(response string) *SpyStore {
+	return &SpyStore{response: response, cancelled: make(chan struct{})}
 }
 
 // Fetch returns response after a short delay.
@@ -20,19 +27,34 @@ func (s *SpyStore) Fetch() string {
 
 // Cancel will record the call.
 func (s *SpyStore) Cancel() {
-	s.cancelled = true
+	s.once.Do(func() { close(s.cancelled) })
 }
 
-func (s *SpyStore) assertWasCancelled() {
-	s.t.Helper()
-	if !s.cancelled {
-		s.t.Error("store was not told to cancel")
+// Cancelled is closed once Cancel has been called.
+func (s *SpyStore) Cancelled() <-chan struct{} {
+	return s.cancelled
+}
+
+// cancelWait is how long the assertions give a store to be cancelled, as cancellation
+// can happen on another goroutine.
+const cancelWait = 500 * time.Millisecond
+
+// AssertWasCancelled fails the test if Cancel isn't called on store soon.
+func AssertWasCancelled(t testing.TB, store *SpyStore) {
+	t.Helper()
+	select {
+	case <-store.Cancelled():
+	case <-time.After(cancelWait):
+		t.Error("store was not told to cancel")
 	}
 }
 
-func (s *SpyStore) assertWasNotCancelled() {
-	s.t.Helper()
-	if s.cancelled {
-		s.t.Error("store was told to cancel")
+// AssertWasNotCancelled fails the test if Cancel has been called on store.
+func AssertWasNotCancelled(t testing.TB, store *SpyStore) {
+	t.Helper()
+	select {
+	case <-store.Cancelled():
+		t.Error("store was told to cancel")
+	default:
 	}
 }
@@ -2,6 +2,7 @@ package context3
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -44,3 +45,63 @@ func TestServer(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTPStore(t *testing.T) {
+	t.Run("fetches the body from the url", func(t *testing.T) {
+		backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "hello, world")
+		}))
+		defer backend.Close()
+
+		store := NewHTTPStore(backend.Client(), backend.URL)
+
+		got, err := store.Fetch(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "hello, world" {
+			t.Errorf(`got "%s", want "%s"`, got, "hello, world")
+		}
+	})
+
+	t.Run("cancelling one request to Server only cancels its request to the backend", func(t *testing.T) {
+		arrived := make(chan struct{}, 2)
+		cancelled := make(chan struct{}, 2)
+		backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			arrived <- struct{}{}
+			select {
+			case <-r.Context().Done():
+				cancelled <- struct{}{}
+			case <-time.After(50 * time.Millisecond):
+				fmt.Fprint(w, "hello, world")
+			}
+		}))
+		defer backend.Close()
+
+		svr := Server(NewHTTPStore(backend.Client(), backend.URL))
+
+		patient := httptest.NewRecorder()
+		done := make(chan struct{})
+		go func() {
+			svr.ServeHTTP(patient, httptest.NewRequest(http.MethodGet, "/", nil))
+			close(done)
+		}()
+		<-arrived
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		cancellingCtx, cancel := context.WithCancel(request.Context())
+		time.AfterFunc(5*time.Millisecond, cancel)
+		svr.ServeHTTP(httptest.NewRecorder(), request.WithContext(cancellingCtx))
+
+		select {
+		case <-cancelled:
+		case <-time.After(time.Second):
+			t.Error("backend request was not cancelled")
+		}
+
+		<-done
+		if patient.Body.String() != "hello, world" {
+			t.Errorf(`got "%s" for the request which wasn't cancelled, want "hello, world"`, patient.Body.String())
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package context3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// HTTPStore fetches data from a url. Each fetch is made with the context it is given, so when
+// the request to Server is cancelled the request to the url is too, and the server at the other
+// end can stop working on it. Other fetches carry on.
+type HTTPStore struct {
+	client *http.Client
+	url    string
+}
+
+// NewHTTPStore returns a store that GETs url using client.
+func NewHTTPStore(client *http.Client, url string) *HTTPStore {
+	return &HTTPStore{client: client, url: url}
+}
+
+// Fetch returns the body of the response, or an error if the request failed or ctx was cancelled.
+func (h *HTTPStore) Fetch(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("problem reading response from %s, %w", h.url, err)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,166 @@
+// Package assert is the chapter's assertion helpers grown up: generic, usable from any testing.TB,
+// and with failure messages that say what went wrong.
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Equal fails the test if got and want are not equal.
+func Equal[T comparable](t testing.TB, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// NotEqual fails the test if got and want are equal.
+func NotEqual[T comparable](t testing.TB, got, want T) {
+	t.Helper()
+	if got == want {
+		t.Errorf("didn't want %v", got)
+	}
+}
+
+// True fails the test if got is false.
+func True(t testing.TB, got bool) {
+	t.Helper()
+	if !got {
+		t.Errorf("got %v, want true", got)
+	}
+}
+
+// False fails the test if got is true.
+func False(t testing.TB, got bool) {
+	t.Helper()
+	if got {
+		t.Errorf("got %v, want false", got)
+	}
+}
+
+// NoError stops the test if err is not nil, as there's usually no point carrying on.
+func NoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("didn't want an error but got %v", err)
+	}
+}
+
+// ErrorIs fails the test unless got is, or wraps, want.
+func ErrorIs(t testing.TB, got, want error) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("wanted error %v but didn't get one", want)
+		return
+	}
+	if !errors.Is(got, want) {
+		t.Errorf("got error %v, want %v", got, want)
+	}
+}
+
+// DeepEqual fails the test if got and want are not reflect.DeepEqual, listing where they differ
+// so you don't have to spot it in two long lines of %v.
+func DeepEqual[T any](t testing.TB, got, want T) {
+	t.Helper()
+	if reflect.DeepEqual(got, want) {
+		return
+	}
+
+	differences := Diff(got, want)
+	if len(differences) == 0 {
+		// e.g. NaNs, or functions, which are never deeply equal
+		t.Errorf("got %+v, want %+v", got, want)
+		return
+	}
+	t.Errorf("got %+v, want %+v\n%s", got, want, strings.Join(differences, "\n"))
+}
+
+// Diff lists the places where got and want differ, one per line, like `.Tags[1]: got "go", want "tdd"`.
+func Diff(got, want any) []string {
+	return diff("", reflect.ValueOf(got), reflect.ValueOf(want))
+}
+
+func diff(path string, got, want reflect.Value) []string {
+	if !got.IsValid() || !want.IsValid() || got.Type() != want.Type() {
+		return leafDiff(path, got, want)
+	}
+
+	switch got.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if got.IsNil() || want.IsNil() {
+			return leafDiff(path, got, want)
+		}
+		return diff(path, got.Elem(), want.Elem())
+
+	case reflect.Struct:
+		var differences []string
+		for i := 0; i < got.NumField(); i++ {
+			if !got.Type().Field(i).IsExported() {
+				continue
+			}
+			name := path + "." + got.Type().Field(i).Name
+			differences = append(differences, diff(name, got.Field(i), want.Field(i))...)
+		}
+		return differences
+
+	case reflect.Slice, reflect.Array:
+		var differences []string
+		for i := 0; i < max(got.Len(), want.Len()); i++ {
+			name := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= got.Len():
+				differences = append(differences, fmt.Sprintf("%s: missing, want %#v", name, want.Index(i)))
+			case i >= want.Len():
+				differences = append(differences, fmt.Sprintf("%s: got %#v, didn't want it", name, got.Index(i)))
+			default:
+				differences = append(differences, diff(name, got.Index(i), want.Index(i))...)
+			}
+		}
+		return differences
+
+	case reflect.Map:
+		var differences []string
+		for _, key := range want.MapKeys() {
+			name := fmt.Sprintf("%s[%#v]", path, key)
+			if gotValue := got.MapIndex(key); gotValue.IsValid() {
+				differences = append(differences, diff(name, gotValue, want.MapIndex(key))...)
+			} else {
+				differences = append(differences, fmt.Sprintf("%s: missing, want %#v", name, want.MapIndex(key)))
+			}
+		}
+		for _, key := range got.MapKeys() {
+			if !want.MapIndex(key).IsValid() {
+				differences = append(differences, fmt.Sprintf("%s[%#v]: got %#v, didn't want it", path, key, got.MapIndex(key)))
+			}
+		}
+		slices.Sort(differences) // map order is random, but failure messages shouldn't be
+		return differences
+	}
+
+	return leafDiff(path, got, want)
+}
+
+func leafDiff(path string, got, want reflect.Value) []string {
+	if got.IsValid() && want.IsValid() && got.CanInterface() && want.CanInterface() && reflect.DeepEqual(got.Interface(), want.Interface()) {
+		return nil
+	}
+	if path == "" {
+		path = "value"
+	}
+	return []string{fmt.Sprintf("%s: got %s, want %s", path, describe(got), describe(want))}
+}
+
+func describe(v reflect.Value) string {
+	if !v.IsValid() {
+		return "nil"
+	}
+	if !v.CanInterface() {
+		return v.String()
+	}
+	return fmt.Sprintf("%#v", v.Interface())
+}
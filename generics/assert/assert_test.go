@@ -0,0 +1,142 @@
+package assert_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/generics/assert"
+)
+
+// SpyTB records what an assertion reported instead of failing the real test.
+type SpyTB struct {
+	testing.TB
+	Errors []string
+	Fatals []string
+}
+
+func (s *SpyTB) Helper() {}
+
+func (s *SpyTB) Errorf(format string, args ...any) {
+	s.Errors = append(s.Errors, fmt.Sprintf(format, args...))
+}
+
+func (s *SpyTB) Fatalf(format string, args ...any) {
+	s.Fatals = append(s.Fatals, fmt.Sprintf(format, args...))
+}
+
+type person struct {
+	Name string
+	Tags []string
+}
+
+func TestAssertions(t *testing.T) {
+	errNotFound := errors.New("not found")
+
+	cases := []struct {
+		name       string
+		assertion  func(t testing.TB)
+		wantErrors []string
+		wantFatals []string
+	}{
+		{name: "equal passes", assertion: func(t testing.TB) { assert.Equal(t, 1, 1) }},
+		{
+			name:       "equal fails",
+			assertion:  func(t testing.TB) { assert.Equal(t, "hello", "Grace") },
+			wantErrors: []string{"got hello, want Grace"},
+		},
+		{name: "not equal passes", assertion: func(t testing.TB) { assert.NotEqual(t, 1, 2) }},
+		{
+			name:       "not equal fails",
+			assertion:  func(t testing.TB) { assert.NotEqual(t, 1, 1) },
+			wantErrors: []string{"didn't want 1"},
+		},
+		{name: "true passes", assertion: func(t testing.TB) { assert.True(t, true) }},
+		{
+			name:       "true fails",
+			assertion:  func(t testing.TB) { assert.True(t, false) },
+			wantErrors: []string{"got false, want true"},
+		},
+		{
+			name:       "false fails",
+			assertion:  func(t testing.TB) { assert.False(t, true) },
+			wantErrors: []string{"got true, want false"},
+		},
+		{name: "no error passes", assertion: func(t testing.TB) { assert.NoError(t, nil) }},
+		{
+			name:       "no error stops the test",
+			assertion:  func(t testing.TB) { assert.NoError(t, errNotFound) },
+			wantFatals: []string{"didn't want an error but got not found"},
+		},
+		{name: "error is passes for wrapped errors", assertion: func(t testing.TB) { assert.ErrorIs(t, fmt.Errorf("looking up: %w", errNotFound), errNotFound) }},
+		{
+			name:       "error is fails for a different error",
+			assertion:  func(t testing.TB) { assert.ErrorIs(t, errors.New("boom"), errNotFound) },
+			wantErrors: []string{"got error boom, want not found"},
+		},
+		{
+			name:       "error is fails without an error",
+			assertion:  func(t testing.TB) { assert.ErrorIs(t, nil, errNotFound) },
+			wantErrors: []string{"wanted error not found but didn't get one"},
+		},
+		{name: "deep equal passes", assertion: func(t testing.TB) { assert.DeepEqual(t, []int{1, 2}, []int{1, 2}) }},
+		{
+			name: "deep equal lists the differences",
+			assertion: func(t testing.TB) {
+				assert.DeepEqual(t, person{Name: "Chris", Tags: []string{"go", "tdd"}}, person{Name: "Cleo", Tags: []string{"go"}})
+			},
+			wantErrors: []string{`got {Name:Chris Tags:[go tdd]}, want {Name:Cleo Tags:[go]}
+.Name: got "Chris", want "Cleo"
+.Tags[1]: got "tdd", didn't want it`},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			spy := &SpyTB{}
+
+			c.assertion(spy)
+
+			assertMessages(t, "errors", spy.Errors, c.wantErrors)
+			assertMessages(t, "fatals", spy.Fatals, c.wantFatals)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name      string
+		got, want any
+		diff      []string
+	}{
+		{"same", map[string]int{"a": 1}, map[string]int{"a": 1}, nil},
+		{"scalars", 1, 2, []string{"value: got 1, want 2"}},
+		{"missing slice items", []int{1}, []int{1, 2}, []string{"[1]: missing, want 2"}},
+		{
+			"map keys, in a stable order",
+			map[string]int{"a": 1, "b": 2, "c": 3},
+			map[string]int{"a": 1, "b": 5, "d": 4},
+			[]string{`["b"]: got 2, want 5`, `["c"]: got 3, didn't want it`, `["d"]: missing, want 4`},
+		},
+		{"pointers are followed", &person{Name: "Chris"}, &person{Name: "Cleo"}, []string{`.Name: got "Chris", want "Cleo"`}},
+		{"nil against something", []int(nil), []int{1}, []string{"[0]: missing, want 1"}},
+		{"different types", 1, "1", []string{`value: got 1, want "1"`}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := assert.Diff(c.got, c.want)
+			if !reflect.DeepEqual(got, c.diff) {
+				t.Errorf("got diff %q want %q", got, c.diff)
+			}
+		})
+	}
+}
+
+func assertMessages(t testing.TB, kind string, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s %q, want %q", kind, got, want)
+	}
+}
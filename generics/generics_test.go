@@ -1,6 +1,10 @@
 package generics
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/generics/assert"
+)
 
 func TestAssertFunctions(t *testing.T) {
 	t.Run("asserting on integers", func(t *testing.T) {
@@ -21,25 +25,25 @@ func TestStack(t *testing.T) {
 		myStackOfInts := NewStack[int]()
 
 		// check stack is empty
-		AssertTrue(t, myStackOfInts.IsEmpty())
+		assert.True(t, myStackOfInts.IsEmpty())
 
 		// add a thing, then check it's not empty
 		myStackOfInts.Push(123)
-		AssertFalse(t, myStackOfInts.IsEmpty())
+		assert.False(t, myStackOfInts.IsEmpty())
 
 		// add another thing, pop it back again
 		myStackOfInts.Push(456)
 		value, _ := myStackOfInts.Pop()
-		AssertEqual(t, value, 456)
+		assert.Equal(t, value, 456)
 		value, _ = myStackOfInts.Pop()
-		AssertEqual(t, value, 123)
-		AssertTrue(t, myStackOfInts.IsEmpty())
+		assert.Equal(t, value, 123)
+		assert.True(t, myStackOfInts.IsEmpty())
 
 		// can get the numbers we put in as numbers, not untyped interface{}
 		myStackOfInts.Push(1)
 		myStackOfInts.Push(2)
 		firstNum, _ := myStackOfInts.Pop()
 		secondNum, _ := myStackOfInts.Pop()
-		AssertEqual(t, firstNum+secondNum, 3)
+		assert.Equal(t, firstNum+secondNum, 3)
 	})
 }
@@ -0,0 +1,35 @@
+package generics
+
+// Number is any type that can be added up with +. The ~ means named types built on these count
+// too, so time.Duration, which is an int64 underneath, is a Number.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Reduce combines every item in collection into a single value, starting with initialValue and
+// calling f with the result so far and the next item. A and B can be different types, so a
+// collection of transactions can be reduced to a balance.
+func Reduce[A, B any](collection []A, f func(B, A) B, initialValue B) B {
+	result := initialValue
+	for _, x := range collection {
+		result = f(result, x)
+	}
+	return result
+}
+
+// Sum adds up each of the slices it is given, returning their totals in the same order.
+func Sum[T Number](xs ...[]T) []T {
+	total := func(numbers []T) T {
+		return Reduce(numbers, add[T], 0)
+	}
+
+	return Reduce(xs, func(totals []T, numbers []T) []T {
+		return append(totals, total(numbers))
+	}, make([]T, 0, len(xs)))
+}
+
+func add[T Number](a, b T) T {
+	return a + b
+}
@@ -0,0 +1,108 @@
+package generics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/generics/assert"
+)
+
+func TestSum(t *testing.T) {
+	t.Run("ints", func(t *testing.T) {
+		assert.DeepEqual(t, Sum([]int{1, 2}, []int{0, 9}), []int{3, 9})
+	})
+
+	t.Run("floats", func(t *testing.T) {
+		assert.DeepEqual(t, Sum([]float64{0.5, 0.25}, []float64{1.5}), []float64{0.75, 1.5})
+	})
+
+	t.Run("durations", func(t *testing.T) {
+		laps := []time.Duration{time.Minute, 30 * time.Second, 45 * time.Second}
+		assert.DeepEqual(t, Sum(laps), []time.Duration{2*time.Minute + 15*time.Second})
+	})
+
+	t.Run("empty slices add up to zero", func(t *testing.T) {
+		assert.DeepEqual(t, Sum([]int{}, nil, []int{3, 4, 5}), []int{0, 0, 12})
+	})
+
+	t.Run("nothing to sum", func(t *testing.T) {
+		assert.DeepEqual(t, Sum[int](), []int{})
+	})
+}
+
+func TestReduce(t *testing.T) {
+	t.Run("multiplication of all elements", func(t *testing.T) {
+		multiply := func(x, y int) int {
+			return x * y
+		}
+
+		assert.Equal(t, Reduce([]int{1, 2, 3}, multiply, 1), 6)
+	})
+
+	t.Run("into a different type", func(t *testing.T) {
+		lengths := func(acc int, s string) int {
+			return acc + len(s)
+		}
+
+		assert.Equal(t, Reduce([]string{"a", "bb", "ccc"}, lengths, 0), 6)
+	})
+
+	t.Run("nothing to reduce gives the initial value", func(t *testing.T) {
+		join := func(acc, s string) string {
+			return acc + s
+		}
+
+		assert.Equal(t, Reduce(nil, join, "start"), "start")
+	})
+}
+
+// The arrays chapter's examples, rebuilt on Sum and Reduce.
+
+func TestArraysChapterExamples(t *testing.T) {
+	t.Run("SumAllTails", func(t *testing.T) {
+		sumAllTails := func(numbers ...[]int) []int {
+			tails := Reduce(numbers, func(tails [][]int, x []int) [][]int {
+				if len(x) == 0 {
+					return append(tails, x)
+				}
+				return append(tails, x[1:])
+			}, [][]int{})
+			return Sum(tails...)
+		}
+
+		assert.DeepEqual(t, sumAllTails([]int{1, 2}, []int{0, 9}), []int{2, 9})
+		assert.DeepEqual(t, sumAllTails([]int{}, []int{3, 4, 5}), []int{0, 9})
+	})
+
+	t.Run("bank balance", func(t *testing.T) {
+		type transaction struct {
+			from, to string
+			sum      float64
+		}
+		transactions := []transaction{{"Chris", "Riya", 100}, {"Adil", "Chris", 25}}
+
+		balanceFor := func(name string, balance float64) float64 {
+			return Reduce(transactions, func(balance float64, t transaction) float64 {
+				if t.from == name {
+					balance -= t.sum
+				}
+				if t.to == name {
+					balance += t.sum
+				}
+				return balance
+			}, balance)
+		}
+
+		assert.Equal(t, balanceFor("Riya", 100), 200)
+		assert.Equal(t, balanceFor("Chris", 0), -75)
+		assert.Equal(t, balanceFor("Adil", 200), 175)
+	})
+
+	t.Run("concatenate strings", func(t *testing.T) {
+		concatenate := func(x, y string) string {
+			return x + y
+		}
+
+		assert.Equal(t, Reduce([]string{"a", "b", "c"}, concatenate, ""), "abc")
+	})
+}
@@ -1,9 +1,11 @@
 module github.com/quii/learn-go-with-tests
 
-go 1.23
+go 1.24
 
 require (
 	github.com/approvals/go-approval-tests v0.0.0-20211008131110-0c40b30e0000
 	github.com/gomarkdown/markdown v0.0.0-20240626202925-2eda941fd024
 	github.com/gorilla/websocket v1.5.3
+	golang.org/x/sys v0.36.0
+	google.golang.org/protobuf v1.36.9
 )
@@ -0,0 +1,135 @@
+// Package clientip works out the address of the client that made a request, even when it came
+// through reverse proxies or load balancers.
+//
+// Proxies say who they forwarded a request for in the X-Forwarded-For and X-Real-IP headers,
+// but anyone can send those headers, so they are only believed when the request came from a
+// proxy you trust.
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Resolver finds the client address of requests, given which proxies can be trusted.
+type Resolver struct {
+	trusted []netip.Prefix
+}
+
+// NewResolver returns a Resolver trusting the proxies at the given addresses or CIDR ranges,
+// such as "10.0.0.1" or "10.0.0.0/8". With no proxies every forwarding header is ignored.
+func NewResolver(trustedProxies ...string) (*Resolver, error) {
+	r := &Resolver{}
+
+	for _, proxy := range trustedProxies {
+		prefix, err := parsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q, %w", proxy, err)
+		}
+		r.trusted = append(r.trusted, prefix)
+	}
+
+	return r, nil
+}
+
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// ClientIP returns the address of the client that made req. If req came from a trusted proxy
+// the X-Forwarded-For header is read from the right, skipping any more trusted proxies, and the
+// first address which isn't one is the client. X-Real-IP is used if there is no X-Forwarded-For.
+// It returns the zero netip.Addr if req.RemoteAddr isn't an address, as can happen in tests.
+func (r *Resolver) ClientIP(req *http.Request) netip.Addr {
+	remote := remoteAddr(req)
+	if !r.isTrusted(remote) {
+		return remote
+	}
+
+	hops := forwardedFor(req)
+	if len(hops) == 0 {
+		if realIP, err := netip.ParseAddr(strings.TrimSpace(req.Header.Get("X-Real-IP"))); err == nil {
+			return realIP.Unmap()
+		}
+		return remote
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			// whoever added this wasn't a well behaved proxy, so don't believe anything further left
+			return client
+		}
+		client = hop.Unmap()
+		if !r.isTrusted(client) {
+			return client
+		}
+	}
+
+	return client
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteAddr(req *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
+
+// forwardedFor returns every address in the request's X-Forwarded-For headers, left to right.
+func forwardedFor(req *http.Request) []string {
+	var hops []string
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	return hops
+}
+
+type contextKey struct{}
+
+// Middleware stores the client address of each request in its context for FromContext, so
+// handlers and middleware further in, like loggers and rate limiters, all agree on who the client is.
+func (r *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.WithValue(req.Context(), contextKey{}, r.ClientIP(req))
+		next.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// FromContext returns the client address stored by Middleware.
+func FromContext(ctx context.Context) (netip.Addr, bool) {
+	addr, ok := ctx.Value(contextKey{}).(netip.Addr)
+	return addr, ok && addr.IsValid()
+}
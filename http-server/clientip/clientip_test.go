@@ -0,0 +1,185 @@
+package clientip_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/http-server/clientip"
+)
+
+func TestClientIP(t *testing.T) {
+	resolver, err := clientip.NewResolver("10.0.0.0/8", "192.168.1.1", "2001:db8::/32")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string][]string
+		want       string
+	}{
+		{
+			name:       "direct connection",
+			remoteAddr: "203.0.113.7:51234",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "spoofed X-Forwarded-For from an untrusted client is ignored",
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "spoofed X-Real-IP from an untrusted client is ignored",
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string][]string{"X-Real-IP": {"1.2.3.4"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "trusted proxy",
+			remoteAddr: "10.1.2.3:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.20"}},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "chain of trusted proxies",
+			remoteAddr: "10.1.2.3:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.20, 192.168.1.1, 10.9.9.9"}},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "client spoofing an address in front of a trusted proxy",
+			remoteAddr: "10.1.2.3:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4, 198.51.100.20"}},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "several X-Forwarded-For headers are read as one list",
+			remoteAddr: "10.1.2.3:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4", "198.51.100.20, 10.0.0.2"}},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "garbage in the chain stops at the last good hop",
+			remoteAddr: "10.1.2.3:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.20, not-an-ip, 10.0.0.2"}},
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "every hop trusted returns the leftmost",
+			remoteAddr: "10.1.2.3:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"10.0.0.5, 10.0.0.2"}},
+			want:       "10.0.0.5",
+		},
+		{
+			name:       "X-Real-IP from a trusted proxy",
+			remoteAddr: "192.168.1.1:443",
+			headers:    map[string][]string{"X-Real-IP": {" 198.51.100.20 "}},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "X-Forwarded-For wins over X-Real-IP",
+			remoteAddr: "192.168.1.1:443",
+			headers:    map[string][]string{"X-Real-IP": {"1.2.3.4"}, "X-Forwarded-For": {"198.51.100.20"}},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "trusted proxy without forwarding headers",
+			remoteAddr: "10.1.2.3:443",
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "IPv4 mapped IPv6 addresses are unmapped",
+			remoteAddr: "[::ffff:10.1.2.3]:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"::ffff:198.51.100.20"}},
+			want:       "198.51.100.20",
+		},
+		{
+			name:       "IPv6 proxy",
+			remoteAddr: "[2001:db8::1]:443",
+			headers:    map[string][]string{"X-Forwarded-For": {"2001:db9::7"}},
+			want:       "2001:db9::7",
+		},
+		{
+			name:       "remote address that isn't an address",
+			remoteAddr: "pipe",
+			headers:    map[string][]string{"X-Forwarded-For": {"1.2.3.4"}},
+			want:       "invalid IP",
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = test.remoteAddr
+			for name, values := range test.headers {
+				for _, v := range values {
+					req.Header.Add(name, v)
+				}
+			}
+
+			got := resolver.ClientIP(req)
+
+			if got.String() != test.want {
+				t.Errorf("got %s want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewResolver(t *testing.T) {
+	if _, err := clientip.NewResolver("10.0.0.0/33"); err == nil {
+		t.Error("expected an error for an invalid CIDR")
+	}
+	if _, err := clientip.NewResolver("proxy.internal"); err == nil {
+		t.Error("expected an error for a hostname")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	resolver, _ := clientip.NewResolver("10.0.0.1")
+
+	var got netip.Addr
+	var ok bool
+	handler := resolver.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok = clientip.FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.20")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !ok || got != netip.MustParseAddr("198.51.100.20") {
+		t.Errorf("got %v, %v from the context", got, ok)
+	}
+
+	if _, ok := clientip.FromContext(req.Context()); ok {
+		t.Error("didn't expect a client address on a context the middleware never saw")
+	}
+}
+
+func ExampleResolver_Middleware() {
+	resolver, _ := clientip.NewResolver("10.0.0.0/8")
+
+	logRequests := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, _ := clientip.FromContext(r.Context())
+			fmt.Printf("%s %s from %s\n", r.Method, r.URL.Path, client)
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	handler := resolver.Middleware(logRequests(http.NotFoundHandler()))
+
+	req := httptest.NewRequest(http.MethodGet, "/league", nil)
+	req.RemoteAddr = "10.0.0.7:5678"
+	req.Header.Set("X-Forwarded-For", "198.51.100.20")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Output: GET /league from 198.51.100.20
+}
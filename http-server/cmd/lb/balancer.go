@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultHealthPath = "/healthz"
+	// backendCookieName remembers which backend a client was sent to.
+	backendCookieName = "lb_backend"
+)
+
+// Balancer spreads requests across several PlayerServers, taking turns between the backends
+// which are up. Backends are ejected when a health check or a proxied request fails, and
+// rejoin once a health check passes again.
+//
+// A PlayerServer keeps login sessions, games being played over /ws/{id} and idempotency keys in
+// memory, so each client is given a cookie that sends it back to the same backend for as long
+// as that backend is up. Clients that don't keep cookies take turns like everyone else, and
+// anything a backend kept for a client is lost if the backend goes down.
+type Balancer struct {
+	backends   []*backend
+	next       atomic.Uint64
+	client     *http.Client
+	healthPath string
+}
+
+type backend struct {
+	url     *url.URL
+	proxy   *httputil.ReverseProxy
+	healthy atomic.Bool
+}
+
+// BalancerOption configures optional behaviour of a Balancer.
+type BalancerOption func(*Balancer)
+
+// WithHealthPath changes the path health checks request from each backend, which is /healthz
+// by default. Any response below 500 means the backend is up.
+func WithHealthPath(path string) BalancerOption {
+	return func(b *Balancer) {
+		b.healthPath = path
+	}
+}
+
+// WithHealthClient replaces the client health checks are made with, so they can time out sooner.
+func WithHealthClient(client *http.Client) BalancerOption {
+	return func(b *Balancer) {
+		b.client = client
+	}
+}
+
+// NewBalancer creates a Balancer for backends, all of which are assumed to be up until checked.
+func NewBalancer(backends []*url.URL, options ...BalancerOption) *Balancer {
+	b := &Balancer{
+		client:     &http.Client{Timeout: 2 * time.Second},
+		healthPath: defaultHealthPath,
+	}
+
+	for _, option := range options {
+		option(b)
+	}
+
+	for _, u := range backends {
+		be := &backend{url: u, proxy: httputil.NewSingleHostReverseProxy(u)}
+		be.healthy.Store(true)
+		be.proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			if r.Context().Err() != nil {
+				// the client went away, which says nothing about the backend
+				return
+			}
+			// the backend couldn't be reached, so stop sending it requests until it passes a health check
+			be.healthy.Store(false)
+			http.Error(w, "backend unavailable", http.StatusBadGateway)
+		}
+		b.backends = append(b.backends, be)
+	}
+
+	return b
+}
+
+func (b *Balancer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if be, ok := b.stuckTo(r); ok {
+		be.proxy.ServeHTTP(w, r)
+		return
+	}
+
+	i, ok := b.pick()
+	if !ok {
+		http.Error(w, "no healthy backends", http.StatusServiceUnavailable)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: backendCookieName, Value: strconv.Itoa(i), Path: "/", HttpOnly: true})
+	b.backends[i].proxy.ServeHTTP(w, r)
+}
+
+// stuckTo returns the backend r's client was sent to before, if it is still up.
+func (b *Balancer) stuckTo(r *http.Request) (*backend, bool) {
+	cookie, err := r.Cookie(backendCookieName)
+	if err != nil {
+		return nil, false
+	}
+
+	i, err := strconv.Atoi(cookie.Value)
+	if err != nil || i < 0 || i >= len(b.backends) || !b.backends[i].healthy.Load() {
+		return nil, false
+	}
+	return b.backends[i], true
+}
+
+// pick returns the index of the next healthy backend in turn.
+func (b *Balancer) pick() (int, bool) {
+	for range b.backends {
+		i := int((b.next.Add(1) - 1) % uint64(len(b.backends)))
+		if b.backends[i].healthy.Load() {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Healthy returns the backends which are currently being sent requests.
+func (b *Balancer) Healthy() []*url.URL {
+	var healthy []*url.URL
+	for _, be := range b.backends {
+		if be.healthy.Load() {
+			healthy = append(healthy, be.url)
+		}
+	}
+	return healthy
+}
+
+// CheckHealth checks every backend at the same time, ejecting those which are down and letting
+// back in those which have recovered.
+func (b *Balancer) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, be := range b.backends {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			be.healthy.Store(b.isUp(ctx, be))
+		}()
+	}
+	wg.Wait()
+}
+
+func (b *Balancer) isUp(ctx context.Context, be *backend) bool {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, be.url.JoinPath(b.healthPath).String(), nil)
+	if err != nil {
+		return false
+	}
+
+	response, err := b.client.Do(request)
+	if err != nil {
+		return false
+	}
+	response.Body.Close()
+
+	return response.StatusCode < http.StatusInternalServerError
+}
+
+// Watch checks the backends' health every time ticks receives a value, until ctx is cancelled.
+func (b *Balancer) Watch(ctx context.Context, ticks <-chan time.Time) {
+	for {
+		select {
+		case <-ticks:
+			b.CheckHealth(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
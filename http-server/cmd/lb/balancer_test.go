@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubBackend is a player server which says its name, and can be told to fail its health checks.
+type stubBackend struct {
+	*httptest.Server
+	name string
+	down atomic.Bool
+}
+
+func newStubBackend(t *testing.T, name string) *stubBackend {
+	t.Helper()
+
+	backend := &stubBackend{name: name}
+	backend.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == defaultHealthPath && backend.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, name)
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+func (s *stubBackend) url(t *testing.T) *url.URL {
+	t.Helper()
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestBalancer(t *testing.T) {
+	t.Run("takes turns between backends", func(t *testing.T) {
+		a, b := newStubBackend(t, "a"), newStubBackend(t, "b")
+		balancer := NewBalancer([]*url.URL{a.url(t), b.url(t)})
+
+		assertServedBy(t, balancer, "a", "b", "a", "b")
+	})
+
+	t.Run("ejects a backend failing its health check, and lets it back when it recovers", func(t *testing.T) {
+		a, b := newStubBackend(t, "a"), newStubBackend(t, "b")
+		balancer := NewBalancer([]*url.URL{a.url(t), b.url(t)})
+
+		a.down.Store(true)
+		balancer.CheckHealth(context.Background())
+
+		assertHealthy(t, balancer, b.url(t))
+		assertServedBy(t, balancer, "b", "b", "b")
+
+		a.down.Store(false)
+		balancer.CheckHealth(context.Background())
+
+		assertHealthy(t, balancer, a.url(t), b.url(t))
+		assertServedBy(t, balancer, "a", "b")
+	})
+
+	t.Run("ejects a backend which can't be reached", func(t *testing.T) {
+		a, b := newStubBackend(t, "a"), newStubBackend(t, "b")
+		balancer := NewBalancer([]*url.URL{a.url(t), b.url(t)})
+
+		b.Close()
+
+		response := get(balancer, "/league")
+		assertStatus(t, response, http.StatusOK)
+
+		response = get(balancer, "/league")
+		assertStatus(t, response, http.StatusBadGateway)
+
+		assertHealthy(t, balancer, a.url(t))
+		assertServedBy(t, balancer, "a", "a")
+	})
+
+	t.Run("keeps a backend which is up when the client goes away", func(t *testing.T) {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer slow.Close()
+		u, _ := url.Parse(slow.URL)
+		balancer := NewBalancer([]*url.URL{u})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(5*time.Millisecond, cancel)
+		balancer.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/league", nil).WithContext(ctx))
+
+		assertHealthy(t, balancer, u)
+	})
+
+	t.Run("sends a client back to the backend it was sent to before", func(t *testing.T) {
+		a, b := newStubBackend(t, "a"), newStubBackend(t, "b")
+		balancer := NewBalancer([]*url.URL{a.url(t), b.url(t)})
+
+		first := get(balancer, "/league")
+		cookies := first.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("got cookies %v, want one naming the backend", cookies)
+		}
+
+		for range 3 {
+			request := httptest.NewRequest(http.MethodGet, "/league", nil)
+			request.AddCookie(cookies[0])
+			response := httptest.NewRecorder()
+			balancer.ServeHTTP(response, request)
+
+			if got := response.Body.String(); got != first.Body.String() {
+				t.Errorf("served by %s, want %s", got, first.Body.String())
+			}
+		}
+
+		a.down.Store(true)
+		balancer.CheckHealth(context.Background())
+
+		request := httptest.NewRequest(http.MethodGet, "/league", nil)
+		request.AddCookie(cookies[0])
+		response := httptest.NewRecorder()
+		balancer.ServeHTTP(response, request)
+
+		if got := response.Body.String(); got != "b" {
+			t.Errorf("served by %s once a was down, want b", got)
+		}
+	})
+
+	t.Run("is unavailable when every backend is down", func(t *testing.T) {
+		a := newStubBackend(t, "a")
+		balancer := NewBalancer([]*url.URL{a.url(t)})
+
+		a.down.Store(true)
+		balancer.CheckHealth(context.Background())
+
+		assertStatus(t, get(balancer, "/league"), http.StatusServiceUnavailable)
+	})
+
+	t.Run("checks health at the given path", func(t *testing.T) {
+		a := newStubBackend(t, "a")
+		balancer := NewBalancer([]*url.URL{a.url(t)}, WithHealthPath("/readyz"))
+
+		a.down.Store(true) // only fails at /healthz
+		balancer.CheckHealth(context.Background())
+
+		assertHealthy(t, balancer, a.url(t))
+	})
+
+	t.Run("watches backends' health on every tick", func(t *testing.T) {
+		a, b := newStubBackend(t, "a"), newStubBackend(t, "b")
+		balancer := NewBalancer([]*url.URL{a.url(t), b.url(t)})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ticks := make(chan time.Time)
+		go balancer.Watch(ctx, ticks)
+
+		a.down.Store(true)
+		ticks <- time.Now()
+		ticks <- time.Now() // the first check has finished once the second tick is received
+
+		assertHealthy(t, balancer, b.url(t))
+	})
+}
+
+func TestParseBackends(t *testing.T) {
+	t.Run("parses a comma separated list", func(t *testing.T) {
+		got, err := parseBackends("http://localhost:5000, http://localhost:5001")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []*url.URL{
+			{Scheme: "http", Host: "localhost:5000"},
+			{Scheme: "http", Host: "localhost:5001"},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("rejects backends which aren't URLs", func(t *testing.T) {
+		if _, err := parseBackends("localhost:5000"); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func get(handler http.Handler, path string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, path, nil))
+	return response
+}
+
+func assertServedBy(t testing.TB, balancer *Balancer, backends ...string) {
+	t.Helper()
+
+	var got []string
+	for range backends {
+		got = append(got, get(balancer, "/league").Body.String())
+	}
+	if !reflect.DeepEqual(got, backends) {
+		t.Errorf("requests served by %v, want %v", got, backends)
+	}
+}
+
+func assertHealthy(t testing.TB, balancer *Balancer, want ...*url.URL) {
+	t.Helper()
+
+	if got := balancer.Healthy(); !reflect.DeepEqual(got, want) {
+		t.Errorf("healthy backends are %v, want %v", got, want)
+	}
+}
+
+func assertStatus(t testing.TB, response *httptest.ResponseRecorder, want int) {
+	t.Helper()
+
+	if response.Code != want {
+		t.Errorf("got status %d want %d", response.Code, want)
+	}
+}
@@ -0,0 +1,75 @@
+// Balances requests across several copies of the player server, so one can go down, or be
+// restarted, without players noticing.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/acceptance-tests/gracefulshutdown"
+)
+
+// shutdownTimeout is how long requests being proxied get to finish once the balancer is asked to stop.
+const shutdownTimeout = 10 * time.Second
+
+var (
+	addr       = flag.String("addr", ":8080", "address to listen on")
+	backends   = flag.String("backends", "http://localhost:5000,http://localhost:5001", "comma separated URLs of the player servers to balance across")
+	healthPath = flag.String("health", defaultHealthPath, "path to check each backend's health at")
+	interval   = flag.Duration("interval", 5*time.Second, "how often to check the backends' health")
+)
+
+func main() {
+	flag.Parse()
+
+	urls, err := parseBackends(*backends)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	balancer := NewBalancer(urls, WithHealthPath(*healthPath))
+	balancer.CheckHealth(ctx)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	go balancer.Watch(ctx, ticker.C)
+
+	server := gracefulshutdown.NewServer(
+		&http.Server{Addr: *addr, Handler: balancer},
+		gracefulshutdown.WithTimeout(shutdownTimeout),
+	)
+
+	log.Printf("balancing %d backends on %s", len(urls), *addr)
+	if err := server.ListenAndServe(ctx); err != nil {
+		log.Fatalf("problem shutting down gracefully %v", err)
+	}
+
+	log.Println("shut down gracefully")
+}
+
+func parseBackends(list string) ([]*url.URL, error) {
+	var urls []*url.URL
+	for _, raw := range strings.Split(list, ",") {
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("backend %q should be a URL like http://localhost:5000", raw)
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
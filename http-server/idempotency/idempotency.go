@@ -0,0 +1,247 @@
+// Package idempotency lets clients safely retry POST requests. A client sends the same
+// Idempotency-Key header with every attempt at a request, and if an earlier attempt already got
+// through the response it got is replayed rather than doing the work again, so a win recorded
+// over a flaky connection is only recorded once. Keys belong to the caller who sent them, so one
+// client can't see the response to another's request by guessing its key.
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// Header is the request header clients put their idempotency key in.
+	Header = "Idempotency-Key"
+
+	// ReplayedHeader is set to "true" on responses which were replayed rather than freshly made.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// Response is a response saved so it can be replayed, along with a hash of the request that got it.
+type Response struct {
+	RequestHash string
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// KeyStore saves the responses to requests made with idempotency keys, forgetting them after ttl.
+type KeyStore interface {
+	Get(key string) (Response, bool)
+	Put(key string, response Response, ttl time.Duration)
+}
+
+// Guard replays the responses to requests it has seen before.
+type Guard struct {
+	store  KeyStore
+	ttl    time.Duration
+	caller func(r *http.Request) string
+
+	lock     sync.Mutex
+	inFlight map[string]bool
+}
+
+// GuardOption configures optional behaviour of a Guard.
+type GuardOption func(*Guard)
+
+// WithCaller sets how a Guard tells who made a request, such as by their session cookie. By
+// default callers are told apart by their Authorization header.
+func WithCaller(caller func(r *http.Request) string) GuardOption {
+	return func(g *Guard) {
+		g.caller = caller
+	}
+}
+
+// NewGuard returns a Guard remembering responses in store for ttl.
+func NewGuard(store KeyStore, ttl time.Duration, options ...GuardOption) *Guard {
+	g := &Guard{
+		store:    store,
+		ttl:      ttl,
+		caller:   func(r *http.Request) string { return r.Header.Get("Authorization") },
+		inFlight: map[string]bool{},
+	}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Middleware replays the saved response to a POST whose Idempotency-Key the same caller has
+// used before. Reusing a key for a different request is a 422, and retrying while the first
+// attempt is still being handled is a 409. Requests without a key, or which aren't POSTs, are
+// passed straight through. Server errors, 401s and 403s aren't saved, so the client can try
+// again once the server has recovered or they've logged in.
+func (g *Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(Header) == "" || r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := scopedKey(g.caller(r), r.Header.Get(Header))
+
+		hash, err := hashRequest(r)
+		if err != nil {
+			http.Error(w, "could not read request body", http.StatusBadRequest)
+			return
+		}
+
+		if !g.begin(key) {
+			http.Error(w, "a request with this "+Header+" is still being handled", http.StatusConflict)
+			return
+		}
+		defer g.end(key)
+
+		if saved, ok := g.store.Get(key); ok {
+			if saved.RequestHash != hash {
+				http.Error(w, Header+" was already used for a different request", http.StatusUnprocessableEntity)
+				return
+			}
+			replay(w, saved)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if worthSaving(recorder.status) {
+			g.store.Put(key, Response{
+				RequestHash: hash,
+				Status:      recorder.status,
+				Header:      w.Header().Clone(),
+				Body:        recorder.body.Bytes(),
+			}, g.ttl)
+		}
+	})
+}
+
+// worthSaving reports whether a response with status would be the same if the request was retried.
+func worthSaving(status int) bool {
+	return status < http.StatusInternalServerError &&
+		status != http.StatusUnauthorized && status != http.StatusForbidden
+}
+
+// scopedKey is what a response is saved under: the client's key hashed together with who the
+// caller is, so the same key from different callers is kept apart and nobody's credentials end
+// up in the store.
+func scopedKey(caller, key string) string {
+	h := sha256.New()
+	io.WriteString(h, caller+"\n"+key)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (g *Guard) begin(key string) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.inFlight[key] {
+		return false
+	}
+	g.inFlight[key] = true
+	return true
+}
+
+func (g *Guard) end(key string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	delete(g.inFlight, key)
+}
+
+// hashRequest identifies a request by its method, path and body, putting the body back so the
+// handler can still read it.
+func hashRequest(r *http.Request) (string, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func replay(w http.ResponseWriter, saved Response) {
+	for name, values := range saved.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(saved.Status)
+	w.Write(saved.Body)
+}
+
+// responseRecorder passes a response on to the client while keeping a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// MemoryStore is a KeyStore which keeps responses in memory, so they are forgotten on restart.
+type MemoryStore struct {
+	now func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	response Response
+	expires  time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore which uses now to tell when responses expire.
+func NewMemoryStore(now func() time.Time) *MemoryStore {
+	return &MemoryStore{now: now, entries: map[string]memoryEntry{}}
+}
+
+// Get returns the response saved for key, if it hasn't expired.
+func (m *MemoryStore) Get(key string) (Response, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || !m.now().Before(entry.expires) {
+		return Response{}, false
+	}
+	return entry.response, true
+}
+
+// Put saves response for key until ttl has passed, clearing out anything that has already expired.
+func (m *MemoryStore) Put(key string, response Response, ttl time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := m.now()
+	for k, entry := range m.entries {
+		if !now.Before(entry.expires) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = memoryEntry{response: response, expires: now.Add(ttl)}
+}
@@ -0,0 +1,278 @@
+package idempotency_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/http-server/idempotency"
+)
+
+const ttl = time.Hour
+
+func TestGuard(t *testing.T) {
+	t.Run("a retried request gets the first response without running the handler again", func(t *testing.T) {
+		handler := &countingHandler{status: http.StatusAccepted}
+		guarded := newGuard(time.Now).Middleware(handler)
+
+		first := post(guarded, "/players/Pepper", "key-1", "")
+		second := post(guarded, "/players/Pepper", "key-1", "")
+
+		assertCalls(t, handler, 1)
+		assertStatus(t, first, http.StatusAccepted)
+		assertStatus(t, second, http.StatusAccepted)
+		assertBody(t, second, "call 1")
+		assertHeader(t, second, "X-Call", "1")
+		assertHeader(t, second, idempotency.ReplayedHeader, "true")
+		assertHeader(t, first, idempotency.ReplayedHeader, "")
+	})
+
+	t.Run("different keys are different requests", func(t *testing.T) {
+		handler := &countingHandler{status: http.StatusAccepted}
+		guarded := newGuard(time.Now).Middleware(handler)
+
+		post(guarded, "/players/Pepper", "key-1", "")
+		second := post(guarded, "/players/Pepper", "key-2", "")
+
+		assertCalls(t, handler, 2)
+		assertBody(t, second, "call 2")
+	})
+
+	t.Run("requests without a key are never replayed", func(t *testing.T) {
+		handler := &countingHandler{status: http.StatusAccepted}
+		guarded := newGuard(time.Now).Middleware(handler)
+
+		post(guarded, "/players/Pepper", "", "")
+		post(guarded, "/players/Pepper", "", "")
+
+		assertCalls(t, handler, 2)
+	})
+
+	t.Run("only POSTs are guarded", func(t *testing.T) {
+		handler := &countingHandler{status: http.StatusOK}
+		guarded := newGuard(time.Now).Middleware(handler)
+
+		for i := 0; i < 2; i++ {
+			request := httptest.NewRequest(http.MethodGet, "/players/Pepper", nil)
+			request.Header.Set(idempotency.Header, "key-1")
+			guarded.ServeHTTP(httptest.NewRecorder(), request)
+		}
+
+		assertCalls(t, handler, 2)
+	})
+
+	t.Run("reusing a key for a different request is rejected", func(t *testing.T) {
+		handler := &countingHandler{status: http.StatusAccepted}
+		guarded := newGuard(time.Now).Middleware(handler)
+
+		post(guarded, "/players/Pepper", "key-1", "")
+		differentPath := post(guarded, "/players/Floyd", "key-1", "")
+		differentBody := post(guarded, "/players/Pepper", "key-1", "some body")
+
+		assertCalls(t, handler, 1)
+		assertStatus(t, differentPath, http.StatusUnprocessableEntity)
+		assertStatus(t, differentBody, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("the handler can still read the body", func(t *testing.T) {
+		var got string
+		guarded := newGuard(time.Now).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			got = string(body)
+		}))
+
+		post(guarded, "/players/Pepper", "key-1", "hello")
+
+		if got != "hello" {
+			t.Errorf("got body %q want %q", got, "hello")
+		}
+	})
+
+	t.Run("server errors are not saved so the client can try again", func(t *testing.T) {
+		handler := &countingHandler{status: http.StatusInternalServerError}
+		guarded := newGuard(time.Now).Middleware(handler)
+
+		post(guarded, "/players/Pepper", "key-1", "")
+		post(guarded, "/players/Pepper", "key-1", "")
+
+		assertCalls(t, handler, 2)
+	})
+
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		t.Run(fmt.Sprintf("%d responses are not saved so the client can log in and try again", status), func(t *testing.T) {
+			handler := &countingHandler{status: status}
+			guarded := newGuard(time.Now).Middleware(handler)
+
+			post(guarded, "/players/Pepper", "key-1", "")
+			post(guarded, "/players/Pepper", "key-1", "")
+
+			assertCalls(t, handler, 2)
+		})
+	}
+
+	t.Run("the same key from different callers is a different request", func(t *testing.T) {
+		handler := &countingHandler{status: http.StatusAccepted}
+		guarded := newGuard(time.Now).Middleware(handler)
+
+		pepper := postAs(guarded, "Bearer pepper", "/players/Pepper", "key-1")
+		floyd := postAs(guarded, "Bearer floyd", "/players/Pepper", "key-1")
+		retry := postAs(guarded, "Bearer pepper", "/players/Pepper", "key-1")
+
+		assertCalls(t, handler, 2)
+		assertBody(t, pepper, "call 1")
+		assertBody(t, floyd, "call 2")
+		assertBody(t, retry, "call 1")
+	})
+
+	t.Run("callers can be told apart some other way", func(t *testing.T) {
+		handler := &countingHandler{status: http.StatusAccepted}
+		byUserAgent := idempotency.WithCaller(func(r *http.Request) string { return r.UserAgent() })
+		guarded := idempotency.NewGuard(idempotency.NewMemoryStore(time.Now), ttl, byUserAgent).Middleware(handler)
+
+		for _, agent := range []string{"pepper", "floyd", "pepper"} {
+			request := httptest.NewRequest(http.MethodPost, "/players/Pepper", nil)
+			request.Header.Set(idempotency.Header, "key-1")
+			request.Header.Set("User-Agent", agent)
+			guarded.ServeHTTP(httptest.NewRecorder(), request)
+		}
+
+		assertCalls(t, handler, 2)
+	})
+
+	t.Run("keys are forgotten after the ttl", func(t *testing.T) {
+		now := time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)
+		handler := &countingHandler{status: http.StatusAccepted}
+		guarded := newGuard(func() time.Time { return now }).Middleware(handler)
+
+		post(guarded, "/players/Pepper", "key-1", "")
+		now = now.Add(ttl - time.Second)
+		post(guarded, "/players/Pepper", "key-1", "")
+		assertCalls(t, handler, 1)
+
+		now = now.Add(time.Second)
+		post(guarded, "/players/Pepper", "key-1", "")
+		assertCalls(t, handler, 2)
+	})
+
+	t.Run("a retry while the first attempt is in flight is a conflict", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		guarded := newGuard(time.Now).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}))
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			post(guarded, "/players/Pepper", "key-1", "")
+		}()
+
+		<-started
+		retry := post(guarded, "/players/Pepper", "key-1", "")
+		close(release)
+		wg.Wait()
+
+		assertStatus(t, retry, http.StatusConflict)
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)
+	store := idempotency.NewMemoryStore(func() time.Time { return now })
+
+	store.Put("old", idempotency.Response{Status: http.StatusOK}, time.Minute)
+	store.Put("new", idempotency.Response{Status: http.StatusAccepted}, time.Hour)
+
+	if got, ok := store.Get("new"); !ok || got.Status != http.StatusAccepted {
+		t.Errorf("got %+v, %v want the accepted response", got, ok)
+	}
+
+	now = now.Add(time.Minute)
+
+	if _, ok := store.Get("old"); ok {
+		t.Error("expected the old response to have expired")
+	}
+	if _, ok := store.Get("new"); !ok {
+		t.Error("expected the new response to still be saved")
+	}
+	if _, ok := store.Get("never"); ok {
+		t.Error("didn't expect a response for a key that was never saved")
+	}
+}
+
+// countingHandler numbers the calls made to it.
+type countingHandler struct {
+	status int
+	lock   sync.Mutex
+	calls  int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.lock.Lock()
+	h.calls++
+	call := h.calls
+	h.lock.Unlock()
+
+	w.Header().Set("X-Call", fmt.Sprint(call))
+	w.WriteHeader(h.status)
+	fmt.Fprintf(w, "call %d", call)
+}
+
+func newGuard(now func() time.Time) *idempotency.Guard {
+	return idempotency.NewGuard(idempotency.NewMemoryStore(now), ttl)
+}
+
+func post(handler http.Handler, path, key, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		request.Header.Set(idempotency.Header, key)
+	}
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func postAs(handler http.Handler, authorization, path, key string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, path, nil)
+	request.Header.Set(idempotency.Header, key)
+	request.Header.Set("Authorization", authorization)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func assertCalls(t testing.TB, handler *countingHandler, want int) {
+	t.Helper()
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+	if handler.calls != want {
+		t.Errorf("got %d calls to the handler want %d", handler.calls, want)
+	}
+}
+
+func assertStatus(t testing.TB, response *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if response.Code != want {
+		t.Errorf("got status %d want %d", response.Code, want)
+	}
+}
+
+func assertBody(t testing.TB, response *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	if got := response.Body.String(); got != want {
+		t.Errorf("got body %q want %q", got, want)
+	}
+}
+
+func assertHeader(t testing.TB, response *httptest.ResponseRecorder, name, want string) {
+	t.Helper()
+	if got := response.Header().Get(name); got != want {
+		t.Errorf("got %s header %q want %q", name, got, want)
+	}
+}
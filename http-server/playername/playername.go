@@ -0,0 +1,93 @@
+// Package playername checks the names players give before they are recorded, so that the
+// league doesn't end up with names that look the same but aren't, or that can't be shown or
+// looked up again.
+//
+// Names are tidied up before they are checked: any kind of space, such as a no-break space, is
+// made an ordinary one, runs of spaces become one, spaces at either end are removed and
+// invisible zero width spaces are dropped. "Chris  Smith " is then the same player as
+// "Chris Smith".
+package playername
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength is the most characters, not bytes, a name can have.
+const MaxLength = 32
+
+// The reasons a name can be rejected. Each reads well after the name of the field it is for.
+var (
+	ErrEmpty    = errors.New("must not be empty")
+	ErrTooLong  = fmt.Errorf("must be at most %d characters", MaxLength)
+	ErrEncoding = errors.New("must be valid UTF-8")
+	ErrControl  = errors.New("cannot contain control characters")
+	ErrSlash    = errors.New("names cannot contain /")
+	ErrReserved = errors.New("is reserved")
+)
+
+// reserved are names which can't be told apart from something else in a URL path.
+var reserved = []string{".", ".."}
+
+// Normalize tidies name up and checks it, returning the name to record the player under or the
+// reason it can't be used.
+func Normalize(name string) (string, error) {
+	if !utf8.ValidString(name) {
+		return "", ErrEncoding
+	}
+
+	var b strings.Builder
+	lastWasSpace := true // so leading spaces are dropped
+	for _, r := range name {
+		switch {
+		case isControl(r):
+			return "", ErrControl
+		case r == '\u200b' || r == '\ufeff': // a zero width space or byte order mark
+			continue
+		case unicode.IsSpace(r):
+			if !lastWasSpace {
+				b.WriteByte(' ')
+			}
+			lastWasSpace = true
+		default:
+			b.WriteRune(r)
+			lastWasSpace = false
+		}
+	}
+	normalized := strings.TrimSuffix(b.String(), " ")
+
+	switch {
+	case normalized == "":
+		return "", ErrEmpty
+	case utf8.RuneCountInString(normalized) > MaxLength:
+		return "", ErrTooLong
+	case strings.Contains(normalized, "/"):
+		return "", ErrSlash
+	}
+	for _, r := range reserved {
+		if normalized == r {
+			return "", ErrReserved
+		}
+	}
+	return normalized, nil
+}
+
+// isControl reports whether r is a control character, including the tab and newline, or one of
+// the invisible marks that change the direction text is shown in, which can make a name look
+// like another.
+func isControl(r rune) bool {
+	switch {
+	case unicode.IsControl(r):
+		return true
+	case r == '\u200e' || r == '\u200f':
+		return true
+	case '\u202a' <= r && r <= '\u202e':
+		return true
+	case '\u2066' <= r && r <= '\u2069':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package playername_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/http-server/playername"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+		err   error
+	}{
+		{name: "an ordinary name", input: "Chris", want: "Chris"},
+		{name: "a name with a space", input: "Chris Smith", want: "Chris Smith"},
+		{name: "letters from other alphabets", input: "Zoë Ødegård", want: "Zoë Ødegård"},
+		{name: "emoji", input: "Pepper 🌶", want: "Pepper 🌶"},
+		{name: "spaces at either end are dropped", input: "  Chris ", want: "Chris"},
+		{name: "runs of spaces become one", input: "Chris   Smith", want: "Chris Smith"},
+		{name: "a no-break space is an ordinary space", input: "Chris\u00a0Smith", want: "Chris Smith"},
+		{name: "an em space is an ordinary space", input: "Chris\u2003Smith", want: "Chris Smith"},
+		{name: "an ideographic space is an ordinary space", input: "Chris\u3000Smith", want: "Chris Smith"},
+		{name: "a mix of spaces at the ends", input: "\u00a0 \u2009Chris ", want: "Chris"},
+		{name: "zero width spaces are dropped", input: "Ch\u200bris", want: "Chris"},
+		{name: "a byte order mark is dropped", input: "\ufeffChris", want: "Chris"},
+		{name: "the longest name allowed", input: strings.Repeat("a", playername.MaxLength), want: strings.Repeat("a", playername.MaxLength)},
+		{name: "length is counted in characters, not bytes", input: strings.Repeat("é", playername.MaxLength), want: strings.Repeat("é", playername.MaxLength)},
+		{name: "dots are fine in a longer name", input: "C.J.", want: "C.J."},
+
+		{name: "nothing", input: "", err: playername.ErrEmpty},
+		{name: "only spaces", input: " \u00a0\u3000 ", err: playername.ErrEmpty},
+		{name: "only a zero width space", input: "\u200b", err: playername.ErrEmpty},
+		{name: "one character too long", input: strings.Repeat("a", playername.MaxLength+1), err: playername.ErrTooLong},
+		{name: "too long once spaces are tidied is still too long", input: " " + strings.Repeat("é", playername.MaxLength+1) + " ", err: playername.ErrTooLong},
+		{name: "long only because of its spaces", input: "Chris" + strings.Repeat(" ", 40) + "Smith", want: "Chris Smith"},
+		{name: "a tab", input: "Chris\tSmith", err: playername.ErrControl},
+		{name: "a newline", input: "Chris\n", err: playername.ErrControl},
+		{name: "a NUL", input: "Chris\x00", err: playername.ErrControl},
+		{name: "a delete", input: "Chris\x7f", err: playername.ErrControl},
+		{name: "a next line control", input: "Chris\u0085Smith", err: playername.ErrControl},
+		{name: "a right-to-left override", input: "Chris\u202eoleC", err: playername.ErrControl},
+		{name: "a right-to-left mark", input: "\u200fChris", err: playername.ErrControl},
+		{name: "a directional isolate", input: "\u2067Chris\u2069", err: playername.ErrControl},
+		{name: "invalid UTF-8", input: "Chris\xff", err: playername.ErrEncoding},
+		{name: "a slash", input: "Chris/Smith", err: playername.ErrSlash},
+		{name: "a dot", input: ".", err: playername.ErrReserved},
+		{name: "two dots, padded with spaces", input: " .. ", err: playername.ErrReserved},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := playername.Normalize(c.input)
+
+			if !errors.Is(err, c.err) {
+				t.Fatalf("got error %v want %v", err, c.err)
+			}
+			if got != c.want {
+				t.Errorf("got %q want %q", got, c.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"iter"
 	"slices"
 	"testing"
+
+	"github.com/quii/learn-go-with-tests/generics/assert"
 )
 
 func Concatenate(seq iter.Seq[string]) string {
@@ -30,9 +32,7 @@ func TestConcatenate(t *testing.T) {
 	t.Run("values of a slice", func(t *testing.T) {
 		got := Concatenate(slices.Values([]string{"a", "b", "c"}))
 		want := "abc"
-		if got != want {
-			t.Errorf("got %q want %q", got, want)
-		}
+		assert.Equal(t, got, want)
 	})
 
 	t.Run("values of a slice backwards", func(t *testing.T) {
@@ -40,16 +40,12 @@ func TestConcatenate(t *testing.T) {
 
 		got := Concatenate(Values(backward))
 		want := "cba"
-		if got != want {
-			t.Errorf("got %q want %q", got, want)
-		}
+		assert.Equal(t, got, want)
 	})
 
 	t.Run("values of a slice sorted", func(t *testing.T) {
 		got := Concatenate(slices.Values(slices.Sorted(slices.Values([]string{"c", "a", "b"}))))
 		want := "abc"
-		if got != want {
-			t.Errorf("got %q want %q", got, want)
-		}
+		assert.Equal(t, got, want)
 	})
 }
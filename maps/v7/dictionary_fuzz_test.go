@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func FuzzDictionary(f *testing.F) {
+	f.Add("test", "this is just a test")
+	f.Add("", "")
+	f.Add("über", "über alles")
+
+	f.Fuzz(func(t *testing.T, word, definition string) {
+		dictionary := Dictionary{}
+
+		assertError(t, dictionary.Add(word, definition), nil)
+		assertDefinition(t, dictionary, word, definition)
+
+		assertError(t, dictionary.Add(word, "something else"), ErrWordExists)
+		assertDefinition(t, dictionary, word, definition)
+
+		assertError(t, dictionary.Update(word, definition+" updated"), nil)
+		assertDefinition(t, dictionary, word, definition+" updated")
+
+		assertError(t, dictionary.Delete(word), nil)
+		_, err := dictionary.Search(word)
+		assertError(t, err, ErrNotFound)
+		assertError(t, dictionary.Delete(word), ErrWordDoesNotExist)
+	})
+}
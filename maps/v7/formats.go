@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// The dictionary can be read from and written to two kinds of file, one entry per line, so that
+// even a big file is dealt with a line at a time.
+//
+// A TSV glossary has the word, a tab, then its definition. Tabs, newlines and backslashes in
+// either are written as \t, \n and \\. Blank lines are skipped.
+//
+// JSON Lines has an object like {"word":"go","definition":"a language"} on each line.
+
+// LineError is a problem with one line of a file being read into a Dictionary.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e LineError) Unwrap() error {
+	return e.Err
+}
+
+// ErrMalformedLine means a line isn't in the format being read.
+var ErrMalformedLine = errors.New("malformed line")
+
+// ReadTSV reads a TSV glossary into a Dictionary. Lines that can't be read, or that repeat a
+// word, are skipped and the rest are still read; their LineErrors are then joined together and
+// returned along with the Dictionary.
+func ReadTSV(r io.Reader) (Dictionary, error) {
+	return readLines(r, func(line string) (string, string, error) {
+		word, definition, found := strings.Cut(line, "\t")
+		if !found {
+			return "", "", fmt.Errorf("%w: want a word and definition separated by a tab", ErrMalformedLine)
+		}
+
+		word, err := unescapeTSV(word)
+		if err != nil {
+			return "", "", err
+		}
+		definition, err = unescapeTSV(definition)
+		return word, definition, err
+	})
+}
+
+// WriteTSV writes d as a TSV glossary, sorted by word.
+func WriteTSV(w io.Writer, d Dictionary) error {
+	return writeLines(w, d, func(word, definition string) (string, error) {
+		return tsvEscaper.Replace(word) + "\t" + tsvEscaper.Replace(definition), nil
+	})
+}
+
+// jsonEntry is a line of JSON Lines.
+type jsonEntry struct {
+	Word       string `json:"word"`
+	Definition string `json:"definition"`
+}
+
+// ReadJSONLines reads JSON Lines into a Dictionary. Like ReadTSV, lines that can't be read are
+// skipped and reported together once the rest have been read.
+func ReadJSONLines(r io.Reader) (Dictionary, error) {
+	return readLines(r, func(line string) (string, string, error) {
+		var entry jsonEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return "", "", fmt.Errorf("%w: %v", ErrMalformedLine, err)
+		}
+		return entry.Word, entry.Definition, nil
+	})
+}
+
+// WriteJSONLines writes d as JSON Lines, sorted by word.
+func WriteJSONLines(w io.Writer, d Dictionary) error {
+	return writeLines(w, d, func(word, definition string) (string, error) {
+		line, err := json.Marshal(jsonEntry{Word: word, Definition: definition})
+		return string(line), err
+	})
+}
+
+func readLines(r io.Reader, parse func(line string) (word, definition string, err error)) (Dictionary, error) {
+	d := Dictionary{}
+	var errs []error
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		word, definition, err := parse(line)
+		if err == nil && word == "" {
+			err = fmt.Errorf("%w: the word is missing", ErrMalformedLine)
+		}
+		if err == nil {
+			err = d.Add(word, definition)
+		}
+		if err != nil {
+			errs = append(errs, LineError{Line: number, Err: err})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return d, err
+	}
+	return d, errors.Join(errs...)
+}
+
+func writeLines(w io.Writer, d Dictionary, format func(word, definition string) (string, error)) error {
+	words := make([]string, 0, len(d))
+	for word := range d {
+		words = append(words, word)
+	}
+	slices.Sort(words)
+
+	buffered := bufio.NewWriter(w)
+	for _, word := range words {
+		line, err := format(word, d[word])
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(buffered, line); err != nil {
+			return err
+		}
+	}
+	return buffered.Flush()
+}
+
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`)
+
+func unescapeTSV(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		if i == len(s) {
+			return "", fmt.Errorf("%w: it ends with a lone \\", ErrMalformedLine)
+		}
+		switch s[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		default:
+			return "", fmt.Errorf("%w: unknown escape \\%c", ErrMalformedLine, s[i])
+		}
+	}
+	return b.String(), nil
+}
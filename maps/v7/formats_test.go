@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/generics/assert"
+)
+
+var formats = []struct {
+	name  string
+	read  func(r io.Reader) (Dictionary, error)
+	write func(w io.Writer, d Dictionary) error
+}{
+	{
+		name:  "TSV",
+		read:  ReadTSV,
+		write: WriteTSV,
+	},
+	{
+		name:  "JSON Lines",
+		read:  ReadJSONLines,
+		write: WriteJSONLines,
+	},
+}
+
+func TestFormatsRoundTrip(t *testing.T) {
+	dictionary := Dictionary{
+		"go":      "a programming language",
+		"tab":     "a key\tthat indents",
+		"poem":    "roses are red\nviolets are blue",
+		"slash":   `C:\Users`,
+		"unicode": "café ☕",
+	}
+
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			var file bytes.Buffer
+			assert.NoError(t, format.write(&file, dictionary))
+
+			got, err := format.read(&file)
+
+			assert.NoError(t, err)
+			assert.DeepEqual(t, got, dictionary)
+		})
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	var file bytes.Buffer
+
+	err := WriteTSV(&file, Dictionary{"zebra": "stripy", "apple": "a fruit", "tab": "a\tkey"})
+
+	assert.NoError(t, err)
+	assertStrings(t, file.String(), "apple\ta fruit\ntab\ta\\tkey\nzebra\tstripy\n")
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	var file bytes.Buffer
+
+	err := WriteJSONLines(&file, Dictionary{"zebra": "stripy", "apple": "a fruit"})
+
+	assert.NoError(t, err)
+	assertStrings(t, file.String(), `{"word":"apple","definition":"a fruit"}`+"\n"+`{"word":"zebra","definition":"stripy"}`+"\n")
+}
+
+func TestReadingMalformedFiles(t *testing.T) {
+	cases := []struct {
+		name      string
+		read      func(file string) (Dictionary, error)
+		file      string
+		want      Dictionary
+		badLines  []int
+		wantCause []error
+	}{
+		{
+			name: "TSV",
+			read: func(file string) (Dictionary, error) { return ReadTSV(strings.NewReader(file)) },
+			file: "go\ta language\n" +
+				"no tab here\n" +
+				"\n" +
+				"\tno word\n" +
+				"go\tagain\n" +
+				"bad\tescape \\q\n" +
+				"map\ta hash table\n",
+			want:      Dictionary{"go": "a language", "map": "a hash table"},
+			badLines:  []int{2, 4, 5, 6},
+			wantCause: []error{ErrMalformedLine, ErrWordExists},
+		},
+		{
+			name: "JSON Lines",
+			read: func(file string) (Dictionary, error) { return ReadJSONLines(strings.NewReader(file)) },
+			file: `{"word":"go","definition":"a language"}` + "\n" +
+				`{"word":"broken"` + "\n" +
+				`{"definition":"no word"}` + "\n" +
+				`{"word":"map","definition":"a hash table"}` + "\n",
+			want:      Dictionary{"go": "a language", "map": "a hash table"},
+			badLines:  []int{2, 3},
+			wantCause: []error{ErrMalformedLine},
+		},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.read(test.file)
+
+			assert.DeepEqual(t, got, test.want)
+			for _, cause := range test.wantCause {
+				assert.ErrorIs(t, err, cause)
+			}
+
+			var badLines []int
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var lineErr LineError
+				if !errors.As(e, &lineErr) {
+					t.Fatalf("got %v, want every error to be a LineError", e)
+				}
+				badLines = append(badLines, lineErr.Line)
+			}
+			assert.DeepEqual(t, badLines, test.badLines)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordFrequencies counts how many times each word appears in some text.
+type WordFrequencies map[string]int
+
+// WordCount is a word and the number of times it was seen.
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// BuildFromText reads all of r and counts its words. Words are compared case-insensitively
+// and surrounding punctuation is ignored, so "Go," and "go" are the same word.
+func BuildFromText(r io.Reader) (WordFrequencies, error) {
+	frequencies := WordFrequencies{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanWords)
+
+	for scanner.Scan() {
+		frequencies[strings.ToLower(scanner.Text())]++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return frequencies, nil
+}
+
+// TopN returns the n most frequent words, most frequent first. Words with the same
+// count are in alphabetical order so the result is always the same for the same text.
+func (f WordFrequencies) TopN(n int) []WordCount {
+	counts := make([]WordCount, 0, len(f))
+	for word, count := range f {
+		counts = append(counts, WordCount{word, count})
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Word < counts[j].Word
+	})
+
+	if n < 0 {
+		n = 0
+	}
+	if n < len(counts) {
+		counts = counts[:n]
+	}
+
+	return counts
+}
+
+// scanWords is a bufio.SplitFunc returning runs of letters and digits. Apostrophes
+// inside a word are kept so "don't" stays one word.
+func scanWords(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	start := 0
+	for start < len(data) {
+		if !atEOF && !utf8.FullRune(data[start:]) {
+			return start, nil, nil
+		}
+		r, width := utf8.DecodeRune(data[start:])
+		if isWordRune(r) {
+			break
+		}
+		start += width
+	}
+
+	for i := start; i < len(data); {
+		if !atEOF && !utf8.FullRune(data[i:]) {
+			return start, nil, nil
+		}
+		r, width := utf8.DecodeRune(data[i:])
+		if !isWordRune(r) && !(r == '\'' && i > start) {
+			return i + width, trimApostrophes(data[start:i]), nil
+		}
+		i += width
+	}
+
+	if atEOF && len(data) > start {
+		return len(data), trimApostrophes(data[start:]), nil
+	}
+
+	return start, nil, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func trimApostrophes(word []byte) []byte {
+	for len(word) > 0 && word[len(word)-1] == '\'' {
+		word = word[:len(word)-1]
+	}
+	return word
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/quii/learn-go-with-tests/generics/assert"
+)
+
+func TestBuildFromText(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+		want WordFrequencies
+	}{
+		{"empty text", "", WordFrequencies{}},
+		{"single word", "hello", WordFrequencies{"hello": 1}},
+		{"ignores case", "Go go GO", WordFrequencies{"go": 3}},
+		{"ignores punctuation", "tests, tests. (tests!)", WordFrequencies{"tests": 3}},
+		{"keeps apostrophes inside words", "don't 'quote' me", WordFrequencies{"don't": 1, "quote": 1, "me": 1}},
+		{"splits on any whitespace", "one\ttwo\n\nthree  one", WordFrequencies{"one": 2, "two": 1, "three": 1}},
+		{"counts numbers and non-ascii letters", "café 42 café", WordFrequencies{"café": 2, "42": 1}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := BuildFromText(strings.NewReader(test.text))
+
+			assertError(t, err, nil)
+			assert.DeepEqual(t, got, test.want)
+		})
+	}
+}
+
+func TestBuildFromTextReadsInSmallChunks(t *testing.T) {
+	got, err := BuildFromText(iotest.OneByteReader(strings.NewReader("café, Café and more café")))
+
+	assertError(t, err, nil)
+	want := WordFrequencies{"café": 3, "and": 1, "more": 1}
+	assert.DeepEqual(t, got, want)
+}
+
+func TestTopN(t *testing.T) {
+	frequencies := WordFrequencies{"the": 5, "go": 3, "tests": 3, "maps": 1}
+
+	t.Run("most frequent first, ties alphabetical", func(t *testing.T) {
+		got := frequencies.TopN(3)
+		want := []WordCount{{"the", 5}, {"go", 3}, {"tests", 3}}
+
+		assert.DeepEqual(t, got, want)
+	})
+
+	t.Run("n larger than the number of words", func(t *testing.T) {
+		got := frequencies.TopN(10)
+
+		if len(got) != len(frequencies) {
+			t.Errorf("got %d words want %d", len(got), len(frequencies))
+		}
+	})
+
+	t.Run("zero or negative n", func(t *testing.T) {
+		if got := frequencies.TopN(0); len(got) != 0 {
+			t.Errorf("got %v want nothing", got)
+		}
+		if got := frequencies.TopN(-1); len(got) != 0 {
+			t.Errorf("got %v want nothing", got)
+		}
+	})
+}
+
+func BenchmarkBuildFromText(b *testing.B) {
+	document := strings.Repeat("It was the best of times, it was the worst of times; it was the age of wisdom. ", 10000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		frequencies, err := BuildFromText(strings.NewReader(document))
+		if err != nil {
+			b.Fatal(err)
+		}
+		frequencies.TopN(10)
+	}
+}
@@ -31,7 +31,7 @@ func SecondsInRadians(t time.Time) float64 {
 // SecondHandPoint is the unit vector of the second hand at time `t`,.
 // represented a Point.
 func SecondHandPoint(t time.Time) Point {
-	return angleToPoint(SecondsInRadians(t))
+	return secondHandPoints[t.Second()]
 }
 
 // MinutesInRadians returns the angle of the minute hand from 12 o'clock in radians.
@@ -43,7 +43,7 @@ func MinutesInRadians(t time.Time) float64 {
 // MinuteHandPoint is the unit vector of the minute hand at time `t`,.
 // represented a Point.
 func MinuteHandPoint(t time.Time) Point {
-	return angleToPoint(MinutesInRadians(t))
+	return rotate(minuteHandPoints[t.Minute()], minuteSecondCreep[t.Second()])
 }
 
 // HoursInRadians returns the angle of the hour hand from 12 o'clock in radians.
@@ -55,7 +55,8 @@ func HoursInRadians(t time.Time) float64 {
 // HourHandPoint is the unit vector of the hour hand at time `t`,.
 // represented a Point.
 func HourHandPoint(t time.Time) Point {
-	return angleToPoint(HoursInRadians(t))
+	hourAndMinute := (t.Hour()%hoursInClock)*minutesInClock + t.Minute()
+	return rotate(hourHandPoints[hourAndMinute], hourSecondCreep[t.Second()])
 }
 
 func angleToPoint(angle float64) Point {
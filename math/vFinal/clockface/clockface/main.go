@@ -2,13 +2,31 @@
 package main
 
 import (
+	"flag"
 	"os"
 	"time"
 
 	"github.com/quii/learn-go-with-tests/math/vFinal/clockface/svg"
 )
 
+var (
+	digital = flag.Bool("digital", false, "draw a seven-segment digital clock instead of an analogue one")
+	moon    = flag.Bool("moon", false, "show the phase of the Moon on an analogue clock")
+	dark    = flag.Bool("dark", false, "draw an analogue clock with a dark theme")
+)
+
 func main() {
+	flag.Parse()
+
 	t := time.Now()
-	svg.Write(os.Stdout, t)
+	if *digital {
+		svg.DigitalWriter(os.Stdout, t, svg.Options{})
+		return
+	}
+
+	opts := svg.Options{MoonPhase: *moon}
+	if *dark {
+		opts.Theme = svg.DarkTheme
+	}
+	svg.WriteWithOptions(os.Stdout, t, opts)
 }
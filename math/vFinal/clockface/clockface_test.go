@@ -6,6 +6,7 @@ import (
 	"time"
 
 	. "github.com/quii/learn-go-with-tests/math/vFinal/clockface"
+	"github.com/quii/learn-go-with-tests/math/vFinal/epsilon"
 )
 
 func TestSecondsInRadians(t *testing.T) {
@@ -22,9 +23,7 @@ func TestSecondsInRadians(t *testing.T) {
 	for _, c := range cases {
 		t.Run(testName(c.time), func(t *testing.T) {
 			got := SecondsInRadians(c.time)
-			if !roughlyEqualFloat64(got, c.angle) {
-				t.Fatalf("Wanted %v radians, but got %v", c.angle, got)
-			}
+			epsilon.Float(t, got, c.angle)
 		})
 	}
 }
@@ -41,9 +40,7 @@ func TestSecondHandPoint(t *testing.T) {
 	for _, c := range cases {
 		t.Run(testName(c.time), func(t *testing.T) {
 			got := SecondHandPoint(c.time)
-			if !roughlyEqualPoint(got, c.point) {
-				t.Fatalf("Wanted %v Point, but got %v", c.point, got)
-			}
+			epsilon.Vector(t, got, c.point)
 		})
 	}
 }
@@ -60,9 +57,7 @@ func TestMinutesInRadians(t *testing.T) {
 	for _, c := range cases {
 		t.Run(testName(c.time), func(t *testing.T) {
 			got := MinutesInRadians(c.time)
-			if !roughlyEqualFloat64(got, c.angle) {
-				t.Fatalf("Wanted %v radians, but got %v", c.angle, got)
-			}
+			epsilon.Float(t, got, c.angle)
 		})
 	}
 }
@@ -79,9 +74,7 @@ func TestMinuteHandPoint(t *testing.T) {
 	for _, c := range cases {
 		t.Run(testName(c.time), func(t *testing.T) {
 			got := MinuteHandPoint(c.time)
-			if !roughlyEqualPoint(got, c.point) {
-				t.Fatalf("Wanted %v Point, but got %v", c.point, got)
-			}
+			epsilon.Vector(t, got, c.point)
 		})
 	}
 }
@@ -100,9 +93,7 @@ func TestHoursInRadians(t *testing.T) {
 	for _, c := range cases {
 		t.Run(testName(c.time), func(t *testing.T) {
 			got := HoursInRadians(c.time)
-			if !roughlyEqualFloat64(got, c.angle) {
-				t.Fatalf("Wanted %v radians, but got %v", c.angle, got)
-			}
+			epsilon.Float(t, got, c.angle)
 		})
 	}
 }
@@ -119,23 +110,11 @@ func TestHourHandPoint(t *testing.T) {
 	for _, c := range cases {
 		t.Run(testName(c.time), func(t *testing.T) {
 			got := HourHandPoint(c.time)
-			if !roughlyEqualPoint(got, c.point) {
-				t.Fatalf("Wanted %v Point, but got %v", c.point, got)
-			}
+			epsilon.Vector(t, got, c.point)
 		})
 	}
 }
 
-func roughlyEqualFloat64(a, b float64) bool {
-	const equalityThreshold = 1e-7
-	return math.Abs(a-b) < equalityThreshold
-}
-
-func roughlyEqualPoint(a, b Point) bool {
-	return roughlyEqualFloat64(a.X, b.X) &&
-		roughlyEqualFloat64(a.Y, b.Y)
-}
-
 func simpleTime(hours, minutes, seconds int) time.Time {
 	return time.Date(312, time.October, 28, hours, minutes, seconds, 0, time.UTC)
 }
@@ -143,3 +122,45 @@ func simpleTime(hours, minutes, seconds int) time.Time {
 func testName(t time.Time) string {
 	return t.Format("15:04:05")
 }
+
+func TestHandPointsMatchTheirAngles(t *testing.T) {
+	start := simpleTime(0, 0, 0)
+
+	for s := 0; s < 24*60*60; s++ {
+		tm := start.Add(time.Duration(s) * time.Second)
+
+		hands := []struct {
+			name  string
+			point Point
+			angle float64
+		}{
+			{"second", SecondHandPoint(tm), SecondsInRadians(tm)},
+			{"minute", MinuteHandPoint(tm), MinutesInRadians(tm)},
+			{"hour", HourHandPoint(tm), HoursInRadians(tm)},
+		}
+
+		for _, hand := range hands {
+			want := Point{math.Sin(hand.angle), math.Cos(hand.angle)}
+			if !epsilon.Vector(t, hand.point, want) {
+				t.Fatalf("%s hand at %s is wrong", hand.name, tm.Format("15:04:05"))
+			}
+		}
+	}
+}
+
+var benchmarkTime = simpleTime(10, 37, 42)
+
+func BenchmarkHourHandPoint(b *testing.B) {
+	b.Run("memoized", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			HourHandPoint(benchmarkTime)
+		}
+	})
+
+	b.Run("trigonometry", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			angle := HoursInRadians(benchmarkTime)
+			_ = Point{math.Sin(angle), math.Cos(angle)}
+		}
+	})
+}
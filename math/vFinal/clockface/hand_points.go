@@ -0,0 +1,35 @@
+package clockface
+
+import (
+	"math"
+
+	"github.com/quii/learn-go-with-tests/math/vFinal/linalg"
+)
+
+// A clock only has so many hand positions, so rather than doing trigonometry on every render the
+// unit vectors are worked out once. The minute and hour hands also creep forward with the
+// seconds; that creep is kept in its own small table and added on by rotating the hand's point.
+var (
+	secondHandPoints  = handPoints(secondsInClock, secondsInClock)
+	minuteHandPoints  = handPoints(minutesInClock, minutesInClock)
+	hourHandPoints    = handPoints(hoursInClock*minutesInClock, hoursInClock*minutesInClock)
+	minuteSecondCreep = handPoints(secondsInClock, minutesInClock*secondsInClock)
+	hourSecondCreep   = handPoints(secondsInClock, hoursInClock*minutesInClock*secondsInClock)
+)
+
+// handPoints returns the unit vectors of the first n of the positions a hand making a full turn
+// in steps steps can be in.
+func handPoints(n, steps int) []Point {
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = angleToPoint(2 * math.Pi * float64(i) / float64(steps))
+	}
+	return points
+}
+
+// rotate turns p clockwise by the angle of the unit vector by. As by is (sin, cos) of that
+// angle, it can be put straight into a rotation matrix without any more trigonometry.
+func rotate(p, by Point) Point {
+	clockwise := linalg.Matrix{A: by.Y, B: -by.X, C: by.X, D: by.Y}
+	return Point(clockwise.Apply(linalg.Vec2(p)))
+}
@@ -0,0 +1,25 @@
+package clockface
+
+import (
+	"math"
+	"time"
+)
+
+// The Moon goes from new to full and back again every synodic month. Its length changes a little
+// from one month to the next, but counting average months on from a new moon we know the time of
+// puts the phase within a day or so, which is plenty for a picture on a clock.
+const synodicMonthInDays = 29.530588853
+
+const secondsInDay = 24 * 60 * 60
+
+// knownNewMoon is the new moon of 6 January 2000, 18:14 UTC.
+var knownNewMoon = time.Date(2000, time.January, 6, 18, 14, 0, 0, time.UTC)
+
+// MoonPhase is how far through the lunar cycle the Moon is at time t, from 0 up to but not
+// including 1: 0 is a new moon, 0.25 the first quarter, 0.5 a full moon and 0.75 the last quarter.
+func MoonPhase(t time.Time) float64 {
+	// Work in seconds rather than with t.Sub, as a Duration can only span about 290 years.
+	seconds := float64(t.Unix()-knownNewMoon.Unix()) + float64(t.Nanosecond())/1e9
+	months := seconds / (synodicMonthInDays * secondsInDay)
+	return months - math.Floor(months)
+}
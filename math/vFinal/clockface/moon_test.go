@@ -0,0 +1,42 @@
+package clockface_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/quii/learn-go-with-tests/math/vFinal/clockface"
+	"github.com/quii/learn-go-with-tests/math/vFinal/epsilon"
+)
+
+// Counting average months can be out by up to about 15 hours, which is 0.02 of the cycle.
+var phaseTolerance = epsilon.Within(0.025)
+
+func TestMoonPhase(t *testing.T) {
+	cases := []struct {
+		name  string
+		time  time.Time
+		phase float64
+	}{
+		{"the new moon we count from", time.Date(2000, time.January, 6, 18, 14, 0, 0, time.UTC), 0},
+		{"new moon", time.Date(2024, time.January, 11, 11, 57, 0, 0, time.UTC), 0},
+		{"first quarter", time.Date(2024, time.January, 18, 3, 53, 0, 0, time.UTC), 0.25},
+		{"full moon", time.Date(2024, time.January, 25, 17, 54, 0, 0, time.UTC), 0.5},
+		{"last quarter", time.Date(2024, time.February, 2, 23, 18, 0, 0, time.UTC), 0.75},
+		{"full moon of the January 2000 eclipse", time.Date(2000, time.January, 21, 4, 40, 0, 0, time.UTC), 0.5},
+		{"new moon in another time zone", time.Date(2025, time.October, 21, 13, 25, 0, 0, time.FixedZone("CEST", 2*60*60)), 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := MoonPhase(c.time)
+
+			if got < 0 || got >= 1 {
+				t.Fatalf("phase %v is outside of [0, 1)", got)
+			}
+			// the phase wraps around from just under 1 to 0 at each new moon
+			if !phaseTolerance.EqualFloats(got, c.phase) && !phaseTolerance.EqualFloats(got-1, c.phase) {
+				t.Errorf("got phase %v want %v", got, c.phase)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package clockface
+
+import (
+	"math"
+	"time"
+)
+
+// A stopwatch's hands are geared differently to a clock's: the tenths hand goes round once a
+// second, the second hand once a minute and the minute hand once an hour.
+const tenthsInSecond = 10
+
+// StopwatchTenthsInRadians returns the angle of the tenths hand after elapsed, which ticks
+// round once a second in steps of a tenth.
+func StopwatchTenthsInRadians(elapsed time.Duration) float64 {
+	tenths := (elapsed / (time.Second / tenthsInSecond)) % tenthsInSecond
+	return 2 * math.Pi * float64(tenths) / tenthsInSecond
+}
+
+// StopwatchSecondsInRadians returns the angle of the second hand after elapsed, which sweeps
+// round once a minute.
+func StopwatchSecondsInRadians(elapsed time.Duration) float64 {
+	return 2 * math.Pi * float64(elapsed%time.Minute) / float64(time.Minute)
+}
+
+// StopwatchMinutesInRadians returns the angle of the minute hand after elapsed, which sweeps
+// round once an hour.
+func StopwatchMinutesInRadians(elapsed time.Duration) float64 {
+	return 2 * math.Pi * float64(elapsed%time.Hour) / float64(time.Hour)
+}
+
+// StopwatchTenthsHandPoint is the unit vector of the tenths hand after elapsed.
+func StopwatchTenthsHandPoint(elapsed time.Duration) Point {
+	return angleToPoint(StopwatchTenthsInRadians(elapsed))
+}
+
+// StopwatchSecondHandPoint is the unit vector of the second hand after elapsed.
+func StopwatchSecondHandPoint(elapsed time.Duration) Point {
+	return angleToPoint(StopwatchSecondsInRadians(elapsed))
+}
+
+// StopwatchMinuteHandPoint is the unit vector of the minute hand after elapsed.
+func StopwatchMinuteHandPoint(elapsed time.Duration) Point {
+	return angleToPoint(StopwatchMinutesInRadians(elapsed))
+}
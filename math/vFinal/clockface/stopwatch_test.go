@@ -0,0 +1,91 @@
+package clockface_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	. "github.com/quii/learn-go-with-tests/math/vFinal/clockface"
+	"github.com/quii/learn-go-with-tests/math/vFinal/epsilon"
+)
+
+func TestStopwatchTenthsInRadians(t *testing.T) {
+	cases := []struct {
+		elapsed time.Duration
+		angle   float64
+	}{
+		{0, 0},
+		{100 * time.Millisecond, math.Pi / 5},
+		{150 * time.Millisecond, math.Pi / 5},
+		{500 * time.Millisecond, math.Pi},
+		{time.Second, 0},
+		{3*time.Minute + 900*time.Millisecond, 9 * math.Pi / 5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.elapsed.String(), func(t *testing.T) {
+			got := StopwatchTenthsInRadians(c.elapsed)
+			epsilon.Float(t, got, c.angle)
+		})
+	}
+}
+
+func TestStopwatchSecondsInRadians(t *testing.T) {
+	cases := []struct {
+		elapsed time.Duration
+		angle   float64
+	}{
+		{0, 0},
+		{15 * time.Second, math.Pi / 2},
+		{30*time.Second + 500*time.Millisecond, math.Pi + math.Pi/60},
+		{time.Minute, 0},
+		{61 * time.Minute, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.elapsed.String(), func(t *testing.T) {
+			got := StopwatchSecondsInRadians(c.elapsed)
+			epsilon.Float(t, got, c.angle)
+		})
+	}
+}
+
+func TestStopwatchMinutesInRadians(t *testing.T) {
+	cases := []struct {
+		elapsed time.Duration
+		angle   float64
+	}{
+		{0, 0},
+		{30 * time.Minute, math.Pi},
+		{7*time.Minute + 30*time.Second, math.Pi / 4},
+		{time.Hour, 0},
+		{25*time.Hour + 45*time.Minute, 3 * math.Pi / 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.elapsed.String(), func(t *testing.T) {
+			got := StopwatchMinutesInRadians(c.elapsed)
+			epsilon.Float(t, got, c.angle)
+		})
+	}
+}
+
+func TestStopwatchHandPoints(t *testing.T) {
+	elapsed := 15*time.Minute + 45*time.Second + 500*time.Millisecond
+
+	cases := []struct {
+		name  string
+		got   Point
+		point Point
+	}{
+		{"tenths", StopwatchTenthsHandPoint(elapsed), Point{0, -1}},
+		{"seconds", StopwatchSecondHandPoint(45 * time.Second), Point{-1, 0}},
+		{"minutes", StopwatchMinuteHandPoint(elapsed), Point{math.Sin(StopwatchMinutesInRadians(elapsed)), math.Cos(StopwatchMinutesInRadians(elapsed))}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			epsilon.Vector(t, c.got, c.point)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package svg
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	digitWidth     = 40
+	digitHeight    = 70
+	segmentWidth   = 8
+	digitSpacing   = 12
+	colonWidth     = 12
+	digitalPadding = 10
+)
+
+// segments of a seven-segment display, named in the usual way, clockwise from the top with g in the middle.
+const (
+	segA = 1 << iota
+	segB
+	segC
+	segD
+	segE
+	segF
+	segG
+)
+
+var digitSegments = [10]int{
+	segA | segB | segC | segD | segE | segF,
+	segB | segC,
+	segA | segB | segG | segE | segD,
+	segA | segB | segG | segC | segD,
+	segF | segG | segB | segC,
+	segA | segF | segG | segC | segD,
+	segA | segF | segG | segE | segD | segC,
+	segA | segB | segC,
+	segA | segB | segC | segD | segE | segF | segG,
+	segA | segB | segC | segD | segF | segG,
+}
+
+// DigitalWriter writes an SVG of a seven-segment digital clock showing the time t to w.
+func DigitalWriter(w io.Writer, t time.Time, opts Options) {
+	values := []int{t.Hour(), t.Minute()}
+	if !opts.HideSeconds {
+		values = append(values, t.Second())
+	}
+
+	digits, colons := 2*len(values), len(values)-1
+	width := 2*digitalPadding + digits*(digitWidth+digitSpacing) + colons*(colonWidth+digitSpacing) - digitSpacing
+	height := 2*digitalPadding + digitHeight
+
+	fmt.Fprintf(w, digitalSVGStart, width, height)
+
+	x := digitalPadding
+	for i, v := range values {
+		if i > 0 {
+			colon(w, x)
+			x += colonWidth + digitSpacing
+		}
+		for _, d := range []int{v / 10, v % 10} {
+			digit(w, x, d)
+			x += digitWidth + digitSpacing
+		}
+	}
+
+	io.WriteString(w, svgEnd)
+}
+
+func digit(w io.Writer, x, d int) {
+	const (
+		top    = digitalPadding
+		middle = digitalPadding + (digitHeight-segmentWidth)/2
+		bottom = digitalPadding + digitHeight - segmentWidth
+		half   = digitHeight / 2
+	)
+	right := x + digitWidth - segmentWidth
+
+	lit := digitSegments[d]
+	horizontal := func(y int) { segment(w, x, y, digitWidth, segmentWidth) }
+	vertical := func(x, y int) { segment(w, x, y, segmentWidth, half) }
+
+	if lit&segA != 0 {
+		horizontal(top)
+	}
+	if lit&segB != 0 {
+		vertical(right, top)
+	}
+	if lit&segC != 0 {
+		vertical(right, top+half)
+	}
+	if lit&segD != 0 {
+		horizontal(bottom)
+	}
+	if lit&segE != 0 {
+		vertical(x, top+half)
+	}
+	if lit&segF != 0 {
+		vertical(x, top)
+	}
+	if lit&segG != 0 {
+		horizontal(middle)
+	}
+}
+
+func segment(w io.Writer, x, y, width, height int) {
+	fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" style="fill:#f00;"/>`, x, y, width, height)
+}
+
+func colon(w io.Writer, x int) {
+	cx := x + colonWidth/2
+	r := segmentWidth / 2
+	fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%d" style="fill:#f00;"/>`, cx, digitalPadding+digitHeight/3, r)
+	fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%d" style="fill:#f00;"/>`, cx, digitalPadding+2*digitHeight/3, r)
+}
+
+const digitalSVGStart = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd">
+<svg xmlns="http://www.w3.org/2000/svg"
+     width="100%%"
+     height="100%%"
+     viewBox="0 0 %d %d"
+     version="2.0">
+<rect x="0" y="0" width="%[1]d" height="%[2]d" style="fill:#000;"/>`
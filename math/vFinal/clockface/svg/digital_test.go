@@ -0,0 +1,87 @@
+package svg_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/quii/learn-go-with-tests/math/vFinal/clockface/svg"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+type Rect struct {
+	X      int `xml:"x,attr"`
+	Y      int `xml:"y,attr"`
+	Width  int `xml:"width,attr"`
+	Height int `xml:"height,attr"`
+}
+
+type DigitalSVG struct {
+	XMLName xml.Name `xml:"svg"`
+	Rects   []Rect   `xml:"rect"`
+	Circles []Circle `xml:"circle"`
+}
+
+func TestDigitalWriter(t *testing.T) {
+	cases := []struct {
+		name string
+		time time.Time
+		opts Options
+	}{
+		{"with seconds", simpleTime(12, 34, 56), Options{}},
+		{"without seconds", simpleTime(9, 5, 0), Options{HideSeconds: true}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := bytes.Buffer{}
+			DigitalWriter(&b, c.time, c.opts)
+
+			assertGolden(t, b.Bytes())
+		})
+	}
+
+	t.Run("lights the segments for each digit and draws a colon between hours and minutes", func(t *testing.T) {
+		b := bytes.Buffer{}
+		DigitalWriter(&b, simpleTime(8, 8, 0), Options{HideSeconds: true})
+
+		svg := DigitalSVG{}
+		if err := xml.Unmarshal(b.Bytes(), &svg); err != nil {
+			t.Fatal(err)
+		}
+
+		// a background plus 6 segments for each 0 and 7 for each 8
+		if got, want := len(svg.Rects), 1+2*6+2*7; got != want {
+			t.Errorf("got %d rects, want %d", got, want)
+		}
+
+		if got, want := len(svg.Circles), 2; got != want {
+			t.Errorf("got %d circles, want %d", got, want)
+		}
+	})
+}
+
+func assertGolden(t *testing.T, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", filepath.Base(t.Name())+".golden.svg")
+
+	if *update {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("could not read golden file, run the tests with -update to create it, %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run the tests with -update if the change is intended\ngot:\n%s", golden, got)
+	}
+}
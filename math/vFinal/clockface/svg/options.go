@@ -0,0 +1,15 @@
+package svg
+
+// Options changes how a clock is drawn. The zero value draws the clock with all of its hands.
+type Options struct {
+	// HideSeconds leaves out the second hand, or the seconds digits of a digital clock.
+	HideSeconds bool
+	// MoonPhase adds a picture of the Moon, lit as it is at the clock's time, below the middle of
+	// an analogue clock.
+	MoonPhase bool
+	// Theme is the palette an analogue clock is drawn in, LightTheme if it is left empty.
+	Theme Theme
+	// InlineStyles gives every part of an analogue clock a style attribute, as clocks used to be
+	// drawn, instead of a class styled by a style block.
+	InlineStyles bool
+}
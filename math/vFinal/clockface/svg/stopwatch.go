@@ -0,0 +1,30 @@
+package svg
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	cf "github.com/quii/learn-go-with-tests/math/vFinal/clockface"
+)
+
+const (
+	stopwatchSecondHandLength = 90
+	stopwatchMinuteHandLength = 60
+	stopwatchTenthsHandLength = 40
+)
+
+// StopwatchWriter writes an SVG analogue stopwatch to w showing how much time has elapsed, with
+// minute, second and tenths of a second hands.
+func StopwatchWriter(w io.Writer, elapsed time.Duration) {
+	io.WriteString(w, svgStart)
+	bezel(w, newStylesheet(LightTheme, true))
+	hand(w, cf.StopwatchMinuteHandPoint(elapsed), stopwatchMinuteHandLength, handStyle("#000"))
+	hand(w, cf.StopwatchSecondHandPoint(elapsed), stopwatchSecondHandLength, handStyle("#f00"))
+	hand(w, cf.StopwatchTenthsHandPoint(elapsed), stopwatchTenthsHandLength, handStyle("#00f"))
+	io.WriteString(w, svgEnd)
+}
+
+func handStyle(colour string) string {
+	return fmt.Sprintf(`style="fill:none;stroke:%s;stroke-width:3px;"`, colour)
+}
@@ -0,0 +1,51 @@
+package svg_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	. "github.com/quii/learn-go-with-tests/math/vFinal/clockface/svg"
+)
+
+func TestStopwatchWriter(t *testing.T) {
+	cases := []struct {
+		name    string
+		elapsed time.Duration
+		lines   []Line
+	}{
+		{
+			"nothing elapsed",
+			0,
+			[]Line{{150, 150, 150, 90}, {150, 150, 150, 60}, {150, 150, 150, 110}},
+		},
+		{
+			"half an hour, a quarter minute and half a second",
+			30*time.Minute + 15*time.Second + 500*time.Millisecond,
+			[]Line{{150, 150, 148.377, 209.978}, {150, 150, 239.877, 154.71}, {150, 150, 150, 190}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := bytes.Buffer{}
+			StopwatchWriter(&b, c.elapsed)
+
+			svg := SVG{}
+			if err := xml.Unmarshal(b.Bytes(), &svg); err != nil {
+				t.Fatalf("could not parse stopwatch SVG, %v", err)
+			}
+
+			if len(svg.Line) != len(c.lines) {
+				t.Fatalf("got %d hands, want %d", len(svg.Line), len(c.lines))
+			}
+
+			for _, line := range c.lines {
+				if !containsLine(line, svg.Line) {
+					t.Errorf("Expected to find the hand line %+v, in the SVG lines %+v", line, svg.Line)
+				}
+			}
+		})
+	}
+}
@@ -4,9 +4,11 @@ package svg
 import (
 	"fmt"
 	"io"
+	"math"
 	"time"
 
 	cf "github.com/quii/learn-go-with-tests/math/vFinal/clockface"
+	"github.com/quii/learn-go-with-tests/math/vFinal/linalg"
 )
 
 const (
@@ -15,37 +17,77 @@ const (
 	hourHandLength   = 50
 	clockCentreX     = 150
 	clockCentreY     = 150
+	moonRadius       = 15
+	moonOffset       = 45
 )
 
 // Write writes an SVG representation of an analogue clock, showing the time t, to the writer w.
 func Write(w io.Writer, t time.Time) {
+	WriteWithOptions(w, t, Options{})
+}
+
+// WriteWithOptions writes an SVG analogue clock showing the time t to w, drawn according to opts.
+func WriteWithOptions(w io.Writer, t time.Time, opts Options) {
+	styles := newStylesheet(opts.Theme, opts.InlineStyles)
+
 	io.WriteString(w, svgStart)
-	io.WriteString(w, bezel)
-	secondHand(w, t)
-	minuteHand(w, t)
-	hourHand(w, t)
+	styles.writeTo(w)
+	bezel(w, styles)
+	if opts.MoonPhase {
+		moonPhase(w, t, styles)
+	}
+	if !opts.HideSeconds {
+		hand(w, cf.SecondHandPoint(t), secondHandLength, styles.attr("second-hand"))
+	}
+	hand(w, cf.MinuteHandPoint(t), minuteHandLength, styles.attr("minute-hand"))
+	hand(w, cf.HourHandPoint(t), hourHandLength, styles.attr("hour-hand"))
 	io.WriteString(w, svgEnd)
 }
 
-func secondHand(w io.Writer, t time.Time) {
-	p := makeHand(cf.SecondHandPoint(t), secondHandLength)
-	fmt.Fprintf(w, `<line x1="150" y1="150" x2="%.3f" y2="%.3f" style="fill:none;stroke:#f00;stroke-width:3px;"/>`, p.X, p.Y)
+func bezel(w io.Writer, styles stylesheet) {
+	fmt.Fprintf(w, `<circle cx="150" cy="150" r="100" %s/>`, styles.attr("bezel"))
+}
+
+// hand draws a hand from the middle of the clock towards the unit vector p, styled by attr.
+func hand(w io.Writer, p cf.Point, length float64, attr string) {
+	p = makeHand(p, length)
+	fmt.Fprintf(w, `<line x1="150" y1="150" x2="%.3f" y2="%.3f" %s/>`, p.X, p.Y, attr)
 }
 
-func minuteHand(w io.Writer, t time.Time) {
-	p := makeHand(cf.MinuteHandPoint(t), minuteHandLength)
-	fmt.Fprintf(w, `<line x1="150" y1="150" x2="%.3f" y2="%.3f" style="fill:none;stroke:#000;stroke-width:3px;"/>`, p.X, p.Y)
+// moonPhase draws the Moon as a dark disc with its lit part over the top. The lit part is bounded
+// by half of the Moon's edge, on the right as it waxes and the left as it wanes, and by the
+// terminator, which is half of an ellipse whose width follows the cosine of the phase.
+func moonPhase(w io.Writer, t time.Time, styles stylesheet) {
+	phase := cf.MoonPhase(t)
+	cos := math.Cos(2 * math.Pi * phase)
+
+	waxing := phase < 0.5
+	terminatorSweep := waxing != (cos > 0)
+
+	top, bottom := clockCentreY+moonOffset-moonRadius, clockCentreY+moonOffset+moonRadius
+	io.WriteString(w, `<g id="moon-phase">`)
+	fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%d" %s/>`, clockCentreX, clockCentreY+moonOffset, moonRadius, styles.attr("moon"))
+	fmt.Fprintf(w, `<path d="M %d %d A %d %d 0 0 %d %d %d A %.3f %d 0 0 %d %d %d Z" %s/>`,
+		clockCentreX, top,
+		moonRadius, moonRadius, sweepFlag(waxing), clockCentreX, bottom,
+		moonRadius*math.Abs(cos), moonRadius, sweepFlag(terminatorSweep), clockCentreX, top,
+		styles.attr("moon-light"))
+	io.WriteString(w, `</g>`)
 }
 
-func hourHand(w io.Writer, t time.Time) {
-	p := makeHand(cf.HourHandPoint(t), hourHandLength)
-	fmt.Fprintf(w, `<line x1="150" y1="150" x2="%.3f" y2="%.3f" style="fill:none;stroke:#000;stroke-width:3px;"/>`, p.X, p.Y)
+// sweepFlag is an SVG arc's sweep-flag, which is 1 for arcs drawn clockwise.
+func sweepFlag(clockwise bool) int {
+	if clockwise {
+		return 1
+	}
+	return 0
 }
 
+// makeHand turns the unit vector of a hand into the SVG coordinates of its tip: scaled to the
+// hand's length, flipped because SVG's y axis points down, and moved to the middle of the clock.
 func makeHand(p cf.Point, length float64) cf.Point {
-	p = cf.Point{X: p.X * length, Y: p.Y * length}
-	p = cf.Point{X: p.X, Y: -p.Y}
-	return cf.Point{X: p.X + clockCentreX, Y: p.Y + clockCentreY}
+	toSVG := linalg.Scale(length, -length).Then(linalg.Translate(clockCentreX, clockCentreY))
+	return cf.Point(toSVG.Apply(linalg.Vec2(p)))
 }
 
 const svgStart = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
@@ -56,6 +98,4 @@ const svgStart = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
      viewBox="0 0 300 300"
      version="2.0">`
 
-const bezel = `<circle cx="150" cy="150" r="100" style="fill:#fff;stroke:#000;stroke-width:5px;"/>`
-
 const svgEnd = `</svg>`
@@ -3,6 +3,10 @@ package svg_test
 import (
 	"bytes"
 	"encoding/xml"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,6 +23,17 @@ type SVG struct {
 	Version string   `xml:"version,attr"`
 	Circle  Circle   `xml:"circle"`
 	Line    []Line   `xml:"line"`
+	Groups  []Group  `xml:"g"`
+}
+
+type Group struct {
+	ID     string `xml:"id,attr"`
+	Circle Circle `xml:"circle"`
+	Path   Path   `xml:"path"`
+}
+
+type Path struct {
+	D string `xml:"d,attr"`
 }
 
 type Line struct {
@@ -132,3 +147,214 @@ func simpleTime(hours, minutes, seconds int) time.Time {
 func testName(t time.Time) string {
 	return t.Format("15:04:05")
 }
+
+func TestSVGWriterHideSeconds(t *testing.T) {
+	b := bytes.Buffer{}
+	WriteWithOptions(&b, simpleTime(0, 0, 0), Options{HideSeconds: true})
+
+	svg := SVG{}
+	xml.Unmarshal(b.Bytes(), &svg)
+
+	secondHand := Line{150, 150, 150, 60}
+	if containsLine(secondHand, svg.Line) {
+		t.Errorf("Expected not to find the second hand line %+v, in the SVG lines %+v", secondHand, svg.Line)
+	}
+}
+
+func TestSVGWriterMoonPhase(t *testing.T) {
+	t.Run("is left out unless asked for", func(t *testing.T) {
+		b := bytes.Buffer{}
+		Write(&b, simpleTime(0, 0, 0))
+
+		svg := SVG{}
+		xml.Unmarshal(b.Bytes(), &svg)
+
+		if len(svg.Groups) != 0 {
+			t.Errorf("did not expect any groups, got %+v", svg.Groups)
+		}
+	})
+
+	// The lit part of the Moon is drawn as an arc around its edge from top to bottom, then the
+	// terminator back up again: on the right while it waxes, on the left while it wanes, and
+	// bulging out past the middle as it becomes gibbous.
+	cases := []struct {
+		name            string
+		time            time.Time
+		edgeSweep       int
+		terminatorSweep int
+		terminatorWidth float64
+	}{
+		{"new moon", time.Date(2024, time.January, 11, 11, 57, 0, 0, time.UTC), 1, 0, 15},
+		{"waxing crescent", time.Date(2024, time.January, 14, 12, 0, 0, 0, time.UTC), 1, 0, 10},
+		{"waxing gibbous", time.Date(2024, time.January, 22, 0, 0, 0, 0, time.UTC), 1, 1, 10},
+		{"waning gibbous", time.Date(2024, time.January, 29, 12, 0, 0, 0, time.UTC), 0, 0, 10},
+		{"waning crescent", time.Date(2024, time.February, 6, 12, 0, 0, 0, time.UTC), 0, 1, 10},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := bytes.Buffer{}
+			WriteWithOptions(&b, c.time, Options{MoonPhase: true})
+
+			svg := SVG{}
+			if err := xml.Unmarshal(b.Bytes(), &svg); err != nil {
+				t.Fatalf("could not parse the SVG, %v", err)
+			}
+			if len(svg.Groups) != 1 || svg.Groups[0].ID != "moon-phase" {
+				t.Fatalf("expected one moon-phase group, got %+v", svg.Groups)
+			}
+
+			moon := svg.Groups[0]
+			if want := (Circle{150, 195, 15}); moon.Circle != want {
+				t.Errorf("got moon %+v want %+v", moon.Circle, want)
+			}
+
+			var edgeSweep, terminatorSweep int
+			var terminatorWidth float64
+			_, err := fmt.Sscanf(moon.Path.D, "M 150 180 A 15 15 0 0 %d 150 210 A %g 15 0 0 %d 150 180 Z", &edgeSweep, &terminatorWidth, &terminatorSweep)
+			if err != nil {
+				t.Fatalf("could not read the lit part of the Moon from %q, %v", moon.Path.D, err)
+			}
+
+			if edgeSweep != c.edgeSweep || terminatorSweep != c.terminatorSweep {
+				t.Errorf("got sweeps %d and %d want %d and %d", edgeSweep, terminatorSweep, c.edgeSweep, c.terminatorSweep)
+			}
+			if math.Abs(terminatorWidth-c.terminatorWidth) > 3 {
+				t.Errorf("got terminator %g wide want about %g", terminatorWidth, c.terminatorWidth)
+			}
+		})
+	}
+}
+
+// BenchmarkSVGWriter is a server rendering clocks at many different times.
+func BenchmarkSVGWriter(b *testing.B) {
+	start := time.Date(1337, time.January, 1, 0, 0, 0, 0, time.UTC)
+	b.ReportAllocs()
+
+	var buf bytes.Buffer
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		Write(&buf, start.Add(time.Duration(i)*time.Second))
+	}
+}
+
+type StyledSVG struct {
+	XMLName  xml.Name        `xml:"svg"`
+	Style    string          `xml:"style"`
+	Elements []StyledElement `xml:",any"`
+}
+
+type StyledElement struct {
+	XMLName  xml.Name
+	Class    string          `xml:"class,attr"`
+	Style    string          `xml:"style,attr"`
+	Elements []StyledElement `xml:",any"`
+}
+
+// drawn returns every element of svg which draws part of the clock, in the order they are drawn.
+func (svg StyledSVG) drawn() []StyledElement {
+	var drawn []StyledElement
+	var walk func(elements []StyledElement)
+	walk = func(elements []StyledElement) {
+		for _, e := range elements {
+			if e.XMLName.Local == "g" {
+				walk(e.Elements)
+				continue
+			}
+			drawn = append(drawn, e)
+		}
+	}
+	walk(svg.Elements)
+	return drawn
+}
+
+func TestSVGWriterThemes(t *testing.T) {
+	write := func(t *testing.T, opts Options) StyledSVG {
+		t.Helper()
+		b := bytes.Buffer{}
+		WriteWithOptions(&b, simpleTime(0, 0, 0), opts)
+
+		svg := StyledSVG{}
+		if err := xml.Unmarshal(b.Bytes(), &svg); err != nil {
+			t.Fatalf("could not parse the SVG, %v", err)
+		}
+		return svg
+	}
+
+	t.Run("gives every part of the clock a class instead of a style", func(t *testing.T) {
+		svg := write(t, Options{MoonPhase: true})
+
+		var classes []string
+		for _, e := range svg.drawn() {
+			if e.Style != "" {
+				t.Errorf("%s %s has the style %q", e.XMLName.Local, e.Class, e.Style)
+			}
+			classes = append(classes, e.Class)
+		}
+
+		want := []string{"bezel", "moon", "moon-light", "second-hand", "minute-hand", "hour-hand"}
+		if !slices.Equal(classes, want) {
+			t.Errorf("got classes %q want %q", classes, want)
+		}
+	})
+
+	cases := []struct {
+		name  string
+		theme Theme
+		want  []string
+	}{
+		{
+			"light by default",
+			Theme{},
+			[]string{".bezel{fill:#fff;stroke:#000;stroke-width:5px;}", ".hour-hand{fill:none;stroke:#000;stroke-width:3px;}", ".second-hand{fill:none;stroke:#f00;stroke-width:3px;}"},
+		},
+		{
+			"dark",
+			DarkTheme,
+			[]string{".bezel{fill:#111;stroke:#ccc;stroke-width:5px;}", ".hour-hand{fill:none;stroke:#eee;stroke-width:3px;}", ".second-hand{fill:none;stroke:#f55;stroke-width:3px;}"},
+		},
+		{
+			"custom, with the light theme's colours where it has none",
+			Theme{Face: "ivory", SecondHand: "rebeccapurple"},
+			[]string{".bezel{fill:ivory;stroke:#000;stroke-width:5px;}", ".hour-hand{fill:none;stroke:#000;stroke-width:3px;}", ".second-hand{fill:none;stroke:rebeccapurple;stroke-width:3px;}"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run("styles the classes with the theme: "+c.name, func(t *testing.T) {
+			svg := write(t, Options{Theme: c.theme})
+
+			for _, rule := range c.want {
+				if !strings.Contains(svg.Style, rule) {
+					t.Errorf("expected to find %q in the style %q", rule, svg.Style)
+				}
+			}
+		})
+	}
+
+	t.Run("styles every part inline when asked to", func(t *testing.T) {
+		svg := write(t, Options{Theme: Theme{SecondHand: "#0f0"}, InlineStyles: true})
+
+		if svg.Style != "" {
+			t.Errorf("did not expect a style block, got %q", svg.Style)
+		}
+
+		var styles []string
+		for _, e := range svg.drawn() {
+			if e.Class != "" {
+				t.Errorf("%s has the class %q", e.XMLName.Local, e.Class)
+			}
+			styles = append(styles, e.Style)
+		}
+
+		want := []string{
+			"fill:#fff;stroke:#000;stroke-width:5px;",
+			"fill:none;stroke:#0f0;stroke-width:3px;",
+			"fill:none;stroke:#000;stroke-width:3px;",
+			"fill:none;stroke:#000;stroke-width:3px;",
+		}
+		if !slices.Equal(styles, want) {
+			t.Errorf("got styles %q want %q", styles, want)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package svg
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Theme is the palette an analogue clock is drawn with. Its colours can be anything CSS accepts
+// as a colour, and any left empty are taken from LightTheme.
+type Theme struct {
+	Face       string
+	Bezel      string
+	Hands      string
+	SecondHand string
+	Moon       string
+	MoonLight  string
+}
+
+var (
+	// LightTheme is black hands on a white face, and is what clocks are drawn with by default.
+	LightTheme = Theme{Face: "#fff", Bezel: "#000", Hands: "#000", SecondHand: "#f00", Moon: "#333", MoonLight: "#ffd"}
+	// DarkTheme is light hands on a black face.
+	DarkTheme = Theme{Face: "#111", Bezel: "#ccc", Hands: "#eee", SecondHand: "#f55", Moon: "#333", MoonLight: "#ffd"}
+)
+
+// classes are the CSS classes given to the parts of an analogue clock, in the order they are
+// written to its style block.
+var classes = []string{"bezel", "hour-hand", "minute-hand", "second-hand", "moon", "moon-light"}
+
+// stylesheet is the style of each part of a clock drawn in a theme.
+type stylesheet struct {
+	styles map[string]string
+	inline bool
+}
+
+func newStylesheet(theme Theme, inline bool) stylesheet {
+	theme = Theme{
+		Face:       cmp.Or(theme.Face, LightTheme.Face),
+		Bezel:      cmp.Or(theme.Bezel, LightTheme.Bezel),
+		Hands:      cmp.Or(theme.Hands, LightTheme.Hands),
+		SecondHand: cmp.Or(theme.SecondHand, LightTheme.SecondHand),
+		Moon:       cmp.Or(theme.Moon, LightTheme.Moon),
+		MoonLight:  cmp.Or(theme.MoonLight, LightTheme.MoonLight),
+	}
+
+	hand := func(colour string) string {
+		return fmt.Sprintf("fill:none;stroke:%s;stroke-width:3px;", colour)
+	}
+	return stylesheet{
+		styles: map[string]string{
+			"bezel":       fmt.Sprintf("fill:%s;stroke:%s;stroke-width:5px;", theme.Face, theme.Bezel),
+			"hour-hand":   hand(theme.Hands),
+			"minute-hand": hand(theme.Hands),
+			"second-hand": hand(theme.SecondHand),
+			"moon":        fmt.Sprintf("fill:%s;stroke:none;", theme.Moon),
+			"moon-light":  fmt.Sprintf("fill:%s;stroke:none;", theme.MoonLight),
+		},
+		inline: inline,
+	}
+}
+
+// writeTo writes the style block giving each class its style. There isn't one when the styles
+// are inline.
+func (s stylesheet) writeTo(w io.Writer) {
+	if s.inline {
+		return
+	}
+
+	var css strings.Builder
+	for _, class := range classes {
+		fmt.Fprintf(&css, ".%s{%s}", class, s.styles[class])
+	}
+	fmt.Fprintf(w, `<style>%s</style>`, css.String())
+}
+
+// attr is the attribute styling an element as class: the class itself, or its style when the
+// styles are inline.
+func (s stylesheet) attr(class string) string {
+	if s.inline {
+		return fmt.Sprintf(`style="%s"`, s.styles[class])
+	}
+	return fmt.Sprintf(`class="%s"`, class)
+}
@@ -0,0 +1,82 @@
+// Package epsilon compares floating point numbers, and points made of them, in tests. Two floats
+// which are mathematically the same often differ in their last few bits, so they are compared
+// to within a tolerance rather than with ==.
+package epsilon
+
+import (
+	"math"
+	"testing"
+)
+
+// DefaultTolerance is close enough for the clock's angles and points.
+const DefaultTolerance = 1e-7
+
+// XY is any 2D point or vector type, such as clockface.Point or linalg.Vec2.
+type XY interface {
+	~struct{ X, Y float64 }
+}
+
+// Checker compares values to within its tolerance.
+type Checker struct {
+	Tolerance float64
+}
+
+// Default is a Checker with the DefaultTolerance.
+var Default = Within(DefaultTolerance)
+
+// Within returns a Checker which lets values differ by up to tolerance.
+func Within(tolerance float64) Checker {
+	return Checker{Tolerance: tolerance}
+}
+
+// EqualFloats reports whether a and b are within the tolerance of each other.
+func (c Checker) EqualFloats(a, b float64) bool {
+	return math.Abs(a-b) <= c.Tolerance
+}
+
+// Float fails the test if got isn't within the tolerance of want, and reports whether it was.
+func (c Checker) Float(t testing.TB, got, want float64) bool {
+	t.Helper()
+	if c.EqualFloats(got, want) {
+		return true
+	}
+	t.Errorf("got %v, want %v (delta %g, tolerance %g)", got, want, got-want, c.Tolerance)
+	return false
+}
+
+// Float compares got and want with the Default Checker.
+func Float(t testing.TB, got, want float64) bool {
+	t.Helper()
+	return Default.Float(t, got, want)
+}
+
+// distance is how far apart a and b are.
+func distance[V XY](a, b V) (dx, dy, d float64) {
+	p, q := struct{ X, Y float64 }(a), struct{ X, Y float64 }(b)
+	dx, dy = p.X-q.X, p.Y-q.Y
+	return dx, dy, math.Hypot(dx, dy)
+}
+
+// EqualVectors reports whether a and b are within the tolerance of each other, measured as the
+// distance between them rather than each coordinate separately.
+func EqualVectors[V XY](c Checker, a, b V) bool {
+	_, _, d := distance(a, b)
+	return d <= c.Tolerance
+}
+
+// VectorWithin fails the test if got isn't within c's tolerance of want, and reports whether it was.
+func VectorWithin[V XY](c Checker, t testing.TB, got, want V) bool {
+	t.Helper()
+	dx, dy, d := distance(got, want)
+	if d <= c.Tolerance {
+		return true
+	}
+	t.Errorf("got %v, want %v (delta {%g %g}, distance %g, tolerance %g)", got, want, dx, dy, d, c.Tolerance)
+	return false
+}
+
+// Vector compares got and want with the Default Checker.
+func Vector[V XY](t testing.TB, got, want V) bool {
+	t.Helper()
+	return VectorWithin(Default, t, got, want)
+}
@@ -0,0 +1,101 @@
+package epsilon_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/math/vFinal/epsilon"
+)
+
+type point struct {
+	X float64
+	Y float64
+}
+
+// SpyTB records failures instead of failing the real test.
+type SpyTB struct {
+	testing.TB
+	Errors []string
+}
+
+func (s *SpyTB) Helper() {}
+
+func (s *SpyTB) Errorf(format string, args ...any) {
+	s.Errors = append(s.Errors, fmt.Sprintf(format, args...))
+}
+
+func TestFloat(t *testing.T) {
+	t.Run("floats within the tolerance are equal", func(t *testing.T) {
+		spy := &SpyTB{}
+
+		if !epsilon.Float(spy, 0.1+0.2, 0.3) {
+			t.Error("expected 0.1+0.2 to roughly equal 0.3")
+		}
+		assertNoFailures(t, spy)
+	})
+
+	t.Run("failures show the delta", func(t *testing.T) {
+		spy := &SpyTB{}
+
+		if epsilon.Float(spy, 1.5, 1) {
+			t.Error("didn't expect 1.5 to roughly equal 1")
+		}
+		assertFailures(t, spy, "got 1.5, want 1 (delta 0.5, tolerance 1e-07)")
+	})
+
+	t.Run("the tolerance can be changed", func(t *testing.T) {
+		spy := &SpyTB{}
+
+		epsilon.Within(0.5).Float(spy, 1.5, 1)
+		assertNoFailures(t, spy)
+
+		epsilon.Within(0.01).Float(spy, 1.5, 1)
+		assertFailures(t, spy, "got 1.5, want 1 (delta 0.5, tolerance 0.01)")
+	})
+}
+
+func TestVector(t *testing.T) {
+	t.Run("points within the tolerance are equal", func(t *testing.T) {
+		spy := &SpyTB{}
+
+		if !epsilon.Vector(spy, point{math.Sin(math.Pi), math.Cos(math.Pi)}, point{0, -1}) {
+			t.Error("expected the point at pi radians to roughly be {0 -1}")
+		}
+		assertNoFailures(t, spy)
+	})
+
+	t.Run("the distance between points is compared, not each coordinate", func(t *testing.T) {
+		c := epsilon.Within(1)
+
+		if !epsilon.EqualVectors(c, point{0.7, 0.7}, point{0, 0}) {
+			t.Error("expected points 0.99 apart to be within 1")
+		}
+		if epsilon.EqualVectors(c, point{0.8, 0.8}, point{0, 0}) {
+			t.Error("didn't expect points 1.13 apart to be within 1, even though each coordinate is")
+		}
+	})
+
+	t.Run("failures show the delta and distance", func(t *testing.T) {
+		spy := &SpyTB{}
+
+		if epsilon.VectorWithin(epsilon.Within(0.1), spy, point{3, 4}, point{0, 0}) {
+			t.Error("didn't expect {3 4} to roughly equal {0 0}")
+		}
+		assertFailures(t, spy, "got {3 4}, want {0 0} (delta {3 4}, distance 5, tolerance 0.1)")
+	})
+}
+
+func assertNoFailures(t testing.TB, spy *SpyTB) {
+	t.Helper()
+	if len(spy.Errors) != 0 {
+		t.Errorf("didn't expect failures, got %q", spy.Errors)
+	}
+}
+
+func assertFailures(t testing.TB, spy *SpyTB, want ...string) {
+	t.Helper()
+	if fmt.Sprint(spy.Errors) != fmt.Sprint(want) {
+		t.Errorf("got failures %q want %q", spy.Errors, want)
+	}
+}
@@ -0,0 +1,133 @@
+// Package linalg does the small amount of linear algebra needed to draw in 2D: vectors, and
+// affine transforms that scale, rotate, flip and move them.
+package linalg
+
+import (
+	"fmt"
+	"math"
+)
+
+// Vec2 is a vector, or a point, in 2D.
+type Vec2 struct {
+	X float64
+	Y float64
+}
+
+// Add returns v + w.
+func (v Vec2) Add(w Vec2) Vec2 {
+	return Vec2{v.X + w.X, v.Y + w.Y}
+}
+
+// Sub returns v - w.
+func (v Vec2) Sub(w Vec2) Vec2 {
+	return Vec2{v.X - w.X, v.Y - w.Y}
+}
+
+// Scale returns v with both components multiplied by k.
+func (v Vec2) Scale(k float64) Vec2 {
+	return Vec2{v.X * k, v.Y * k}
+}
+
+// Dot returns the dot product of v and w.
+func (v Vec2) Dot(w Vec2) float64 {
+	return v.X*w.X + v.Y*w.Y
+}
+
+// Len returns the length of v.
+func (v Vec2) Len() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+// Matrix is an affine transform, the 2x3 matrix
+//
+//	| A C E |
+//	| B D F |
+//
+// which maps (x, y) to (Ax + Cy + E, Bx + Dy + F). The fields are in the same order as the
+// arguments of SVG's matrix() transform.
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Identity returns the transform that leaves points where they are.
+func Identity() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+// Translate returns the transform that moves points by (dx, dy).
+func Translate(dx, dy float64) Matrix {
+	return Matrix{A: 1, D: 1, E: dx, F: dy}
+}
+
+// Scale returns the transform that stretches points by sx horizontally and sy vertically. A
+// negative factor flips points over that axis.
+func Scale(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}
+}
+
+// Rotate returns the transform that turns points anticlockwise about the origin by theta radians,
+// with y pointing up.
+func Rotate(theta float64) Matrix {
+	sin, cos := math.Sincos(theta)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// Apply transforms v.
+func (m Matrix) Apply(v Vec2) Vec2 {
+	return Vec2{
+		X: m.A*v.X + m.C*v.Y + m.E,
+		Y: m.B*v.X + m.D*v.Y + m.F,
+	}
+}
+
+// Then returns the transform that does m and then n.
+func (m Matrix) Then(n Matrix) Matrix {
+	return Matrix{
+		A: n.A*m.A + n.C*m.B,
+		B: n.B*m.A + n.D*m.B,
+		C: n.A*m.C + n.C*m.D,
+		D: n.B*m.C + n.D*m.D,
+		E: n.A*m.E + n.C*m.F + n.E,
+		F: n.B*m.E + n.D*m.F + n.F,
+	}
+}
+
+// Determinant returns how much m scales areas by. It is negative if m flips points over.
+func (m Matrix) Determinant() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// Inverse returns the transform that undoes m. It returns false if there isn't one, which
+// happens when m squashes everything onto a line or a point.
+func (m Matrix) Inverse() (Matrix, bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return Matrix{}, false
+	}
+
+	inv := Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+	}
+	inv.E = -(inv.A*m.E + inv.C*m.F)
+	inv.F = -(inv.B*m.E + inv.D*m.F)
+
+	return inv, true
+}
+
+// ApproxEqual reports whether every field of m is within epsilon of n's.
+func (m Matrix) ApproxEqual(n Matrix, epsilon float64) bool {
+	for _, d := range []float64{m.A - n.A, m.B - n.B, m.C - n.C, m.D - n.D, m.E - n.E, m.F - n.F} {
+		if math.Abs(d) > epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats m as an SVG transform attribute value.
+func (m Matrix) String() string {
+	return fmt.Sprintf("matrix(%g %g %g %g %g %g)", m.A, m.B, m.C, m.D, m.E, m.F)
+}
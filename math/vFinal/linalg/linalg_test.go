@@ -0,0 +1,115 @@
+package linalg
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/quii/learn-go-with-tests/math/vFinal/epsilon"
+)
+
+const tolerance = 1e-9
+
+func TestTransforms(t *testing.T) {
+	cases := []struct {
+		name      string
+		transform Matrix
+		in, want  Vec2
+	}{
+		{"identity", Identity(), Vec2{3, 4}, Vec2{3, 4}},
+		{"translate", Translate(10, -5), Vec2{3, 4}, Vec2{13, -1}},
+		{"scale", Scale(2, 3), Vec2{3, 4}, Vec2{6, 12}},
+		{"flip over the x axis", Scale(1, -1), Vec2{3, 4}, Vec2{3, -4}},
+		{"quarter turn", Rotate(math.Pi / 2), Vec2{1, 0}, Vec2{0, 1}},
+		{"half turn", Rotate(math.Pi), Vec2{3, 4}, Vec2{-3, -4}},
+		{"scale then translate", Scale(2, 2).Then(Translate(1, 1)), Vec2{3, 4}, Vec2{7, 9}},
+		{"translate then scale", Translate(1, 1).Then(Scale(2, 2)), Vec2{3, 4}, Vec2{8, 10}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := c.transform.Apply(c.in)
+			assertVec(t, got, c.want)
+		})
+	}
+}
+
+func TestVec2(t *testing.T) {
+	v, w := Vec2{3, 4}, Vec2{1, -2}
+
+	assertVec(t, v.Add(w), Vec2{4, 2})
+	assertVec(t, v.Sub(w), Vec2{2, 6})
+	assertVec(t, v.Scale(-2), Vec2{-6, -8})
+
+	if got := v.Dot(w); got != -5 {
+		t.Errorf("got dot product %v want -5", got)
+	}
+	if got := v.Len(); got != 5 {
+		t.Errorf("got length %v want 5", got)
+	}
+}
+
+func TestInverse(t *testing.T) {
+	t.Run("a transform then its inverse is the identity", func(t *testing.T) {
+		assertion := func(tx, ty, sx, sy, theta float64) bool {
+			m := transformFrom(tx, ty, sx, sy, theta)
+			inv, ok := m.Inverse()
+			if !ok {
+				return false
+			}
+			return m.Then(inv).ApproxEqual(Identity(), tolerance) && inv.Then(m).ApproxEqual(Identity(), tolerance)
+		}
+
+		if err := quick.Check(assertion, &quick.Config{MaxCount: 1000}); err != nil {
+			t.Error("failed checks", err)
+		}
+	})
+
+	t.Run("applying a composed transform is applying each in turn", func(t *testing.T) {
+		assertion := func(tx, ty, sx, sy, theta, x, y float64) bool {
+			m := transformFrom(tx, ty, sx, sy, theta)
+			n := transformFrom(ty, tx, sy, sx, -theta)
+			v := Vec2{math.Mod(x, 1000), math.Mod(y, 1000)}
+
+			got := m.Then(n).Apply(v)
+			want := n.Apply(m.Apply(v))
+			return epsilon.EqualVectors(epsilon.Within(tolerance*1e3), got, want)
+		}
+
+		if err := quick.Check(assertion, &quick.Config{MaxCount: 1000}); err != nil {
+			t.Error("failed checks", err)
+		}
+	})
+
+	t.Run("squashing transforms have no inverse", func(t *testing.T) {
+		if _, ok := Scale(0, 1).Inverse(); ok {
+			t.Error("expected no inverse")
+		}
+	})
+}
+
+func TestString(t *testing.T) {
+	got := Scale(2, -2).Then(Translate(150, 150)).String()
+	want := "matrix(2 0 0 -2 150 150)"
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+// transformFrom turns arbitrary numbers from testing/quick into a reasonably sized transform
+// that scales, rotates and moves points. Scale factors are kept away from zero so it has an inverse.
+func transformFrom(tx, ty, sx, sy, theta float64) Matrix {
+	scale := func(f float64) float64 {
+		return math.Copysign(0.1+math.Mod(math.Abs(f), 10), f)
+	}
+
+	return Scale(scale(sx), scale(sy)).
+		Then(Rotate(math.Mod(theta, 2*math.Pi))).
+		Then(Translate(math.Mod(tx, 1000), math.Mod(ty, 1000)))
+}
+
+func assertVec(t testing.TB, got, want Vec2) {
+	t.Helper()
+	epsilon.VectorWithin(epsilon.Within(tolerance), t, got, want)
+}
@@ -0,0 +1,135 @@
+// Package mock is a small toolkit for building test doubles which record the calls made to them.
+//
+// A spy embeds a *Recorder and calls Record from each of its methods. Tests can then inspect the
+// calls, or declare the calls they Expect up front and check them with AssertExpectations.
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// Call is a method call made to a test double.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s%v", c.Method, c.Args)
+}
+
+func (c Call) matches(other Call) bool {
+	return c.Method == other.Method && reflect.DeepEqual(c.Args, other.Args)
+}
+
+// Recorder records calls to a test double. It is safe to use from multiple goroutines.
+//
+// A lenient Recorder ignores calls it wasn't told to expect. A strict Recorder fails the test
+// as soon as a call is made which isn't the next one expected. The zero value is a lenient Recorder
+// which can't make assertions, handy for dummies whose calls nobody checks.
+type Recorder struct {
+	t        testing.TB
+	strict   bool
+	lock     sync.Mutex
+	calls    []Call
+	expected []Call
+}
+
+// NewRecorder creates a lenient Recorder.
+func NewRecorder(t testing.TB) *Recorder {
+	return &Recorder{t: t}
+}
+
+// NewStrictRecorder creates a Recorder which fails t on any call that isn't the next one expected.
+func NewStrictRecorder(t testing.TB) *Recorder {
+	return &Recorder{t: t, strict: true}
+}
+
+// Expect adds a call to the sequence of calls the double should receive. It returns the Recorder so
+// expectations can be chained.
+func (r *Recorder) Expect(method string, args ...any) *Recorder {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.expected = append(r.expected, Call{method, args})
+	return r
+}
+
+// Record is called by a test double's methods to register a call.
+func (r *Recorder) Record(method string, args ...any) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	call := Call{method, args}
+	position := len(r.calls)
+	r.calls = append(r.calls, call)
+
+	if !r.strict {
+		return
+	}
+
+	if position >= len(r.expected) {
+		r.t.Errorf("unexpected call %v, only expected %v", call, r.expected)
+		return
+	}
+
+	if want := r.expected[position]; !want.matches(call) {
+		r.t.Errorf("unexpected call %v at position %d, wanted %v", call, position, want)
+	}
+}
+
+// Calls returns every call recorded so far, in order.
+func (r *Recorder) Calls() []Call {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Methods returns the names of the methods called so far, in order.
+func (r *Recorder) Methods() []string {
+	var methods []string
+	for _, c := range r.Calls() {
+		methods = append(methods, c.Method)
+	}
+	return methods
+}
+
+// CallsTo returns the calls made to method, in order.
+func (r *Recorder) CallsTo(method string) []Call {
+	var calls []Call
+	for _, c := range r.Calls() {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// AssertExpectations fails the test if the expected calls were not all made in order. A strict
+// Recorder must have received exactly the expected calls, a lenient one may have had others in between.
+func (r *Recorder) AssertExpectations() {
+	r.t.Helper()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.strict {
+		if len(r.calls) < len(r.expected) {
+			r.t.Errorf("expected calls %v were not made, got %v", r.expected[len(r.calls):], r.calls)
+		}
+		return
+	}
+
+	next := 0
+	for _, call := range r.calls {
+		if next < len(r.expected) && r.expected[next].matches(call) {
+			next++
+		}
+	}
+
+	if next < len(r.expected) {
+		r.t.Errorf("expected %v to be called in order after %v, got %v", r.expected[next:], r.expected[:next], r.calls)
+	}
+}
@@ -0,0 +1,138 @@
+package mock_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/mocking/mock"
+)
+
+// spyTB lets us check the failures reported by a Recorder without failing this test.
+type spyTB struct {
+	testing.TB
+	failures []string
+}
+
+func (s *spyTB) Helper() {}
+
+func (s *spyTB) Errorf(format string, args ...any) {
+	s.failures = append(s.failures, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("records calls in order", func(t *testing.T) {
+		r := mock.NewRecorder(t)
+
+		r.Record("Write", "3")
+		r.Record("Sleep")
+
+		want := []mock.Call{{Method: "Write", Args: []any{"3"}}, {Method: "Sleep"}}
+		if got := r.Calls(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+		assertStrings(t, r.Methods(), []string{"Write", "Sleep"})
+	})
+
+	t.Run("is safe to use concurrently", func(t *testing.T) {
+		r := mock.NewRecorder(t)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.Record("Sleep")
+			}()
+		}
+		wg.Wait()
+
+		if got := len(r.CallsTo("Sleep")); got != 100 {
+			t.Errorf("got %d calls want 100", got)
+		}
+	})
+}
+
+func TestStrictRecorder(t *testing.T) {
+	t.Run("passes when exactly the expected calls are made", func(t *testing.T) {
+		tb := &spyTB{}
+		r := mock.NewStrictRecorder(tb).Expect("Write", "3").Expect("Sleep")
+
+		r.Record("Write", "3")
+		r.Record("Sleep")
+		r.AssertExpectations()
+
+		assertStrings(t, tb.failures, nil)
+	})
+
+	t.Run("fails on a call made out of order", func(t *testing.T) {
+		tb := &spyTB{}
+		r := mock.NewStrictRecorder(tb).Expect("Write").Expect("Sleep")
+
+		r.Record("Sleep")
+
+		assertStrings(t, tb.failures, []string{"unexpected call Sleep[] at position 0, wanted Write[]"})
+	})
+
+	t.Run("fails on a call with the wrong arguments", func(t *testing.T) {
+		tb := &spyTB{}
+		r := mock.NewStrictRecorder(tb).Expect("Start", 3)
+
+		r.Record("Start", 5)
+
+		assertStrings(t, tb.failures, []string{"unexpected call Start[5] at position 0, wanted Start[3]"})
+	})
+
+	t.Run("fails on a call that wasn't expected at all", func(t *testing.T) {
+		tb := &spyTB{}
+		r := mock.NewStrictRecorder(tb).Expect("Sleep")
+
+		r.Record("Sleep")
+		r.Record("Sleep")
+
+		assertStrings(t, tb.failures, []string{"unexpected call Sleep[], only expected [Sleep[]]"})
+	})
+
+	t.Run("fails when expected calls are missing", func(t *testing.T) {
+		tb := &spyTB{}
+		r := mock.NewStrictRecorder(tb).Expect("Write").Expect("Sleep")
+
+		r.Record("Write")
+		r.AssertExpectations()
+
+		assertStrings(t, tb.failures, []string{"expected calls [Sleep[]] were not made, got [Write[]]"})
+	})
+}
+
+func TestLenientRecorder(t *testing.T) {
+	t.Run("ignores calls which weren't expected", func(t *testing.T) {
+		tb := &spyTB{}
+		r := mock.NewRecorder(tb).Expect("Start", 3).Expect("Finish", "Ruth")
+
+		r.Record("Start", 3)
+		r.Record("Sleep")
+		r.Record("Finish", "Ruth")
+		r.AssertExpectations()
+
+		assertStrings(t, tb.failures, nil)
+	})
+
+	t.Run("fails when expected calls are made out of order", func(t *testing.T) {
+		tb := &spyTB{}
+		r := mock.NewRecorder(tb).Expect("Start", 3).Expect("Finish", "Ruth")
+
+		r.Record("Finish", "Ruth")
+		r.Record("Start", 3)
+		r.AssertExpectations()
+
+		assertStrings(t, tb.failures, []string{"expected [Finish[Ruth]] to be called in order after [Start[3]], got [Finish[Ruth] Start[3]]"})
+	})
+}
+
+func assertStrings(t testing.TB, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
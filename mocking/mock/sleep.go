@@ -0,0 +1,119 @@
+package mock
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// SpyTime stands in for time.Sleep, recording every duration it is asked to sleep for without
+// sleeping at all. Pass its Sleep method wherever a func(time.Duration) is wanted.
+type SpyTime struct {
+	lock  sync.Mutex
+	slept []time.Duration
+}
+
+// Sleep records duration.
+func (s *SpyTime) Sleep(duration time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.slept = append(s.slept, duration)
+}
+
+// Durations returns the durations asked for, in order.
+func (s *SpyTime) Durations() []time.Duration {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return slices.Clone(s.slept)
+}
+
+// Total returns how long would have been spent sleeping.
+func (s *SpyTime) Total() time.Duration {
+	var total time.Duration
+	for _, d := range s.Durations() {
+		total += d
+	}
+	return total
+}
+
+// FakeClock is a clock which only moves when it is told to. Its Sleep method records durations
+// like SpyTime's and moves the clock on by them, plus Oversleep, to stand in for a real sleep
+// which tends to wake up a little late. Use Advance to pretend some work took a while.
+type FakeClock struct {
+	SpyTime
+	Oversleep time.Duration
+
+	lock sync.Mutex
+	now  time.Time
+}
+
+// NewFakeClock creates a FakeClock which starts at start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// Sleep records duration and moves the clock on by it and Oversleep.
+func (c *FakeClock) Sleep(duration time.Duration) {
+	c.SpyTime.Sleep(duration)
+	c.Advance(duration + c.Oversleep)
+}
+
+// Advance moves the clock on by duration.
+func (c *FakeClock) Advance(duration time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(duration)
+}
+
+// SpySleeper is a Sleeper, with a Sleep method taking no arguments, which counts how many
+// times it is asked to sleep.
+type SpySleeper struct {
+	lock  sync.Mutex
+	calls int
+}
+
+// Sleep counts the call.
+func (s *SpySleeper) Sleep() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.calls++
+}
+
+// Calls returns how many times Sleep was called.
+func (s *SpySleeper) Calls() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.calls
+}
+
+// AssertSleeps checks spy was asked to sleep for exactly the durations in want, in order.
+func AssertSleeps(t testing.TB, spy *SpyTime, want ...time.Duration) {
+	t.Helper()
+	if got := spy.Durations(); !slices.Equal(got, want) {
+		t.Errorf("should have slept for %v but slept for %v", want, got)
+	}
+}
+
+// AssertTotalSleep checks the durations spy was asked to sleep for add up to want.
+func AssertTotalSleep(t testing.TB, spy *SpyTime, want time.Duration) {
+	t.Helper()
+	if got := spy.Total(); got != want {
+		t.Errorf("should have slept for %v in total but slept for %v", want, got)
+	}
+}
+
+// AssertSleepCount checks spy was asked to sleep want times.
+func AssertSleepCount(t testing.TB, spy *SpySleeper, want int) {
+	t.Helper()
+	if got := spy.Calls(); got != want {
+		t.Errorf("should have slept %d times but slept %d times", want, got)
+	}
+}
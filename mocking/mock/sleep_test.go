@@ -0,0 +1,82 @@
+package mock_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/mocking/mock"
+)
+
+func TestSpyTime(t *testing.T) {
+	t.Run("records durations in order without sleeping", func(t *testing.T) {
+		spy := &mock.SpyTime{}
+
+		start := time.Now()
+		spy.Sleep(time.Hour)
+		spy.Sleep(2 * time.Second)
+
+		if time.Since(start) > time.Second {
+			t.Error("spy really slept")
+		}
+		mock.AssertSleeps(t, spy, time.Hour, 2*time.Second)
+		mock.AssertTotalSleep(t, spy, time.Hour+2*time.Second)
+	})
+
+	t.Run("assertions report what was slept", func(t *testing.T) {
+		spy := &mock.SpyTime{}
+		spy.Sleep(time.Second)
+		tb := &spyTB{}
+
+		mock.AssertSleeps(tb, spy, time.Minute)
+		mock.AssertTotalSleep(tb, spy, time.Minute)
+
+		if len(tb.failures) != 2 {
+			t.Errorf("got failures %v, want 2", tb.failures)
+		}
+	})
+
+	t.Run("is safe to use from multiple goroutines", func(t *testing.T) {
+		spy := &mock.SpyTime{}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				spy.Sleep(time.Millisecond)
+			}()
+		}
+		wg.Wait()
+
+		mock.AssertTotalSleep(t, spy, 50*time.Millisecond)
+	})
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	clock := mock.NewFakeClock(start)
+	clock.Oversleep = 10 * time.Millisecond
+
+	clock.Sleep(time.Second)
+	clock.Advance(time.Minute)
+
+	if got, want := clock.Now(), start.Add(time.Minute+time.Second+10*time.Millisecond); !got.Equal(want) {
+		t.Errorf("got time %v want %v", got, want)
+	}
+	mock.AssertSleeps(t, &clock.SpyTime, time.Second)
+}
+
+func TestSpySleeper(t *testing.T) {
+	spy := &mock.SpySleeper{}
+	spy.Sleep()
+	spy.Sleep()
+
+	mock.AssertSleepCount(t, spy, 2)
+
+	tb := &spyTB{}
+	mock.AssertSleepCount(tb, spy, 3)
+	if len(tb.failures) != 1 {
+		t.Errorf("got failures %v, want 1", tb.failures)
+	}
+}
@@ -0,0 +1,11 @@
+package main
+
+import "net/http"
+
+// CountdownHandler streams a countdown to the client, each number arriving as it is counted.
+func CountdownHandler(sleeper Sleeper) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "text/plain; charset=utf-8")
+		Countdown(w, sleeper)
+	}
+}
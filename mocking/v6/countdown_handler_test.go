@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/mocking/mock"
+)
+
+func TestCountdownFlushes(t *testing.T) {
+	t.Run("flushes after every line", func(t *testing.T) {
+		spy := &SpyFlushingWriter{SpyCountdownOperations{mock.NewStrictRecorder(t).
+			Expect(write).Expect(flushCall).Expect(sleep).
+			Expect(write).Expect(flushCall).Expect(sleep).
+			Expect(write).Expect(flushCall).Expect(sleep).
+			Expect(write).Expect(flushCall),
+		}}
+
+		Countdown(spy, spy)
+
+		spy.AssertExpectations()
+	})
+
+	t.Run("flushes writers whose Flush returns an error", func(t *testing.T) {
+		var out bytes.Buffer
+		buffered := bufio.NewWriter(&out)
+
+		Countdown(buffered, &SpyCountdownOperations{mock.NewRecorder(t)})
+
+		if got := out.String(); got != "3\n2\n1\nGo!" {
+			t.Errorf("got %q written through the buffer, want the whole countdown", got)
+		}
+	})
+}
+
+func TestCountdownHandler(t *testing.T) {
+	sleeper := &BlockingSleeper{proceed: make(chan struct{})}
+	server := httptest.NewServer(CountdownHandler(sleeper))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body := bufio.NewReader(res.Body)
+
+	// the countdown can't carry on until we let it, so each line must have been streamed on its own
+	for _, want := range []string{"3\n", "2\n", "1\n"} {
+		assertNextChunk(t, body, want)
+		sleeper.proceed <- struct{}{}
+	}
+
+	rest, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != finalWord {
+		t.Errorf("got %q want %q", rest, finalWord)
+	}
+}
+
+func assertNextChunk(t testing.TB, body *bufio.Reader, want string) {
+	t.Helper()
+
+	line := make(chan string, 1)
+	go func() {
+		got, _ := body.ReadString('\n')
+		line <- got
+	}()
+
+	select {
+	case got := <-line:
+		if got != want {
+			t.Fatalf("got %q want %q", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for %q, it was not flushed", want)
+	}
+}
+
+const flushCall = "flush"
+
+type SpyFlushingWriter struct {
+	SpyCountdownOperations
+}
+
+func (s *SpyFlushingWriter) Flush() {
+	s.Record(flushCall)
+}
+
+// BlockingSleeper only returns from Sleep once told to proceed.
+type BlockingSleeper struct {
+	proceed chan struct{}
+}
+
+func (b *BlockingSleeper) Sleep() {
+	<-b.proceed
+}
@@ -2,16 +2,17 @@ package main
 
 import (
 	"bytes"
-	"reflect"
 	"testing"
 	"time"
+
+	"github.com/quii/learn-go-with-tests/mocking/mock"
 )
 
 func TestCountdown(t *testing.T) {
 
 	t.Run("prints 3 to Go!", func(t *testing.T) {
 		buffer := &bytes.Buffer{}
-		Countdown(buffer, &SpyCountdownOperations{})
+		Countdown(buffer, &SpyCountdownOperations{mock.NewRecorder(t)})
 
 		got := buffer.String()
 		want := `3
@@ -25,57 +26,111 @@ Go!`
 	})
 
 	t.Run("sleep before every print", func(t *testing.T) {
-		spySleepPrinter := &SpyCountdownOperations{}
-		Countdown(spySleepPrinter, spySleepPrinter)
-
-		want := []string{
-			write,
-			sleep,
-			write,
-			sleep,
-			write,
-			sleep,
-			write,
+		spySleepPrinter := &SpyCountdownOperations{mock.NewStrictRecorder(t).
+			Expect(write).
+			Expect(sleep).
+			Expect(write).
+			Expect(sleep).
+			Expect(write).
+			Expect(sleep).
+			Expect(write),
 		}
 
-		if !reflect.DeepEqual(want, spySleepPrinter.Calls) {
-			t.Errorf("wanted calls %v got %v", want, spySleepPrinter.Calls)
-		}
+		Countdown(spySleepPrinter, spySleepPrinter)
+
+		spySleepPrinter.AssertExpectations()
 	})
 }
 
 func TestConfigurableSleeper(t *testing.T) {
 	sleepTime := 5 * time.Second
 
-	spyTime := &SpyTime{}
+	spyTime := &mock.SpyTime{}
 	sleeper := ConfigurableSleeper{sleepTime, spyTime.Sleep}
 	sleeper.Sleep()
 
-	if spyTime.durationSlept != sleepTime {
-		t.Errorf("should have slept for %v but slept for %v", sleepTime, spyTime.durationSlept)
-	}
+	mock.AssertSleeps(t, spyTime, sleepTime)
+}
+
+func TestCompensatingSleeper(t *testing.T) {
+	interval := time.Second
+	start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("sleeps for the interval when nothing else takes any time", func(t *testing.T) {
+		clock := mock.NewFakeClock(start)
+		sleeper := NewCompensatingSleeper(interval, clock.Sleep, clock.Now)
+
+		sleeper.Sleep()
+		sleeper.Sleep()
+		sleeper.Sleep()
+
+		mock.AssertSleeps(t, &clock.SpyTime, interval, interval, interval)
+	})
+
+	t.Run("takes the time spent working off the next sleep", func(t *testing.T) {
+		clock := mock.NewFakeClock(start)
+		sleeper := NewCompensatingSleeper(interval, clock.Sleep, clock.Now)
+
+		sleeper.Sleep()
+		clock.Advance(300 * time.Millisecond)
+		sleeper.Sleep()
+		clock.Advance(100 * time.Millisecond)
+		sleeper.Sleep()
+
+		mock.AssertSleeps(t, &clock.SpyTime, interval, 700*time.Millisecond, 900*time.Millisecond)
+	})
+
+	t.Run("takes oversleeping off the next sleep, keeping a steady cadence", func(t *testing.T) {
+		clock := mock.NewFakeClock(start)
+		clock.Oversleep = 50 * time.Millisecond
+		sleeper := NewCompensatingSleeper(interval, clock.Sleep, clock.Now)
+
+		for range 4 {
+			sleeper.Sleep()
+		}
+
+		mock.AssertSleeps(t, &clock.SpyTime, interval, 950*time.Millisecond, 950*time.Millisecond, 950*time.Millisecond)
+		if got, want := clock.Now(), start.Add(4*interval+clock.Oversleep); !got.Equal(want) {
+			t.Errorf("woke up last at %v want %v", got, want)
+		}
+	})
+
+	t.Run("doesn't sleep when the work took the whole interval", func(t *testing.T) {
+		clock := mock.NewFakeClock(start)
+		sleeper := NewCompensatingSleeper(interval, clock.Sleep, clock.Now)
+
+		sleeper.Sleep()
+		clock.Advance(interval)
+		sleeper.Sleep()
+
+		mock.AssertSleeps(t, &clock.SpyTime, interval)
+	})
+
+	t.Run("starts again from now rather than catching up when it falls a whole interval behind", func(t *testing.T) {
+		clock := mock.NewFakeClock(start)
+		sleeper := NewCompensatingSleeper(interval, clock.Sleep, clock.Now)
+
+		sleeper.Sleep()
+		clock.Advance(5 * interval)
+		sleeper.Sleep()
+		sleeper.Sleep()
+
+		mock.AssertSleeps(t, &clock.SpyTime, interval, interval, interval)
+	})
 }
 
 type SpyCountdownOperations struct {
-	Calls []string
+	*mock.Recorder
 }
 
 func (s *SpyCountdownOperations) Sleep() {
-	s.Calls = append(s.Calls, sleep)
+	s.Record(sleep)
 }
 
 func (s *SpyCountdownOperations) Write(p []byte) (n int, err error) {
-	s.Calls = append(s.Calls, write)
+	s.Record(write)
 	return
 }
 
 const write = "write"
 const sleep = "sleep"
-
-type SpyTime struct {
-	durationSlept time.Duration
-}
-
-func (s *SpyTime) Sleep(duration time.Duration) {
-	s.durationSlept = duration
-}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/mocking/mock"
+)
+
+func TestEvery(t *testing.T) {
+	t.Run("calls fn counting down, sleeping after each call", func(t *testing.T) {
+		spy := &SpyCountdownOperations{mock.NewRecorder(t)}
+		var got []int
+
+		err := Every(3, spy, func(i int) error {
+			got = append(got, i)
+			spy.Write(nil)
+			return nil
+		})
+
+		assertNoError(t, err)
+		assertInts(t, got, []int{3, 2, 1})
+		assertCalls(t, spy.Methods(), []string{write, sleep, write, sleep, write, sleep})
+	})
+
+	t.Run("stops without sleeping when fn errors", func(t *testing.T) {
+		spy := &SpyCountdownOperations{mock.NewRecorder(t)}
+		boom := errors.New("boom")
+
+		err := Every(3, spy, func(i int) error {
+			spy.Write(nil)
+			if i == 2 {
+				return boom
+			}
+			return nil
+		})
+
+		if err != boom {
+			t.Errorf("got error %v want %v", err, boom)
+		}
+		assertCalls(t, spy.Methods(), []string{write, sleep, write})
+	})
+}
+
+func TestPollLeague(t *testing.T) {
+	t.Run("writes the league on every tick", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"Name":"Chris","Wins":1}]`)
+		}))
+		defer server.Close()
+
+		out := &bytes.Buffer{}
+		spy := &mock.SpySleeper{}
+
+		err := PollLeague(out, server.URL+"/league", 2, spy)
+
+		assertNoError(t, err)
+		if got, want := out.String(), `[{"Name":"Chris","Wins":1}][{"Name":"Chris","Wins":1}]`; got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+		mock.AssertSleepCount(t, spy, 2)
+	})
+
+	t.Run("stops polling when the server errors", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		spy := &mock.SpySleeper{}
+
+		err := PollLeague(&bytes.Buffer{}, server.URL+"/league", 3, spy)
+
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+		if requests != 1 {
+			t.Errorf("got %d requests want 1", requests)
+		}
+		mock.AssertSleepCount(t, spy, 0)
+	})
+}
+
+func assertCalls(t testing.TB, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wanted calls %v got %v", want, got)
+	}
+}
+
+func assertInts(t testing.TB, got, want []int) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("didn't expect an error but got one, %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// PollLeague fetches the league from a PlayerServer's /league url n times, copying each response
+// to out and waiting on sleeper in between. It gives up on the first failed request.
+func PollLeague(out io.Writer, url string, n int, sleeper Sleeper) error {
+	return Every(n, sleeper, func(int) error {
+		res, err := http.Get(url)
+		if err != nil {
+			return fmt.Errorf("problem fetching league from %s, %v", url, err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status %d fetching league from %s", res.StatusCode, url)
+		}
+
+		_, err = io.Copy(out, res.Body)
+		return err
+	})
+}
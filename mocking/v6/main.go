@@ -24,16 +24,80 @@ func (c *ConfigurableSleeper) Sleep() {
 	c.sleep(c.duration)
 }
 
+// CompensatingSleeper is a Sleeper which keeps a steady cadence, waking up every interval rather
+// than sleeping for interval each time. Whatever was done since it last woke up, and however late
+// the last sleep ran, is taken off the next sleep. If it has fallen more than a whole interval
+// behind it doesn't try to catch up, and starts counting again from now.
+type CompensatingSleeper struct {
+	interval time.Duration
+	sleep    func(time.Duration)
+	now      func() time.Time
+
+	// next is when the sleeper should next wake up, zero before the first Sleep.
+	next time.Time
+}
+
+// NewCompensatingSleeper creates a CompensatingSleeper which wakes every interval, using sleep to
+// wait and now to tell the time, usually time.Sleep and time.Now.
+func NewCompensatingSleeper(interval time.Duration, sleep func(time.Duration), now func() time.Time) *CompensatingSleeper {
+	return &CompensatingSleeper{interval: interval, sleep: sleep, now: now}
+}
+
+// Sleep pauses until an interval after the sleeper last woke up.
+func (c *CompensatingSleeper) Sleep() {
+	now := c.now()
+	if c.next.IsZero() || now.Sub(c.next) > c.interval {
+		c.next = now
+	}
+	c.next = c.next.Add(c.interval)
+
+	if wait := c.next.Sub(now); wait > 0 {
+		c.sleep(wait)
+	}
+}
+
+// Flusher is implemented by writers which buffer what is written to them, such as an
+// http.ResponseWriter streaming a response.
+type Flusher interface {
+	Flush()
+}
+
 const finalWord = "Go!"
 
 // Countdown prints a countdown from 3 to out with a delay between count provided by Sleeper.
+// If out buffers its output it is flushed after every line, so each number appears as it is
+// counted rather than all at once at the end.
 func Countdown(out io.Writer, sleeper Sleeper) {
-	for i := range countDownFrom(3) {
-		fmt.Fprintln(out, i)
-		sleeper.Sleep()
-	}
+	Every(3, sleeper, func(i int) error {
+		_, err := fmt.Fprintln(out, i)
+		flush(out)
+		return err
+	})
 
 	fmt.Fprint(out, finalWord)
+	flush(out)
+}
+
+// flush flushes out if it is a Flusher, or a writer like bufio.Writer whose Flush can fail.
+func flush(out io.Writer) {
+	switch f := out.(type) {
+	case Flusher:
+		f.Flush()
+	case interface{ Flush() error }:
+		f.Flush()
+	}
+}
+
+// Every calls fn n times, with i counting down from n to 1, sleeping after each call.
+// It stops as soon as fn returns an error and returns that error.
+func Every(n int, sleeper Sleeper, fn func(i int) error) error {
+	for i := range countDownFrom(n) {
+		if err := fn(i); err != nil {
+			return err
+		}
+		sleeper.Sleep()
+	}
+	return nil
 }
 
 func countDownFrom(from int) iter.Seq[int] {
@@ -47,6 +111,6 @@ func countDownFrom(from int) iter.Seq[int] {
 }
 
 func main() {
-	sleeper := &ConfigurableSleeper{1 * time.Second, time.Sleep}
+	sleeper := NewCompensatingSleeper(1*time.Second, time.Sleep, time.Now)
 	Countdown(os.Stdout, sleeper)
 }
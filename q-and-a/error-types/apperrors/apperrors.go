@@ -0,0 +1,156 @@
+// Package apperrors describes the failures an application's domain can have, independently of
+// how they are reported. Each kind of failure is its own type so callers can inspect it with
+// errors.As, and each can wrap the error that caused it.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// NotFoundError means the thing asked for does not exist.
+type NotFoundError struct {
+	Resource string
+	ID       string
+	Err      error
+}
+
+func (e NotFoundError) Error() string {
+	return withCause(fmt.Sprintf("%s %q not found", e.Resource, e.ID), e.Err)
+}
+
+func (e NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError means the request clashes with the current state, such as creating something
+// which already exists.
+type ConflictError struct {
+	Resource string
+	ID       string
+	Err      error
+}
+
+func (e ConflictError) Error() string {
+	return withCause(fmt.Sprintf("%s %q conflicts with an existing one", e.Resource, e.ID), e.Err)
+}
+
+func (e ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidError means the input was wrong and retrying it unchanged will not help.
+type InvalidError struct {
+	Field  string
+	Reason string
+	Err    error
+}
+
+func (e InvalidError) Error() string {
+	return withCause(fmt.Sprintf("invalid %s: %s", e.Field, e.Reason), e.Err)
+}
+
+func (e InvalidError) Unwrap() error {
+	return e.Err
+}
+
+// NotFound returns a NotFoundError for the resource with id, caused by err which may be nil.
+func NotFound(resource, id string, err error) error {
+	return NotFoundError{Resource: resource, ID: id, Err: err}
+}
+
+// Conflict returns a ConflictError for the resource with id, caused by err which may be nil.
+func Conflict(resource, id string, err error) error {
+	return ConflictError{Resource: resource, ID: id, Err: err}
+}
+
+// Invalid returns an InvalidError explaining why field is wrong, caused by err which may be nil.
+func Invalid(field, reason string, err error) error {
+	return InvalidError{Field: field, Reason: reason, Err: err}
+}
+
+// IsNotFound reports whether there is a NotFoundError anywhere in err's chain.
+func IsNotFound(err error) bool {
+	var target NotFoundError
+	return errors.As(err, &target)
+}
+
+// IsConflict reports whether there is a ConflictError anywhere in err's chain.
+func IsConflict(err error) bool {
+	var target ConflictError
+	return errors.As(err, &target)
+}
+
+// IsInvalid reports whether there is an InvalidError anywhere in err's chain.
+func IsInvalid(err error) bool {
+	var target InvalidError
+	return errors.As(err, &target)
+}
+
+// HTTPStatus maps err to the HTTP status code a handler should respond with, going by the
+// outermost kind of error in its chain. Errors of no kind are treated as the server's fault.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch err.(type) {
+		case NotFoundError:
+			return http.StatusNotFound
+		case ConflictError:
+			return http.StatusConflict
+		case InvalidError:
+			return http.StatusBadRequest
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Response is how an error is described to an API's clients, usually as a JSON body.
+type Response struct {
+	// Code names the kind of failure for programs to check, like "not_found".
+	Code string `json:"code"`
+	// Message explains the failure to people.
+	Message string `json:"message"`
+	// Details are the fields of the kind of error, such as the resource and ID that weren't found.
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// ToResponse maps err to the HTTP status a handler should respond with, as HTTPStatus does, and
+// a Response describing it.
+func ToResponse(err error) (int, Response) {
+	status := HTTPStatus(err)
+	response := Response{Code: Code(status), Message: err.Error()}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch e := err.(type) {
+		case NotFoundError:
+			response.Details = map[string]string{"resource": e.Resource, "id": e.ID}
+		case ConflictError:
+			response.Details = map[string]string{"resource": e.Resource, "id": e.ID}
+		case InvalidError:
+			response.Details = map[string]string{"field": e.Field, "reason": e.Reason}
+		default:
+			continue
+		}
+		break
+	}
+
+	return status, response
+}
+
+// Code names an HTTP status for a Response, so 404 is "not_found" and 500 "internal_server_error".
+func Code(status int) string {
+	return strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+func withCause(msg string, err error) string {
+	if err == nil {
+		return msg
+	}
+	return msg + ": " + err.Error()
+}
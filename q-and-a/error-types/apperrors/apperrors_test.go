@@ -0,0 +1,172 @@
+package apperrors_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+)
+
+func TestWrappingChains(t *testing.T) {
+	cause := errors.New("no rows in result set")
+
+	t.Run("kinds are found through fmt.Errorf wrapping", func(t *testing.T) {
+		err := fmt.Errorf("handling request: %w", fmt.Errorf("loading player: %w", apperrors.NotFound("player", "Pepper", cause)))
+
+		if !apperrors.IsNotFound(err) {
+			t.Errorf("expected %v to be a NotFoundError", err)
+		}
+		if apperrors.IsConflict(err) || apperrors.IsInvalid(err) {
+			t.Errorf("did not expect %v to be any other kind", err)
+		}
+	})
+
+	t.Run("the cause is still reachable with errors.Is", func(t *testing.T) {
+		err := fmt.Errorf("loading player: %w", apperrors.NotFound("player", "Pepper", cause))
+
+		if !errors.Is(err, cause) {
+			t.Errorf("expected %v to wrap %v", err, cause)
+		}
+	})
+
+	t.Run("details can be read back with errors.As", func(t *testing.T) {
+		err := fmt.Errorf("saving player: %w", apperrors.Conflict("player", "Chris", nil))
+
+		var got apperrors.ConflictError
+		if !errors.As(err, &got) {
+			t.Fatalf("was not a ConflictError, got %T", err)
+		}
+
+		want := apperrors.ConflictError{Resource: "player", ID: "Chris"}
+		if got != want {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	})
+
+	t.Run("the outermost kind decides when kinds are nested", func(t *testing.T) {
+		err := apperrors.Invalid("name", "must not be empty", apperrors.NotFound("player", "", nil))
+
+		if got := apperrors.HTTPStatus(err); got != http.StatusBadRequest {
+			t.Errorf("got status %d want %d", got, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestMessages(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{apperrors.NotFound("player", "Pepper", nil), `player "Pepper" not found`},
+		{apperrors.Conflict("player", "Chris", nil), `player "Chris" conflicts with an existing one`},
+		{apperrors.Invalid("name", "must not be empty", nil), `invalid name: must not be empty`},
+		{apperrors.NotFound("player", "Pepper", errors.New("file closed")), `player "Pepper" not found: file closed`},
+	}
+
+	for _, c := range cases {
+		if got := c.err.Error(); got != c.want {
+			t.Errorf("got %q want %q", got, c.want)
+		}
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"no error", nil, http.StatusOK},
+		{"not found", apperrors.NotFound("player", "Pepper", nil), http.StatusNotFound},
+		{"conflict", apperrors.Conflict("player", "Chris", nil), http.StatusConflict},
+		{"invalid", apperrors.Invalid("name", "too long", nil), http.StatusBadRequest},
+		{"wrapped invalid", fmt.Errorf("oops: %w", apperrors.Invalid("name", "too long", nil)), http.StatusBadRequest},
+		{"anything else", errors.New("disk on fire"), http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := apperrors.HTTPStatus(c.err); got != c.want {
+				t.Errorf("got %d want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestToResponse(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+		want       apperrors.Response
+	}{
+		{
+			name:       "not found",
+			err:        apperrors.NotFound("player", "Pepper", nil),
+			wantStatus: http.StatusNotFound,
+			want: apperrors.Response{
+				Code:    "not_found",
+				Message: `player "Pepper" not found`,
+				Details: map[string]string{"resource": "player", "id": "Pepper"},
+			},
+		},
+		{
+			name:       "conflict",
+			err:        apperrors.Conflict("player", "Chris", nil),
+			wantStatus: http.StatusConflict,
+			want: apperrors.Response{
+				Code:    "conflict",
+				Message: `player "Chris" conflicts with an existing one`,
+				Details: map[string]string{"resource": "player", "id": "Chris"},
+			},
+		},
+		{
+			name:       "wrapped invalid",
+			err:        fmt.Errorf("oops: %w", apperrors.Invalid("name", "too long", nil)),
+			wantStatus: http.StatusBadRequest,
+			want: apperrors.Response{
+				Code:    "bad_request",
+				Message: "oops: invalid name: too long",
+				Details: map[string]string{"field": "name", "reason": "too long"},
+			},
+		},
+		{
+			name:       "anything else",
+			err:        errors.New("disk on fire"),
+			wantStatus: http.StatusInternalServerError,
+			want:       apperrors.Response{Code: "internal_server_error", Message: "disk on fire"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			status, got := apperrors.ToResponse(c.err)
+
+			if status != c.wantStatus {
+				t.Errorf("got status %d want %d", status, c.wantStatus)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %+v want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCode(t *testing.T) {
+	cases := map[int]string{
+		http.StatusBadRequest:          "bad_request",
+		http.StatusUnauthorized:        "unauthorized",
+		http.StatusNotFound:            "not_found",
+		http.StatusMethodNotAllowed:    "method_not_allowed",
+		http.StatusInternalServerError: "internal_server_error",
+	}
+
+	for status, want := range cases {
+		if got := apperrors.Code(status); got != want {
+			t.Errorf("code for %d got %q want %q", status, got, want)
+		}
+	}
+}
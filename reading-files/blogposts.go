@@ -1,23 +1,41 @@
 package blogposts
 
 import (
+	"errors"
+	"fmt"
 	"io/fs"
 )
 
 // NewPostsFromFS returns a collection of blog posts from a file system. If it does not conform to the format then it'll return an error
-func NewPostsFromFS(fileSystem fs.FS) ([]Post, error) {
+func NewPostsFromFS(fileSystem fs.FS, options ...Option) ([]Post, error) {
+	var opts readOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
 	dir, err := fs.ReadDir(fileSystem, ".")
 	if err != nil {
 		return nil, err
 	}
 	var posts []Post
+	var errs []error
 	for _, f := range dir {
 		post, err := getPost(fileSystem, f)
-		if err != nil {
+		if err != nil && !opts.validate {
 			return nil, err //todo: needs clarification, should we totally fail if one file fails? or just ignore?
 		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.Name(), err))
+			continue
+		}
 		posts = append(posts, post)
 	}
+
+	if opts.validate {
+		if err := errors.Join(append(errs, ValidatePosts(posts)...)...); err != nil {
+			return nil, err
+		}
+	}
 	return posts, nil
 }
 
@@ -28,5 +46,10 @@ func getPost(fileSystem fs.FS, f fs.DirEntry) (Post, error) {
 	}
 	defer postFile.Close()
 
-	return newPost(postFile)
+	post, err := newPost(postFile)
+	if err != nil {
+		return Post{}, err
+	}
+	post.File = f.Name()
+	return post, nil
 }
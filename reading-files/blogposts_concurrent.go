@@ -0,0 +1,48 @@
+package blogposts
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"github.com/quii/learn-go-with-tests/sync/pool"
+)
+
+// NewPostsFromFSConcurrently is like NewPostsFromFS but parses up to workers files at once, which
+// must be at least 1. Posts come back in the same order as NewPostsFromFS would return them.
+func NewPostsFromFSConcurrently(fileSystem fs.FS, workers int) ([]Post, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("need at least 1 worker to read posts, got %d", workers)
+	}
+
+	dir, err := fs.ReadDir(fileSystem, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]Post, len(dir))
+	errs := make([]error, len(dir))
+
+	// A post whose parsing panics keeps the error it starts with, and the pool's panic handler
+	// adds what it panicked with.
+	var panicsMu sync.Mutex
+	var panics []error
+	p := pool.New(workers, len(dir), pool.WithPanicHandler(func(recovered any) {
+		panicsMu.Lock()
+		defer panicsMu.Unlock()
+		panics = append(panics, fmt.Errorf("panic: %v", recovered))
+	}))
+	for i, f := range dir {
+		p.Submit(func() {
+			errs[i] = fmt.Errorf("problem parsing %s, it panicked", f.Name())
+			posts[i], errs[i] = getPost(fileSystem, f)
+		})
+	}
+	p.Stop()
+
+	if err := errors.Join(append(errs, panics...)...); err != nil {
+		return nil, err
+	}
+	return posts, nil
+}
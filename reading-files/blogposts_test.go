@@ -1,10 +1,16 @@
 package blogposts_test
 
 import (
-	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+	"errors"
+	"fmt"
+	"io/fs"
 	"reflect"
+	"strings"
+
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
 	"testing"
 	"testing/fstest"
+	"time"
 )
 
 func TestNewBlogPosts(t *testing.T) {
@@ -41,10 +47,185 @@ M`
 		Tags:        []string{"tdd", "go"},
 		Body: `Hello
 World`,
+		WordCount:   2,
+		ReadingTime: blogposts.ReadingTime(2, blogposts.DefaultWordsPerMinute),
+		File:        "hello world.md",
+	})
+}
+
+func TestNewBlogPostsWithFrontMatter(t *testing.T) {
+	const (
+		headerBody = `Title: Post 1
+Description: Description 1
+Tags: tdd, go
+---
+Hello
+World`
+		frontMatterBody = `---
+title: Post 2
+description: Description 2
+tags: [rust, borrow-checker]
+---
+B
+L
+M`
+		plainTagsBody = `---
+Title: Post 3
+Tags: go
+Date: 2024-03-17
+---
+C`
+	)
+
+	fs := fstest.MapFS{
+		"1.md": {Data: []byte(headerBody)},
+		"2.md": {Data: []byte(frontMatterBody)},
+		"3.md": {Data: []byte(plainTagsBody)},
+	}
+
+	posts, err := blogposts.NewPostsFromFS(fs)
+
+	assertNoError(t, err)
+	assertPostsLength(t, posts, fs)
+
+	assertPost(t, posts[0], blogposts.Post{
+		Title:       "Post 1",
+		Description: "Description 1",
+		Tags:        []string{"tdd", "go"},
+		Body: `Hello
+World`,
+		WordCount:   2,
+		ReadingTime: blogposts.ReadingTime(2, blogposts.DefaultWordsPerMinute),
+		File:        "1.md",
+	})
+
+	assertPost(t, posts[1], blogposts.Post{
+		Title:       "Post 2",
+		Description: "Description 2",
+		Tags:        []string{"rust", "borrow-checker"},
+		Body: `B
+L
+M`,
+		WordCount:   3,
+		ReadingTime: blogposts.ReadingTime(3, blogposts.DefaultWordsPerMinute),
+		File:        "2.md",
+	})
+
+	assertPost(t, posts[2], blogposts.Post{
+		Title:       "Post 3",
+		Tags:        []string{"go"},
+		Body:        "C",
+		Date:        time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC),
+		WordCount:   1,
+		ReadingTime: blogposts.ReadingTime(1, blogposts.DefaultWordsPerMinute),
+		File:        "3.md",
 	})
+
+	t.Run("front matter must be closed", func(t *testing.T) {
+		_, err := blogposts.NewPostsFromFS(fstest.MapFS{
+			"broken.md": {Data: []byte("---\ntitle: Oops\nno closing delimiter")},
+		})
+
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+
+	t.Run("dates must be written as 2006-01-02", func(t *testing.T) {
+		_, err := blogposts.NewPostsFromFS(fstest.MapFS{
+			"broken.md": {Data: []byte("---\ntitle: Oops\ndate: 17/03/2024\n---\nbody")},
+		})
+
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}
+
+func TestNewPostsFromFSConcurrently(t *testing.T) {
+	fs := fstest.MapFS{}
+	for i := 0; i < 50; i++ {
+		fs[fmt.Sprintf("post-%02d.md", i)] = &fstest.MapFile{Data: []byte(fmt.Sprintf("Title: Post %d\nDescription: D\nTags: go\n---\nBody %d", i, i))}
+	}
+
+	t.Run("parses the same posts as NewPostsFromFS, in the same order", func(t *testing.T) {
+		want, err := blogposts.NewPostsFromFS(fs)
+		assertNoError(t, err)
+
+		got, err := blogposts.NewPostsFromFSConcurrently(fs, 4)
+		assertNoError(t, err)
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("fails if a file can't be read", func(t *testing.T) {
+		_, err := blogposts.NewPostsFromFSConcurrently(failingFS{}, 4)
+
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+
+	t.Run("fails if parsing a file panics", func(t *testing.T) {
+		_, err := blogposts.NewPostsFromFSConcurrently(panickingFS{fs}, 4)
+
+		if err == nil || !strings.Contains(err.Error(), "post-07.md") {
+			t.Errorf("expected an error about post-07.md but got %v", err)
+		}
+	})
+
+	t.Run("fails without any workers", func(t *testing.T) {
+		_, err := blogposts.NewPostsFromFSConcurrently(fs, 0)
+
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}
+
+func BenchmarkNewPostsFromFS(b *testing.B) {
+	fs := fstest.MapFS{}
+	for i := 0; i < 200; i++ {
+		fs[fmt.Sprintf("post-%03d.md", i)] = &fstest.MapFile{Data: []byte("Title: T\nDescription: D\nTags: go\n---\n" + strings.Repeat("body text\n", 500))}
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			blogposts.NewPostsFromFS(fs)
+		}
+	})
+
+	b.Run("concurrent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			blogposts.NewPostsFromFSConcurrently(fs, 8)
+		}
+	})
+}
+
+type failingFS struct{}
+
+func (failingFS) Open(name string) (fs.File, error) {
+	if name == "." {
+		return fstest.MapFS{"a.md": {}, "b.md": {}}.Open(name)
+	}
+	return nil, errors.New("oh no, always failing")
+}
+
+// panickingFS panics when post-07.md is opened.
+type panickingFS struct {
+	fs.FS
+}
+
+func (p panickingFS) Open(name string) (fs.File, error) {
+	if name == "post-07.md" {
+		panic("oh no, a panic")
+	}
+	return p.FS.Open(name)
 }
 
-func assertNoError(t *testing.T, err error) {
+func assertNoError(t testing.TB, err error) {
 	t.Helper()
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,142 @@
+package blogposts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LivePosts keeps a collection of posts up to date with the files in a directory, so that a
+// server can show edits without being restarted.
+type LivePosts struct {
+	fileSystem fs.FS
+	changes    chan struct{}
+
+	lock  sync.RWMutex
+	files map[string]parsedFile
+}
+
+type parsedFile struct {
+	modTime time.Time
+	post    Post
+}
+
+// NewLivePosts reads the posts in dir. Call Reload or Watch to pick up changes.
+func NewLivePosts(dir string) (*LivePosts, error) {
+	l := &LivePosts{
+		fileSystem: os.DirFS(dir),
+		changes:    make(chan struct{}, 1),
+		files:      map[string]parsedFile{},
+	}
+
+	if _, err := l.Reload(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Posts returns the current posts, ordered by filename.
+func (l *LivePosts) Posts() []Post {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	names := make([]string, 0, len(l.files))
+	for name := range l.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	posts := make([]Post, 0, len(names))
+	for _, name := range names {
+		posts = append(posts, l.files[name].post)
+	}
+	return posts
+}
+
+// Changes receives a value after a Reload finds that posts were added, edited or removed.
+// Changes which happen before the last one has been received are merged together.
+func (l *LivePosts) Changes() <-chan struct{} {
+	return l.changes
+}
+
+// Reload re-reads the directory, only parsing files whose modification time has changed, and
+// reports whether anything changed. A file which can't be read or parsed keeps the post it had
+// before, if any, and is tried again next time; the rest of the directory is still reloaded, and
+// the problems with every such file are returned together.
+func (l *LivePosts) Reload() (bool, error) {
+	dir, err := fs.ReadDir(l.fileSystem, ".")
+	if err != nil {
+		return false, err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	changed := false
+	seen := map[string]bool{}
+	var errs []error
+
+	for _, f := range dir {
+		seen[f.Name()] = true
+
+		info, err := f.Info()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("problem reading %s, %w", f.Name(), err))
+			continue
+		}
+
+		if existing, ok := l.files[f.Name()]; ok && existing.modTime.Equal(info.ModTime()) {
+			continue
+		}
+
+		post, err := getPost(l.fileSystem, f)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("problem parsing %s, %w", f.Name(), err))
+			continue
+		}
+		l.files[f.Name()] = parsedFile{modTime: info.ModTime(), post: post}
+		changed = true
+	}
+
+	for name := range l.files {
+		if !seen[name] {
+			delete(l.files, name)
+			changed = true
+		}
+	}
+
+	if changed {
+		select {
+		case l.changes <- struct{}{}:
+		default:
+		}
+	}
+
+	return changed, errors.Join(errs...)
+}
+
+// Watch calls Reload whenever ticks receives a value, until ctx is cancelled. Pass a time.Ticker's C
+// to poll the directory. Files which fail to reload, such as a half-written one failing to parse,
+// are retried on the next tick. Their errors are passed to report, if it isn't nil, unless they
+// are the same as last time.
+func (l *LivePosts) Watch(ctx context.Context, ticks <-chan time.Time, report func(error)) {
+	var last error
+	for {
+		select {
+		case <-ticks:
+			_, err := l.Reload()
+			if err != nil && report != nil && (last == nil || err.Error() != last.Error()) {
+				report(err)
+			}
+			last = err
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package blogposts_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+)
+
+func TestLivePosts(t *testing.T) {
+	const (
+		firstBody = `Title: Post 1
+Description: Description 1
+Tags: tdd, go
+---
+Hello`
+		editedBody = `Title: Post 1 (edited)
+Description: Description 1
+Tags: tdd, go
+---
+Hello again`
+		secondBody = `Title: Post 2
+Description: Description 2
+Tags: rust
+---
+B`
+	)
+
+	t.Run("reads the posts in a directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writePost(t, dir, "1.md", firstBody)
+		writePost(t, dir, "2.md", secondBody)
+
+		live, err := blogposts.NewLivePosts(dir)
+		assertNoError(t, err)
+
+		assertTitles(t, live.Posts(), "Post 1", "Post 2")
+	})
+
+	t.Run("picks up added, edited and removed posts", func(t *testing.T) {
+		dir := t.TempDir()
+		writePost(t, dir, "1.md", firstBody)
+
+		live, err := blogposts.NewLivePosts(dir)
+		assertNoError(t, err)
+		drain(live.Changes())
+
+		writePost(t, dir, "2.md", secondBody)
+		assertReloaded(t, live, true)
+		assertTitles(t, live.Posts(), "Post 1", "Post 2")
+
+		writePost(t, dir, "1.md", editedBody)
+		touch(t, dir, "1.md", time.Now().Add(time.Minute))
+		assertReloaded(t, live, true)
+		assertTitles(t, live.Posts(), "Post 1 (edited)", "Post 2")
+
+		assertNoError(t, os.Remove(filepath.Join(dir, "2.md")))
+		assertReloaded(t, live, true)
+		assertTitles(t, live.Posts(), "Post 1 (edited)")
+	})
+
+	t.Run("only re-parses files whose modification time changed", func(t *testing.T) {
+		dir := t.TempDir()
+		writePost(t, dir, "1.md", firstBody)
+		originalModTime := time.Now().Add(-time.Hour)
+		touch(t, dir, "1.md", originalModTime)
+
+		live, err := blogposts.NewLivePosts(dir)
+		assertNoError(t, err)
+
+		// same mod time, so the edit should go unnoticed
+		writePost(t, dir, "1.md", editedBody)
+		touch(t, dir, "1.md", originalModTime)
+
+		assertReloaded(t, live, false)
+		assertTitles(t, live.Posts(), "Post 1")
+	})
+
+	t.Run("a file which doesn't parse doesn't stop the others reloading", func(t *testing.T) {
+		dir := t.TempDir()
+		writePost(t, dir, "1.md", firstBody)
+
+		live, err := blogposts.NewLivePosts(dir)
+		assertNoError(t, err)
+		drain(live.Changes())
+
+		writePost(t, dir, "1.md", "---\nTitle: half written")
+		touch(t, dir, "1.md", time.Now().Add(time.Minute))
+		writePost(t, dir, "2.md", secondBody)
+
+		changed, err := live.Reload()
+		if err == nil || !strings.Contains(err.Error(), "1.md") {
+			t.Errorf("got error %v, want one about 1.md", err)
+		}
+		if !changed {
+			t.Error("expected the new post to count as a change")
+		}
+		assertTitles(t, live.Posts(), "Post 1", "Post 2")
+
+		select {
+		case <-live.Changes():
+		default:
+			t.Error("expected a change notification")
+		}
+	})
+
+	t.Run("reports errors when watching", func(t *testing.T) {
+		dir := t.TempDir()
+		writePost(t, dir, "1.md", firstBody)
+
+		live, err := blogposts.NewLivePosts(dir)
+		assertNoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ticks := make(chan time.Time)
+		errs := make(chan error, 1)
+		go live.Watch(ctx, ticks, func(err error) { errs <- err })
+
+		writePost(t, dir, "2.md", "---\nTitle: half written")
+		ticks <- time.Now()
+
+		select {
+		case err := <-errs:
+			if !strings.Contains(err.Error(), "2.md") {
+				t.Errorf("got error %v, want one about 2.md", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the error")
+		}
+	})
+
+	t.Run("notifies on the changes channel when watching", func(t *testing.T) {
+		dir := t.TempDir()
+		writePost(t, dir, "1.md", firstBody)
+
+		live, err := blogposts.NewLivePosts(dir)
+		assertNoError(t, err)
+		drain(live.Changes())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ticks := make(chan time.Time)
+		go live.Watch(ctx, ticks, nil)
+
+		writePost(t, dir, "2.md", secondBody)
+		ticks <- time.Now()
+
+		select {
+		case <-live.Changes():
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a change notification")
+		}
+		assertTitles(t, live.Posts(), "Post 1", "Post 2")
+	})
+}
+
+func writePost(t testing.TB, dir, name, body string) {
+	t.Helper()
+	assertNoError(t, os.WriteFile(filepath.Join(dir, name), []byte(body), 0644))
+}
+
+func touch(t testing.TB, dir, name string, modTime time.Time) {
+	t.Helper()
+	assertNoError(t, os.Chtimes(filepath.Join(dir, name), modTime, modTime))
+}
+
+func drain(changes <-chan struct{}) {
+	select {
+	case <-changes:
+	default:
+	}
+}
+
+func assertReloaded(t testing.TB, live *blogposts.LivePosts, want bool) {
+	t.Helper()
+	changed, err := live.Reload()
+	assertNoError(t, err)
+	if changed != want {
+		t.Errorf("got changed %v from Reload, want %v", changed, want)
+	}
+}
+
+func assertTitles(t testing.TB, posts []blogposts.Post, want ...string) {
+	t.Helper()
+	var got []string
+	for _, p := range posts {
+		got = append(got, p.Title)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got titles %q want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got titles %q want %q", got, want)
+		}
+	}
+}
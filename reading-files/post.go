@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 )
 
 // Post represents a post on a blog
@@ -14,32 +15,123 @@ type Post struct {
 	Description string
 	Tags        []string
 	Body        string
+	Date        time.Time
+	WordCount   int
+	ReadingTime time.Duration
+
+	// File is the name of the file the post was read from, if it was read from one.
+	File string
 }
 
 const (
 	titleSeparator       = "Title: "
 	descriptionSeparator = "Description: "
 	tagsSeparator        = "Tags: "
+
+	frontMatterDelimiter = "---"
+
+	// DateLayout is how a post's date is written in its front matter.
+	DateLayout = "2006-01-02"
 )
 
+// postParser reads a post whose first line has already been scanned.
+type postParser func(firstLine string, scanner *bufio.Scanner) (Post, error)
+
+// newPost reads a post in either the book's header format or with standard front matter between
+// "---" lines, picking the parser by looking at the first line.
 func newPost(postBody io.Reader) (Post, error) {
 	scanner := bufio.NewScanner(postBody)
+	scanner.Scan()
+	firstLine := scanner.Text()
+
+	var parse postParser = parseHeaders
+	if firstLine == frontMatterDelimiter {
+		parse = parseFrontMatter
+	}
 
+	post, err := parse(firstLine, scanner)
+	if err != nil {
+		return Post{}, err
+	}
+
+	post.WordCount = CountWords(post.Body)
+	post.ReadingTime = ReadingTime(post.WordCount, DefaultWordsPerMinute)
+	return post, nil
+}
+
+func parseHeaders(firstLine string, scanner *bufio.Scanner) (Post, error) {
 	readMetaLine := func(tagName string) string {
 		scanner.Scan()
 		return strings.TrimPrefix(scanner.Text(), tagName)
 	}
 
 	return Post{
-		Title:       readMetaLine(titleSeparator),
+		Title:       strings.TrimPrefix(firstLine, titleSeparator),
 		Description: readMetaLine(descriptionSeparator),
 		Tags:        strings.Split(readMetaLine(tagsSeparator), ", "),
 		Body:        readBody(scanner),
 	}, nil
 }
 
+// parseFrontMatter reads simple "key: value" front matter. Tags can be written as "go, tdd" or "[go, tdd]",
+// and dates as 2006-01-02.
+func parseFrontMatter(_ string, scanner *bufio.Scanner) (Post, error) {
+	var post Post
+
+	for {
+		if !scanner.Scan() {
+			return Post{}, fmt.Errorf("front matter was not closed with %q", frontMatterDelimiter)
+		}
+
+		line := scanner.Text()
+		if line == frontMatterDelimiter {
+			break
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			post.Title = value
+		case "description":
+			post.Description = value
+		case "tags":
+			post.Tags = splitTags(value)
+		case "date":
+			date, err := time.Parse(DateLayout, value)
+			if err != nil {
+				return Post{}, fmt.Errorf("could not parse the date of post %q, %v", post.Title, err)
+			}
+			post.Date = date
+		}
+	}
+
+	post.Body = readLines(scanner)
+	return post, nil
+}
+
+func splitTags(value string) []string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 func readBody(scanner *bufio.Scanner) string {
 	scanner.Scan() // ignore a line
+	return readLines(scanner)
+}
+
+func readLines(scanner *bufio.Scanner) string {
 	buf := bytes.Buffer{}
 	for scanner.Scan() {
 		fmt.Fprintln(&buf, scanner.Text())
@@ -0,0 +1,47 @@
+package blogposts_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+)
+
+func FuzzNewPost(f *testing.F) {
+	f.Add([]byte("Title: Post 1\nDescription: Description 1\nTags: tdd, go\n---\nHello\nWorld"))
+	f.Add([]byte("---\ntitle: Post 2\ntags: [rust, borrow-checker]\n---\nB"))
+	f.Add([]byte("---\ntitle: never closed"))
+	f.Add([]byte(""))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Any file must either parse or fail with an error, never panic.
+		blogposts.NewPostsFromFS(fstest.MapFS{"post.md": {Data: data}})
+	})
+}
+
+func FuzzFrontMatterRoundTrip(f *testing.F) {
+	f.Add("Post 2", "Description 2", "B\nL\nM")
+	f.Add("TDD: a primer", "", "")
+
+	f.Fuzz(func(t *testing.T, title, description, body string) {
+		for _, value := range []string{title, description} {
+			if strings.ContainsAny(value, "\r\n") || strings.TrimSpace(value) != value {
+				t.Skip("front matter values are single trimmed lines")
+			}
+		}
+		if strings.Contains(body, "\r") || strings.HasSuffix(body, "\n") || len(body) > 4096 {
+			t.Skip("the body's line endings are normalised")
+		}
+
+		file := fmt.Sprintf("---\ntitle: %s\ndescription: %s\n---\n%s", title, description, body)
+		posts, err := blogposts.NewPostsFromFS(fstest.MapFS{"post.md": {Data: []byte(file)}})
+		assertNoError(t, err)
+
+		got := posts[0]
+		if got.Title != title || got.Description != description || got.Body != body {
+			t.Errorf("got %+v from\n%s", got, file)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package blogposts
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultWordsPerMinute is how fast we assume people read when working out a post's ReadingTime.
+const DefaultWordsPerMinute = 200
+
+var markdownLink = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// CountWords counts the words of prose in a markdown body. Code fences, link targets and tokens
+// that are only markdown punctuation, like "#", "-" or "---", are not counted.
+func CountWords(body string) int {
+	words := 0
+	fence := ""
+
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		for _, token := range strings.Fields(markdownLink.ReplaceAllString(line, "$1")) {
+			if strings.IndexFunc(token, isWordRune) != -1 {
+				words++
+			}
+		}
+	}
+
+	return words
+}
+
+// ReadingTime estimates how long it takes to read words at wordsPerMinute.
+func ReadingTime(words, wordsPerMinute int) time.Duration {
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+	return time.Duration(words) * time.Minute / time.Duration(wordsPerMinute)
+}
+
+// WithWordsPerMinute returns a copy of the post with its ReadingTime worked out for a different reading speed.
+func (p Post) WithWordsPerMinute(wordsPerMinute int) Post {
+	p.ReadingTime = ReadingTime(p.WordCount, wordsPerMinute)
+	return p
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,77 @@
+package blogposts_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+)
+
+func TestCountWords(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"plain prose", "Hello world, how are you?", 5},
+		{"headings and emphasis", "# First recipe!\nWelcome to my **amazing blog**.", 7},
+		{"lists and rules", "- one\n- two\n\n---\n\n* three", 3},
+		{"links count their text, not their target", "Read [the book](https://quii.gitbook.io/learn-go-with-tests) today", 4},
+		{"images count their alt text", "![a gopher](gopher.png)", 2},
+		{"code fences are not prose", "Run this:\n```go\nfmt.Println(\"hello world\")\n```\nand enjoy", 4},
+		{"tilde fences are not prose", "~~~\nlots of code here\n~~~", 0},
+		{"an unclosed fence hides the rest", "before\n```\nafter", 1},
+		{"numbers are words", "Go 1.24 came out in 2025", 6},
+		{"empty", "", 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := blogposts.CountWords(c.body); got != c.want {
+				t.Errorf("got %d words want %d in %q", got, c.want, c.body)
+			}
+		})
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	cases := []struct {
+		name             string
+		words, perMinute int
+		want             time.Duration
+	}{
+		{"a minute of words", 200, 200, time.Minute},
+		{"a slower reader", 200, 100, 2 * time.Minute},
+		{"part of a minute", 50, 200, 15 * time.Second},
+		{"no speed uses the default", 400, 0, 2 * time.Minute},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := blogposts.ReadingTime(c.words, c.perMinute); got != c.want {
+				t.Errorf("got %v want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestPostReadingTime(t *testing.T) {
+	posts, err := blogposts.NewPostsFromFS(fstest.MapFS{
+		"post.md": {Data: []byte("Title: T\nDescription: D\nTags: go\n---\nfour words of prose\n```\nignored code\n```")},
+	})
+	assertNoError(t, err)
+
+	post := posts[0]
+	if post.WordCount != 4 {
+		t.Errorf("got %d words want 4", post.WordCount)
+	}
+	if want := blogposts.ReadingTime(4, blogposts.DefaultWordsPerMinute); post.ReadingTime != want {
+		t.Errorf("got reading time %v want %v", post.ReadingTime, want)
+	}
+
+	slower := post.WithWordsPerMinute(2)
+	if slower.ReadingTime != 2*time.Minute {
+		t.Errorf("got reading time %v at 2 words a minute, want 2m", slower.ReadingTime)
+	}
+}
@@ -0,0 +1,85 @@
+package blogposts
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// RelatedPosts picks up to n of posts to suggest to someone who has read target: the ones sharing
+// the most tags with it, newest first when they share as many. Posts with no tags in common aren't
+// related at all, so fewer than n may be returned. Ties are broken by title, then by the order of
+// posts, so the same posts always give the same suggestions.
+func RelatedPosts(posts []Post, target Post, n int) []Post {
+	indexes := related(posts, target, n, func(i int) bool { return reflect.DeepEqual(posts[i], target) })
+
+	relatedPosts := make([]Post, 0, len(indexes))
+	for _, i := range indexes {
+		relatedPosts = append(relatedPosts, posts[i])
+	}
+	return relatedPosts
+}
+
+// RelatedPostIndexes is RelatedPosts for posts[target], giving the indexes of the posts it picks.
+// Only the target itself is left out, so a post equal to it can still be suggested.
+func RelatedPostIndexes(posts []Post, target, n int) []int {
+	return related(posts, posts[target], n, func(i int) bool { return i == target })
+}
+
+// related returns the indexes in posts of up to n posts related to target, leaving out those
+// isTarget reports are the target itself.
+func related(posts []Post, target Post, n int, isTarget func(i int) bool) []int {
+	if n <= 0 {
+		return nil
+	}
+
+	targetTags := tagSet(target)
+
+	type candidate struct {
+		index  int
+		shared int
+	}
+
+	var candidates []candidate
+	for i, p := range posts {
+		if isTarget(i) {
+			continue
+		}
+
+		shared := 0
+		for tag := range tagSet(p) {
+			if targetTags[tag] {
+				shared++
+			}
+		}
+
+		if shared > 0 {
+			candidates = append(candidates, candidate{index: i, shared: shared})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		pa, pb := posts[a.index], posts[b.index]
+		return cmp.Or(
+			cmp.Compare(b.shared, a.shared),
+			pb.Date.Compare(pa.Date),
+			cmp.Compare(pa.Title, pb.Title),
+		)
+	})
+
+	indexes := make([]int, 0, min(n, len(candidates)))
+	for _, c := range candidates[:min(n, len(candidates))] {
+		indexes = append(indexes, c.index)
+	}
+	return indexes
+}
+
+// tagSet is a post's tags ignoring case, so "Go" and "go" are the same tag, and each only counts once.
+func tagSet(p Post) map[string]bool {
+	tags := make(map[string]bool, len(p.Tags))
+	for _, tag := range p.Tags {
+		tags[strings.ToLower(tag)] = true
+	}
+	return tags
+}
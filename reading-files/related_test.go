@@ -0,0 +1,64 @@
+package blogposts_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+)
+
+func TestRelatedPosts(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC) }
+
+	target := blogposts.Post{Title: "Mocking", Tags: []string{"go", "tdd", "mocking"}, Date: day(10)}
+	posts := []blogposts.Post{
+		target,
+		{Title: "Dependency injection", Tags: []string{"go", "tdd"}, Date: day(1)},
+		{Title: "Spies", Tags: []string{"Go", "TDD", "mocking"}, Date: day(2)},
+		{Title: "Concurrency", Tags: []string{"go"}, Date: day(5)},
+		{Title: "Select", Tags: []string{"go"}, Date: day(5)},
+		{Title: "Arrays", Tags: []string{"go", "go"}, Date: day(3)},
+		{Title: "Recipes", Tags: []string{"cooking"}, Date: day(20)},
+		{Title: "Untagged", Date: day(21)},
+	}
+
+	cases := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{"most shared tags first", 1, []string{"Spies"}},
+		{"then the newest", 3, []string{"Spies", "Dependency injection", "Concurrency"}},
+		{"then by title when they are as new as each other", 4, []string{"Spies", "Dependency injection", "Concurrency", "Select"}},
+		{"never the post itself or posts without shared tags", 10, []string{"Spies", "Dependency injection", "Concurrency", "Select", "Arrays"}},
+		{"none asked for", 0, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assertTitles(t, blogposts.RelatedPosts(posts, target, c.n), c.want...)
+		})
+	}
+
+	t.Run("the order of posts doesn't change the suggestions", func(t *testing.T) {
+		reversed := slices.Clone(posts)
+		slices.Reverse(reversed)
+
+		assertTitles(t, blogposts.RelatedPosts(reversed, target, 10), "Spies", "Dependency injection", "Concurrency", "Select", "Arrays")
+	})
+
+	t.Run("a post without tags has nothing related", func(t *testing.T) {
+		assertTitles(t, blogposts.RelatedPosts(posts, blogposts.Post{Title: "Untagged", Date: day(21)}, 3))
+	})
+
+	t.Run("indexes tell apart posts equal to each other", func(t *testing.T) {
+		duplicated := append(slices.Clone(posts), posts[2])
+
+		got := blogposts.RelatedPostIndexes(duplicated, 2, 2)
+
+		if want := []int{0, 8}; !slices.Equal(got, want) {
+			t.Errorf("got indexes %v want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,240 @@
+package blogposts_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+)
+
+// Posts don't have to come from a directory on disk. Anything that implements fs.FS will do,
+// so these tests read the same posts from a few very different places.
+
+//go:embed testdata/posts
+var embeddedPosts embed.FS
+
+func TestPostsFromOtherFileSystems(t *testing.T) {
+	sources := map[string]func(t *testing.T) fs.FS{
+		"a directory": func(t *testing.T) fs.FS {
+			return os.DirFS("testdata/posts")
+		},
+		"files embedded in the binary": func(t *testing.T) fs.FS {
+			posts, err := fs.Sub(embeddedPosts, "testdata/posts")
+			assertNoError(t, err)
+			return posts
+		},
+		"a zip archive": func(t *testing.T) fs.FS {
+			return zipOf(t, os.DirFS("testdata/posts"))
+		},
+		"an object storage bucket": func(t *testing.T) fs.FS {
+			return bucketOf(t, os.DirFS("testdata/posts"), "blog/posts/")
+		},
+	}
+
+	for name, source := range sources {
+		t.Run(name, func(t *testing.T) {
+			fileSystem := source(t)
+
+			if err := fstest.TestFS(fileSystem, "hello-world.md", "readers.md"); err != nil {
+				t.Fatal(err)
+			}
+
+			posts, err := blogposts.NewPostsFromFS(fileSystem)
+			assertNoError(t, err)
+			assertTestdataPosts(t, posts)
+		})
+	}
+}
+
+func assertTestdataPosts(t *testing.T, posts []blogposts.Post) {
+	t.Helper()
+
+	if len(posts) != 2 {
+		t.Fatalf("got %d posts, wanted 2", len(posts))
+	}
+
+	helloBody := "Hello world!\n\nThe body of posts starts after the `---`"
+	assertPost(t, posts[0], blogposts.Post{
+		Title:       "Hello, TDD world!",
+		Description: "First post on our wonderful blog",
+		Tags:        []string{"tdd", "go"},
+		Body:        helloBody,
+		WordCount:   blogposts.CountWords(helloBody),
+		ReadingTime: blogposts.ReadingTime(blogposts.CountWords(helloBody), blogposts.DefaultWordsPerMinute),
+		File:        "hello-world.md",
+	})
+
+	readersBody := "Zip files, embedded files and buckets all look the same to NewPostsFromFS."
+	assertPost(t, posts[1], blogposts.Post{
+		Title:       "Readers everywhere",
+		Description: "Posts can come from any fs.FS",
+		Tags:        []string{"go", "io"},
+		Body:        readersBody,
+		Date:        time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC),
+		WordCount:   blogposts.CountWords(readersBody),
+		ReadingTime: blogposts.ReadingTime(blogposts.CountWords(readersBody), blogposts.DefaultWordsPerMinute),
+		File:        "readers.md",
+	})
+}
+
+// zipOf archives the files in dir, as if they had been downloaded as a zip. *zip.Reader is an fs.FS.
+func zipOf(t *testing.T, dir fs.FS) fs.FS {
+	t.Helper()
+
+	buf := bytes.Buffer{}
+	archive := zip.NewWriter(&buf)
+	for name, contents := range readFiles(t, dir) {
+		f, err := archive.Create(name)
+		assertNoError(t, err)
+		_, err = f.Write(contents)
+		assertNoError(t, err)
+	}
+	assertNoError(t, archive.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assertNoError(t, err)
+	return reader
+}
+
+// bucketOf uploads the files in dir to a fake bucket under prefix.
+func bucketOf(t *testing.T, dir fs.FS, prefix string) fs.FS {
+	t.Helper()
+
+	bucket := &fakeBucket{objects: map[string][]byte{"blog/drafts/secret.md": []byte("not published yet")}}
+	for name, contents := range readFiles(t, dir) {
+		bucket.objects[prefix+name] = contents
+	}
+	return bucketFS{bucket: bucket, prefix: prefix}
+}
+
+func readFiles(t *testing.T, dir fs.FS) map[string][]byte {
+	t.Helper()
+
+	files := map[string][]byte{}
+	entries, err := fs.ReadDir(dir, ".")
+	assertNoError(t, err)
+	for _, entry := range entries {
+		contents, err := fs.ReadFile(dir, entry.Name())
+		assertNoError(t, err)
+		files[entry.Name()] = contents
+	}
+	return files
+}
+
+// fakeBucket is like an object storage bucket, such as S3: a flat set of keys, which only look
+// like directories because of the slashes in them.
+type fakeBucket struct {
+	objects map[string][]byte
+}
+
+// list returns the keys starting with prefix, in order, as a bucket's list objects call would.
+func (b *fakeBucket) list(prefix string) []string {
+	var keys []string
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (b *fakeBucket) get(key string) ([]byte, bool) {
+	object, ok := b.objects[key]
+	return object, ok
+}
+
+// bucketFS is the fs.FS of the objects under prefix in a bucket. It only has one level of
+// "directory", which is all NewPostsFromFS needs.
+type bucketFS struct {
+	bucket *fakeBucket
+	prefix string
+}
+
+func (b bucketFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name == "." {
+		var entries []fs.DirEntry
+		for _, key := range b.bucket.list(b.prefix) {
+			name := strings.TrimPrefix(key, b.prefix)
+			if !strings.Contains(name, "/") {
+				object, _ := b.bucket.get(key)
+				entries = append(entries, fs.FileInfoToDirEntry(objectInfo{name: name, size: len(object)}))
+			}
+		}
+		return &bucketDir{entries: entries}, nil
+	}
+
+	object, ok := b.bucket.get(b.prefix + name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &bucketObject{Reader: bytes.NewReader(object), info: objectInfo{name: path.Base(name), size: len(object)}}, nil
+}
+
+type bucketObject struct {
+	*bytes.Reader
+	info objectInfo
+}
+
+func (o *bucketObject) Stat() (fs.FileInfo, error) { return o.info, nil }
+func (o *bucketObject) Close() error               { return nil }
+
+type bucketDir struct {
+	entries []fs.DirEntry
+	read    int
+}
+
+func (d *bucketDir) Stat() (fs.FileInfo, error) { return objectInfo{name: ".", dir: true}, nil }
+func (d *bucketDir) Close() error               { return nil }
+
+func (d *bucketDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: ".", Err: fs.ErrInvalid}
+}
+
+func (d *bucketDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.read:]
+	if n <= 0 {
+		d.read = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(remaining))
+	d.read += n
+	return remaining[:n], nil
+}
+
+// objectInfo describes an object. Buckets don't keep modes or modification times we care about.
+type objectInfo struct {
+	name string
+	size int
+	dir  bool
+}
+
+func (i objectInfo) Name() string       { return i.name }
+func (i objectInfo) Size() int64        { return int64(i.size) }
+func (i objectInfo) ModTime() time.Time { return time.Time{} }
+func (i objectInfo) IsDir() bool        { return i.dir }
+func (i objectInfo) Sys() any           { return nil }
+
+func (i objectInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
@@ -0,0 +1,60 @@
+package blogposts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PostError is a post breaking one of the rules ValidatePosts checks.
+type PostError struct {
+	File    string
+	Problem string
+}
+
+func (e PostError) Error() string {
+	if e.File == "" {
+		return "post " + e.Problem
+	}
+	return e.File + ": " + e.Problem
+}
+
+// ValidatePosts checks every post has a title and a date, and that its tags are lowercase so the
+// same tag isn't listed twice. It returns every problem it finds rather than stopping at the first.
+func ValidatePosts(posts []Post) []error {
+	var errs []error
+
+	for _, p := range posts {
+		problem := func(format string, args ...any) {
+			errs = append(errs, PostError{File: p.File, Problem: fmt.Sprintf(format, args...)})
+		}
+
+		if strings.TrimSpace(p.Title) == "" {
+			problem("title must not be empty")
+		}
+		if p.Date.IsZero() {
+			problem("date is missing")
+		}
+		for _, tag := range p.Tags {
+			if tag != strings.ToLower(tag) {
+				problem("tag %q must be lowercase", tag)
+			}
+		}
+	}
+
+	return errs
+}
+
+// Option changes how NewPostsFromFS reads posts.
+type Option func(*readOptions)
+
+type readOptions struct {
+	validate bool
+}
+
+// WithValidation makes NewPostsFromFS fail if any post breaks the rules ValidatePosts checks.
+// Every file is read, so the error lists all the problems, including files which can't be parsed.
+func WithValidation() Option {
+	return func(o *readOptions) {
+		o.validate = true
+	}
+}
@@ -0,0 +1,107 @@
+package blogposts_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	blogposts "github.com/quii/learn-go-with-tests/reading-files"
+)
+
+func TestValidatePosts(t *testing.T) {
+	date := time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)
+
+	t.Run("valid posts have no problems", func(t *testing.T) {
+		errs := blogposts.ValidatePosts([]blogposts.Post{
+			{Title: "Fine", Date: date, Tags: []string{"go", "tdd"}, File: "fine.md"},
+			{Title: "No tags is fine too", Date: date, File: "untagged.md"},
+		})
+
+		if len(errs) != 0 {
+			t.Errorf("expected no problems, got %v", errs)
+		}
+	})
+
+	t.Run("reports every problem with every post", func(t *testing.T) {
+		errs := blogposts.ValidatePosts([]blogposts.Post{
+			{Title: "Fine", Date: date, Tags: []string{"go"}, File: "fine.md"},
+			{Title: "  ", Date: date, File: "blank-title.md"},
+			{Tags: []string{"Go", "tdd", "TDD"}, File: "everything-wrong.md"},
+		})
+
+		want := []error{
+			blogposts.PostError{File: "blank-title.md", Problem: "title must not be empty"},
+			blogposts.PostError{File: "everything-wrong.md", Problem: "title must not be empty"},
+			blogposts.PostError{File: "everything-wrong.md", Problem: "date is missing"},
+			blogposts.PostError{File: "everything-wrong.md", Problem: `tag "Go" must be lowercase`},
+			blogposts.PostError{File: "everything-wrong.md", Problem: `tag "TDD" must be lowercase`},
+		}
+		if !reflect.DeepEqual(errs, want) {
+			t.Errorf("got %v want %v", errs, want)
+		}
+	})
+
+	t.Run("problems say which file they are in", func(t *testing.T) {
+		err := blogposts.PostError{File: "no-date.md", Problem: "date is missing"}
+
+		if got, want := err.Error(), "no-date.md: date is missing"; got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+}
+
+func TestNewPostsFromFSWithValidation(t *testing.T) {
+	t.Run("fails with every problem in a corpus of broken posts", func(t *testing.T) {
+		_, err := blogposts.NewPostsFromFS(os.DirFS("testdata/invalid-posts"), blogposts.WithValidation())
+
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+
+		for _, problem := range []string{
+			"bad-date.md: could not parse the date",
+			"everything-wrong.md: title must not be empty",
+			"everything-wrong.md: date is missing",
+			`everything-wrong.md: tag "Go" must be lowercase`,
+			"no-date.md: date is missing",
+			"no-title.md: title must not be empty",
+			`shouty-tags.md: tag "Go" must be lowercase`,
+			`shouty-tags.md: tag "TDD" must be lowercase`,
+		} {
+			if !strings.Contains(err.Error(), problem) {
+				t.Errorf("expected the error to mention %q, got\n%v", problem, err)
+			}
+		}
+
+		if strings.Contains(err.Error(), "valid.md") {
+			t.Errorf("did not expect problems with valid.md, got\n%v", err)
+		}
+
+		var postErr blogposts.PostError
+		if !errors.As(err, &postErr) {
+			t.Errorf("expected a PostError in %v", err)
+		}
+	})
+
+	t.Run("valid posts are read as usual", func(t *testing.T) {
+		posts, err := blogposts.NewPostsFromFS(fstest.MapFS{
+			"valid.md": {Data: []byte("---\ntitle: All good\ndate: 2024-03-17\ntags: go\n---\nbody")},
+		}, blogposts.WithValidation())
+
+		assertNoError(t, err)
+		assertTitles(t, posts, "All good")
+	})
+
+	t.Run("without validation broken posts are still read", func(t *testing.T) {
+		posts, err := blogposts.NewPostsFromFS(fstest.MapFS{
+			"no-date.md": {Data: []byte("---\ntitle: Undated\n---\nbody")},
+		})
+
+		assertNoError(t, err)
+		assertTitles(t, posts, "Undated")
+	})
+}
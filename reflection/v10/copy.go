@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrCycle is returned by Copy when a value refers back to itself.
+var ErrCycle = errors.New("cannot copy a value which refers to itself")
+
+// Copy returns a deep copy of v, going through it the same way walk does. Pointers, slices and
+// maps are copied along with everything they point to, and ones shared within v are shared
+// within its copy too. Unexported fields, channels and functions are copied as they are.
+func Copy[T any](v T) (T, error) {
+	c := copier{
+		copies:  map[ref]reflect.Value{},
+		copying: map[ref]bool{},
+	}
+
+	copied, err := c.copyValue(reflect.ValueOf(&v).Elem())
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	var out T
+	reflect.ValueOf(&out).Elem().Set(copied)
+	return out, nil
+}
+
+// ref identifies a pointer, slice or map by what it points to.
+type ref struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+type copier struct {
+	copies  map[ref]reflect.Value
+	copying map[ref]bool
+}
+
+func (c *copier) copyValue(val reflect.Value) (reflect.Value, error) {
+	switch val.Kind() {
+	case reflect.Pointer:
+		if val.IsNil() {
+			return reflect.Zero(val.Type()), nil
+		}
+		return c.copyRef(val, func() (reflect.Value, error) {
+			elem, err := c.copyValue(val.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			copied := reflect.New(val.Type().Elem())
+			copied.Elem().Set(elem)
+			return copied, nil
+		})
+	case reflect.Struct:
+		copied := reflect.New(val.Type()).Elem()
+		copied.Set(val)
+		for i := 0; i < val.NumField(); i++ {
+			if !copied.Field(i).CanSet() {
+				continue
+			}
+			if err := c.copyInto(copied.Field(i), val.Field(i)); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+		return copied, nil
+	case reflect.Slice:
+		if val.IsNil() {
+			return reflect.Zero(val.Type()), nil
+		}
+		return c.copyRef(val, func() (reflect.Value, error) {
+			copied := reflect.MakeSlice(val.Type(), val.Len(), val.Cap())
+			for i := 0; i < val.Len(); i++ {
+				if err := c.copyInto(copied.Index(i), val.Index(i)); err != nil {
+					return reflect.Value{}, err
+				}
+			}
+			return copied, nil
+		})
+	case reflect.Array:
+		copied := reflect.New(val.Type()).Elem()
+		for i := 0; i < val.Len(); i++ {
+			if err := c.copyInto(copied.Index(i), val.Index(i)); err != nil {
+				return reflect.Value{}, err
+			}
+		}
+		return copied, nil
+	case reflect.Map:
+		if val.IsNil() {
+			return reflect.Zero(val.Type()), nil
+		}
+		return c.copyRef(val, func() (reflect.Value, error) {
+			copied := reflect.MakeMapWithSize(val.Type(), val.Len())
+			for iter := val.MapRange(); iter.Next(); {
+				value, err := c.copyValue(iter.Value())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				copied.SetMapIndex(iter.Key(), value)
+			}
+			return copied, nil
+		})
+	case reflect.Interface:
+		if val.IsNil() {
+			return reflect.Zero(val.Type()), nil
+		}
+		copied := reflect.New(val.Type()).Elem()
+		if err := c.copyInto(copied, val.Elem()); err != nil {
+			return reflect.Value{}, err
+		}
+		return copied, nil
+	default:
+		return val, nil
+	}
+}
+
+func (c *copier) copyInto(dst, val reflect.Value) error {
+	copied, err := c.copyValue(val)
+	if err != nil {
+		return err
+	}
+	dst.Set(copied)
+	return nil
+}
+
+// copyRef copies a pointer, slice or map with copy unless it has been copied already, in which
+// case the copy is shared. Coming across it again while it is still being copied means it refers
+// back to itself.
+func (c *copier) copyRef(val reflect.Value, copy func() (reflect.Value, error)) (reflect.Value, error) {
+	r := ref{typ: val.Type(), ptr: val.Pointer()}
+	if val.Kind() == reflect.Slice {
+		r.len = val.Len()
+	}
+
+	if copied, ok := c.copies[r]; ok {
+		return copied, nil
+	}
+	if c.copying[r] {
+		return reflect.Value{}, fmt.Errorf("%w, found a %s inside itself", ErrCycle, val.Type())
+	}
+
+	c.copying[r] = true
+	copied, err := copy()
+	delete(c.copying, r)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	c.copies[r] = copied
+	return copied, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type Team struct {
+	Name    string
+	Captain *Person
+	Members []Person
+	Scores  map[string][]int
+	Extra   interface{}
+	secret  *Profile
+}
+
+func TestCopy(t *testing.T) {
+	newTeam := func() Team {
+		return Team{
+			Name:    "Gophers",
+			Captain: &Person{"Chris", Profile{33, "London"}},
+			Members: []Person{{"Cleo", Profile{34, "Reykjavík"}}},
+			Scores:  map[string][]int{"Chris": {1, 2}},
+			Extra:   &Profile{35, "Berlin"},
+		}
+	}
+
+	t.Run("changing the original doesn't change the copy", func(t *testing.T) {
+		team := newTeam()
+
+		got, err := Copy(team)
+		assertNoError(t, err)
+
+		team.Name = "Rustaceans"
+		team.Captain.Profile.City = "Paris"
+		team.Members[0].Name = "Ruth"
+		team.Scores["Chris"][0] = 100
+		team.Scores["Cleo"] = []int{3}
+		team.Extra.(*Profile).City = "Rome"
+
+		if want := newTeam(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("nothing is shared with the original", func(t *testing.T) {
+		team := newTeam()
+
+		got, err := Copy(&team)
+		assertNoError(t, err)
+
+		if got == &team || got.Captain == team.Captain || got.Extra == team.Extra {
+			t.Error("expected the pointers to be copied")
+		}
+		if &got.Members[0] == &team.Members[0] || &got.Scores["Chris"][0] == &team.Scores["Chris"][0] {
+			t.Error("expected the slices to be copied")
+		}
+	})
+
+	t.Run("nil pointers, slices, maps and interfaces stay nil", func(t *testing.T) {
+		got, err := Copy(Team{Name: "Empty"})
+		assertNoError(t, err)
+
+		if !reflect.DeepEqual(got, Team{Name: "Empty"}) {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("things shared in the original are shared in the copy", func(t *testing.T) {
+		captain := &Person{"Chris", Profile{33, "London"}}
+		teams := []Team{{Captain: captain}, {Captain: captain}}
+
+		got, err := Copy(teams)
+		assertNoError(t, err)
+
+		if got[0].Captain != got[1].Captain {
+			t.Error("expected both copied teams to have the same captain")
+		}
+		if got[0].Captain == captain {
+			t.Error("expected the captain to be copied")
+		}
+	})
+
+	t.Run("unexported fields are copied as they are", func(t *testing.T) {
+		team := Team{secret: &Profile{36, "Oslo"}}
+
+		got, err := Copy(team)
+		assertNoError(t, err)
+
+		if got.secret != team.secret {
+			t.Errorf("got %p want %p", got.secret, team.secret)
+		}
+	})
+
+	t.Run("values which refer to themselves are an error", func(t *testing.T) {
+		type node struct {
+			Name string
+			Next *node
+		}
+		loop := &node{Name: "a", Next: &node{Name: "b"}}
+		loop.Next.Next = loop
+
+		selfish := map[string]interface{}{}
+		selfish["me"] = selfish
+
+		cases := map[string]func() error{
+			"a linked list": func() error {
+				_, err := Copy(loop)
+				return err
+			},
+			"a map": func() error {
+				_, err := Copy(selfish)
+				return err
+			},
+		}
+
+		for name, copyIt := range cases {
+			t.Run(name, func(t *testing.T) {
+				if err := copyIt(); !errors.Is(err, ErrCycle) {
+					t.Errorf("got error %v want %v", err, ErrCycle)
+				}
+			})
+		}
+	})
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("didn't expect an error but got one, %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+)
+
+var addr = flag.String("addr", ":8080", "address to listen on")
+
+func main() {
+	flag.Parse()
+
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, NewServer()))
+}
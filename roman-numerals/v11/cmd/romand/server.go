@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	roman "github.com/quii/learn-go-with-tests/roman-numerals/v11"
+)
+
+// MaxArabic is the largest number Roman Numerals can represent without extra symbols.
+const MaxArabic = 3999
+
+// Conversion is the response to a successful conversion in either direction.
+type Conversion struct {
+	Arabic uint16 `json:"arabic"`
+	Roman  string `json:"roman"`
+}
+
+// ErrorResponse describes why a conversion could not be done.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewServer returns a handler converting numbers to and from Roman Numerals.
+func NewServer() http.Handler {
+	router := http.NewServeMux()
+	router.HandleFunc("GET /roman/{arabic}", toRoman)
+	router.HandleFunc("GET /arabic/{roman}", toArabic)
+	return router
+}
+
+func toRoman(w http.ResponseWriter, r *http.Request) {
+	input := r.PathValue("arabic")
+
+	arabic, err := strconv.ParseUint(input, 10, 16)
+	if err != nil || arabic < 1 || arabic > MaxArabic {
+		writeError(w, fmt.Sprintf("%q is not a whole number between 1 and %d", input, MaxArabic))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Conversion{Arabic: uint16(arabic), Roman: roman.ConvertToRoman(uint16(arabic))})
+}
+
+func toArabic(w http.ResponseWriter, r *http.Request) {
+	input := r.PathValue("roman")
+
+	// ConvertToArabic is lenient, so only accept numerals which convert back to themselves
+	arabic := roman.ConvertToArabic(input)
+	if arabic == 0 || roman.ConvertToRoman(arabic) != input {
+		writeError(w, fmt.Sprintf("%q is not a valid Roman Numeral", input))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Conversion{Arabic: arabic, Roman: input})
+}
+
+func writeError(w http.ResponseWriter, message string) {
+	writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRomand(t *testing.T) {
+	server := NewServer()
+
+	t.Run("converts arabic to roman", func(t *testing.T) {
+		response := get(server, "/roman/1984")
+
+		assertStatus(t, response, http.StatusOK)
+		assertConversion(t, response, Conversion{Arabic: 1984, Roman: "MCMLXXXIV"})
+	})
+
+	t.Run("converts roman to arabic", func(t *testing.T) {
+		response := get(server, "/arabic/MCMLXXXIV")
+
+		assertStatus(t, response, http.StatusOK)
+		assertConversion(t, response, Conversion{Arabic: 1984, Roman: "MCMLXXXIV"})
+	})
+
+	invalid := []struct {
+		name string
+		path string
+	}{
+		{"arabic which isn't a number", "/roman/twelve"},
+		{"zero", "/roman/0"},
+		{"negative numbers", "/roman/-4"},
+		{"numbers too big for roman numerals", "/roman/4000"},
+		{"numbers too big for uint16", "/roman/70000"},
+		{"unknown symbols", "/arabic/ABC"},
+		{"lowercase numerals", "/arabic/xiv"},
+		{"non-canonical numerals", "/arabic/IIII"},
+		{"numerals with trailing junk", "/arabic/XIVZ"},
+	}
+
+	for _, test := range invalid {
+		t.Run("rejects "+test.name, func(t *testing.T) {
+			response := get(server, test.path)
+
+			assertStatus(t, response, http.StatusBadRequest)
+
+			var got ErrorResponse
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Fatalf("could not decode error response %q, %v", response.Body, err)
+			}
+			if got.Error == "" {
+				t.Error("expected an error message")
+			}
+		})
+	}
+
+	t.Run("only GET is allowed", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/roman/10", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusMethodNotAllowed)
+	})
+}
+
+func get(server http.Handler, path string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, path, nil))
+	return response
+}
+
+func assertStatus(t testing.TB, response *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if response.Code != want {
+		t.Errorf("got status %d want %d", response.Code, want)
+	}
+}
+
+func assertConversion(t testing.TB, response *httptest.ResponseRecorder, want Conversion) {
+	t.Helper()
+
+	if got := response.Header().Get("content-type"); got != "application/json" {
+		t.Errorf("got content-type %q want application/json", got)
+	}
+
+	var got Conversion
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatalf("could not decode response %q, %v", response.Body, err)
+	}
+	if got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
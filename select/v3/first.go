@@ -0,0 +1,57 @@
+package racer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrAllFailed is returned by First when none of its operations succeeded.
+var ErrAllFailed = errors.New("every operation failed")
+
+// First races fns, like Racer races urls, but with any kind of operation and result. It returns
+// the result of the first one to succeed and cancels the context the rest were given, so they can
+// stop. If they all fail the error wraps ErrAllFailed and every operation's error, and if ctx is
+// done first its error is returned instead. With no fns there's nothing to succeed, so the error
+// is just ErrAllFailed.
+//
+// First doesn't wait for cancelled operations to return, so they should give up promptly when
+// their context is cancelled.
+func First[T any](ctx context.Context, fns ...func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if len(fns) == 0 {
+		return zero, ErrAllFailed
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		result T
+		err    error
+	}
+
+	// buffered so the operations which lose can still finish after First has returned
+	outcomes := make(chan outcome, len(fns))
+	for _, fn := range fns {
+		go func() {
+			result, err := fn(ctx)
+			outcomes <- outcome{result, err}
+		}()
+	}
+
+	var errs []error
+	for range fns {
+		select {
+		case o := <-outcomes:
+			if o.err == nil {
+				return o.result, nil
+			}
+			errs = append(errs, o.err)
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	return zero, fmt.Errorf("%w: %w", ErrAllFailed, errors.Join(errs...))
+}
@@ -0,0 +1,118 @@
+package racer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFirst(t *testing.T) {
+
+	t.Run("returns the first result to succeed", func(t *testing.T) {
+		got, err := First(context.Background(),
+			after(50*time.Millisecond, "slow", nil),
+			after(0, "fast", nil),
+		)
+
+		if err != nil {
+			t.Fatalf("did not expect an error but got one %v", err)
+		}
+		if got != "fast" {
+			t.Errorf("got %q, want %q", got, "fast")
+		}
+	})
+
+	t.Run("failures don't win, even when they finish first", func(t *testing.T) {
+		got, err := First(context.Background(),
+			after(0, "", errors.New("broken")),
+			after(20*time.Millisecond, "working", nil),
+		)
+
+		if err != nil {
+			t.Fatalf("did not expect an error but got one %v", err)
+		}
+		if got != "working" {
+			t.Errorf("got %q, want %q", got, "working")
+		}
+	})
+
+	t.Run("works with any kind of result", func(t *testing.T) {
+		got, _ := First(context.Background(), func(context.Context) (int, error) { return 42, nil })
+
+		if got != 42 {
+			t.Errorf("got %d, want 42", got)
+		}
+	})
+
+	t.Run("the others are cancelled once one succeeds", func(t *testing.T) {
+		cancelled := make(chan error, 1)
+
+		First(context.Background(),
+			after(0, "fast", nil),
+			func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				cancelled <- ctx.Err()
+				return "", ctx.Err()
+			},
+		)
+
+		select {
+		case err := <-cancelled:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("got %v, want %v", err, context.Canceled)
+			}
+		case <-time.After(time.Second):
+			t.Error("the slow operation was never cancelled")
+		}
+	})
+
+	t.Run("returns every error when they all fail", func(t *testing.T) {
+		dnsErr, refusedErr := errors.New("no such host"), errors.New("connection refused")
+
+		_, err := First(context.Background(),
+			after(0, "", dnsErr),
+			after(10*time.Millisecond, "", refusedErr),
+		)
+
+		for _, want := range []error{ErrAllFailed, dnsErr, refusedErr} {
+			if !errors.Is(err, want) {
+				t.Errorf("got error %v, want it to wrap %v", err, want)
+			}
+		}
+	})
+
+	t.Run("nothing succeeds when there's nothing to run", func(t *testing.T) {
+		_, err := First[string](context.Background())
+
+		if err != ErrAllFailed {
+			t.Errorf("got error %v, want %v", err, ErrAllFailed)
+		}
+	})
+
+	t.Run("gives up when the context times out", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := First(ctx,
+			after(time.Second, "too slow", nil),
+			after(0, "", errors.New("broken")),
+		)
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+// after makes an operation which returns result and err after delay, or gives up if cancelled first.
+func after(delay time.Duration, result string, err error) func(context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		select {
+		case <-time.After(delay):
+			return result, err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package racer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HedgeOption changes how Hedged waits before trying again.
+type HedgeOption func(*hedge)
+
+// WithHedgeTimer replaces time.After as the way Hedged waits for delay, so tests can decide when
+// it has passed.
+func WithHedgeTimer(after func(time.Duration) <-chan time.Time) HedgeOption {
+	return func(h *hedge) {
+		h.after = after
+	}
+}
+
+type hedge struct {
+	after func(time.Duration) <-chan time.Time
+}
+
+// Hedged calls fn and, if it hasn't succeeded within delay, calls it again without waiting for
+// the first attempt, returning the result of whichever succeeds first. Asking twice costs a
+// little more work but saves waiting on the odd attempt that is much slower than the rest.
+//
+// Like First, the attempt that loses has its context cancelled, and a failure doesn't win while
+// the other attempt could still succeed. If the first attempt fails before delay the second is
+// started straight away. If both fail the error wraps ErrAllFailed and both of their errors, and
+// if ctx is done first its error is returned instead.
+func Hedged[T any](ctx context.Context, delay time.Duration, fn func(ctx context.Context) (T, error), options ...HedgeOption) (T, error) {
+	h := hedge{after: time.After}
+	for _, option := range options {
+		option(&h)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		result T
+		err    error
+	}
+
+	// buffered so the attempt which loses can still finish after Hedged has returned
+	outcomes := make(chan outcome, 2)
+	attempts := 0
+	attempt := func() {
+		attempts++
+		go func() {
+			result, err := fn(ctx)
+			outcomes <- outcome{result, err}
+		}()
+	}
+
+	attempt()
+	hedgeAt := h.after(delay)
+
+	var zero T
+	var errs []error
+	for len(errs) < attempts {
+		select {
+		case o := <-outcomes:
+			if o.err == nil {
+				return o.result, nil
+			}
+			errs = append(errs, o.err)
+			if attempts == 1 {
+				hedgeAt = nil
+				attempt()
+			}
+		case <-hedgeAt:
+			hedgeAt = nil
+			attempt()
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+
+	return zero, fmt.Errorf("%w: %w", ErrAllFailed, errors.Join(errs...))
+}
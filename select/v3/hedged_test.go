@@ -0,0 +1,235 @@
+package racer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/concurrency/leaktest"
+)
+
+func TestHedged(t *testing.T) {
+	delay := 50 * time.Millisecond
+
+	t.Run("a quick first attempt wins without trying again", func(t *testing.T) {
+		leaktest.Check(t)
+		attempts, timer := newAttempts(), newFakeTimer()
+		result := hedged(context.Background(), delay, attempts, timer)
+
+		waitFor(t, attempts.started, 0)
+		attempts.answer[0] <- answer{result: "first"}
+
+		assertHedgedResult(t, <-result, "first")
+		assertDelay(t, timer, delay)
+		assertAttempts(t, attempts, 1)
+	})
+
+	t.Run("tries again once the delay has passed, and the quicker attempt wins", func(t *testing.T) {
+		leaktest.Check(t)
+		attempts, timer := newAttempts(), newFakeTimer()
+		result := hedged(context.Background(), delay, attempts, timer)
+
+		waitFor(t, attempts.started, 0)
+		timer.fire <- time.Time{}
+		waitFor(t, attempts.started, 1)
+		attempts.answer[1] <- answer{result: "second"}
+
+		assertHedgedResult(t, <-result, "second")
+		waitFor(t, attempts.cancelled, 0)
+	})
+
+	t.Run("the first attempt can still win after the second has started", func(t *testing.T) {
+		leaktest.Check(t)
+		attempts, timer := newAttempts(), newFakeTimer()
+		result := hedged(context.Background(), delay, attempts, timer)
+
+		waitFor(t, attempts.started, 0)
+		timer.fire <- time.Time{}
+		waitFor(t, attempts.started, 1)
+		attempts.answer[0] <- answer{result: "first"}
+
+		assertHedgedResult(t, <-result, "first")
+		waitFor(t, attempts.cancelled, 1)
+	})
+
+	t.Run("a failure doesn't win while the other attempt could still succeed", func(t *testing.T) {
+		leaktest.Check(t)
+		attempts, timer := newAttempts(), newFakeTimer()
+		result := hedged(context.Background(), delay, attempts, timer)
+
+		waitFor(t, attempts.started, 0)
+		timer.fire <- time.Time{}
+		waitFor(t, attempts.started, 1)
+		attempts.answer[1] <- answer{err: errors.New("broken")}
+		attempts.answer[0] <- answer{result: "first"}
+
+		assertHedgedResult(t, <-result, "first")
+	})
+
+	t.Run("a first attempt that fails before the delay is tried again straight away", func(t *testing.T) {
+		leaktest.Check(t)
+		attempts, timer := newAttempts(), newFakeTimer()
+		result := hedged(context.Background(), delay, attempts, timer)
+
+		waitFor(t, attempts.started, 0)
+		attempts.answer[0] <- answer{err: errors.New("broken")}
+		waitFor(t, attempts.started, 1)
+		attempts.answer[1] <- answer{result: "second"}
+
+		assertHedgedResult(t, <-result, "second")
+	})
+
+	t.Run("returns both errors when both attempts fail", func(t *testing.T) {
+		leaktest.Check(t)
+		attempts, timer := newAttempts(), newFakeTimer()
+		dnsErr, refusedErr := errors.New("no such host"), errors.New("connection refused")
+		result := hedged(context.Background(), delay, attempts, timer)
+
+		waitFor(t, attempts.started, 0)
+		timer.fire <- time.Time{}
+		waitFor(t, attempts.started, 1)
+		attempts.answer[0] <- answer{err: dnsErr}
+		attempts.answer[1] <- answer{err: refusedErr}
+
+		got := <-result
+		for _, want := range []error{ErrAllFailed, dnsErr, refusedErr} {
+			if !errors.Is(got.err, want) {
+				t.Errorf("got error %v, want it to wrap %v", got.err, want)
+			}
+		}
+		assertAttempts(t, attempts, 2)
+	})
+
+	t.Run("gives up when the context is cancelled", func(t *testing.T) {
+		leaktest.Check(t)
+		attempts, timer := newAttempts(), newFakeTimer()
+		ctx, cancel := context.WithCancel(context.Background())
+		result := hedged(ctx, delay, attempts, timer)
+
+		waitFor(t, attempts.started, 0)
+		cancel()
+
+		if got := <-result; !errors.Is(got.err, context.Canceled) {
+			t.Errorf("got error %v, want %v", got.err, context.Canceled)
+		}
+		waitFor(t, attempts.cancelled, 0)
+	})
+
+	t.Run("waits for delay with time.After unless told otherwise", func(t *testing.T) {
+		leaktest.Check(t)
+		calls := atomic.Int32{}
+
+		got, err := Hedged(context.Background(), time.Millisecond, func(ctx context.Context) (string, error) {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return "second", nil
+		})
+
+		if err != nil {
+			t.Fatalf("did not expect an error but got one %v", err)
+		}
+		if got != "second" {
+			t.Errorf("got %q, want %q", got, "second")
+		}
+	})
+}
+
+type answer struct {
+	result string
+	err    error
+}
+
+// attempts is an operation for Hedged which tells the test when each attempt starts, numbering
+// them from 0, and waits for the test to give it an answer or for its context to be cancelled.
+type attempts struct {
+	calls     atomic.Int32
+	started   chan int
+	cancelled chan int
+	answer    [2]chan answer
+}
+
+func newAttempts() *attempts {
+	return &attempts{
+		started:   make(chan int, 2),
+		cancelled: make(chan int, 2),
+		answer:    [2]chan answer{make(chan answer, 1), make(chan answer, 1)},
+	}
+}
+
+func (a *attempts) do(ctx context.Context) (string, error) {
+	n := int(a.calls.Add(1)) - 1
+	a.started <- n
+
+	select {
+	case ans := <-a.answer[n]:
+		return ans.result, ans.err
+	case <-ctx.Done():
+		a.cancelled <- n
+		return "", ctx.Err()
+	}
+}
+
+// fakeTimer's delay has passed whenever the test sends to fire.
+type fakeTimer struct {
+	fire  chan time.Time
+	delay atomic.Int64
+}
+
+func newFakeTimer() *fakeTimer {
+	return &fakeTimer{fire: make(chan time.Time)}
+}
+
+func (f *fakeTimer) after(d time.Duration) <-chan time.Time {
+	f.delay.Store(int64(d))
+	return f.fire
+}
+
+// hedged runs Hedged in the background, so the test can decide how its attempts go.
+func hedged(ctx context.Context, delay time.Duration, a *attempts, timer *fakeTimer) <-chan answer {
+	result := make(chan answer, 1)
+	go func() {
+		got, err := Hedged(ctx, delay, a.do, WithHedgeTimer(timer.after))
+		result <- answer{got, err}
+	}()
+	return result
+}
+
+func waitFor(t testing.TB, ch <-chan int, want int) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		if got != want {
+			t.Fatalf("got attempt %d, want attempt %d", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("attempt %d didn't get there within a second", want)
+	}
+}
+
+func assertHedgedResult(t testing.TB, got answer, want string) {
+	t.Helper()
+	if got.err != nil {
+		t.Fatalf("did not expect an error but got one %v", got.err)
+	}
+	if got.result != want {
+		t.Errorf("got %q, want %q", got.result, want)
+	}
+}
+
+func assertDelay(t testing.TB, timer *fakeTimer, want time.Duration) {
+	t.Helper()
+	if got := time.Duration(timer.delay.Load()); got != want {
+		t.Errorf("waited %v before trying again, want %v", got, want)
+	}
+}
+
+func assertAttempts(t testing.TB, a *attempts, want int) {
+	t.Helper()
+	if got := int(a.calls.Load()); got != want {
+		t.Errorf("got %d attempts, want %d", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package racer
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNoHealthyServers is returned by Podium when none of the urls responded successfully in time.
+var ErrNoHealthyServers = errors.New("no servers responded successfully")
+
+type timing struct {
+	url     string
+	latency time.Duration
+}
+
+// Podium returns up to topN of urls which respond with a 200 OK, fastest first, timing out after 10s.
+func Podium(urls []string, topN int) ([]string, error) {
+	return ConfigurablePodium(urls, topN, tenSecondTimeout)
+}
+
+// ConfigurablePodium returns up to topN of urls which respond with a 200 OK within timeout, fastest first.
+func ConfigurablePodium(urls []string, topN int, timeout time.Duration) ([]string, error) {
+	client := &http.Client{Timeout: timeout}
+	timings := make(chan timing, len(urls))
+
+	var wg sync.WaitGroup
+	for _, url := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if latency, ok := measure(client, url); ok {
+				timings <- timing{url, latency}
+			}
+		}()
+	}
+	wg.Wait()
+	close(timings)
+
+	var healthy []timing
+	for t := range timings {
+		healthy = append(healthy, t)
+	}
+
+	if len(healthy) == 0 {
+		return nil, ErrNoHealthyServers
+	}
+
+	sort.Slice(healthy, func(i, j int) bool {
+		return healthy[i].latency < healthy[j].latency
+	})
+
+	var podium []string
+	for i := 0; i < len(healthy) && i < topN; i++ {
+		podium = append(podium, healthy[i].url)
+	}
+
+	return podium, nil
+}
+
+func measure(client *http.Client, url string) (time.Duration, bool) {
+	start := time.Now()
+	response, err := client.Get(url)
+	if err != nil {
+		return 0, false
+	}
+	response.Body.Close()
+
+	return time.Since(start), response.StatusCode == http.StatusOK
+}
@@ -0,0 +1,93 @@
+package racer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPodium(t *testing.T) {
+
+	t.Run("returns the fastest healthy servers in order", func(t *testing.T) {
+		first := makeDelayedServer(0 * time.Millisecond)
+		second := makeDelayedServer(20 * time.Millisecond)
+		third := makeDelayedServer(40 * time.Millisecond)
+		fourth := makeDelayedServer(60 * time.Millisecond)
+		broken := makeBrokenServer()
+
+		defer first.Close()
+		defer second.Close()
+		defer third.Close()
+		defer fourth.Close()
+		defer broken.Close()
+
+		got, err := Podium([]string{fourth.URL, broken.URL, second.URL, first.URL, third.URL}, 3)
+
+		if err != nil {
+			t.Fatalf("did not expect an error but got one %v", err)
+		}
+
+		want := []string{first.URL, second.URL, third.URL}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("returns fewer than topN when there aren't enough healthy servers", func(t *testing.T) {
+		healthy := makeDelayedServer(0 * time.Millisecond)
+		broken := makeBrokenServer()
+
+		defer healthy.Close()
+		defer broken.Close()
+
+		got, err := Podium([]string{broken.URL, healthy.URL}, 3)
+
+		if err != nil {
+			t.Fatalf("did not expect an error but got one %v", err)
+		}
+
+		want := []string{healthy.URL}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("servers slower than the timeout are left off the podium", func(t *testing.T) {
+		fast := makeDelayedServer(0 * time.Millisecond)
+		slow := makeDelayedServer(50 * time.Millisecond)
+
+		defer fast.Close()
+		defer slow.Close()
+
+		got, err := ConfigurablePodium([]string{slow.URL, fast.URL}, 2, 25*time.Millisecond)
+
+		if err != nil {
+			t.Fatalf("did not expect an error but got one %v", err)
+		}
+
+		want := []string{fast.URL}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("returns an error if no server is healthy", func(t *testing.T) {
+		broken := makeBrokenServer()
+
+		defer broken.Close()
+
+		_, err := Podium([]string{broken.URL}, 1)
+
+		if err != ErrNoHealthyServers {
+			t.Errorf("got error %v, want %v", err, ErrNoHealthyServers)
+		}
+	})
+}
+
+func makeBrokenServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+}
@@ -1,35 +1,118 @@
 package racer
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 )
 
 var tenSecondTimeout = 10 * time.Second
 
+// Measurer returns how long url took to respond.
+type Measurer func(url string) time.Duration
+
+// RacerOption changes how a race is run.
+type RacerOption func(*race)
+
+// WithReport writes a timing report of the race to w once both urls have responded or the race
+// has timed out. The winner is then whichever url the Measurer timed as fastest.
+func WithReport(w io.Writer) RacerOption {
+	return func(r *race) {
+		r.report = w
+	}
+}
+
+// WithMeasurer replaces timing an http.Get of each url, so tests can fake how long urls take.
+func WithMeasurer(measure Measurer) RacerOption {
+	return func(r *race) {
+		r.measure = measure
+	}
+}
+
+type race struct {
+	report  io.Writer
+	measure Measurer
+}
+
 // Racer compares the response times of a and b, returning the fastest one, timing out after 10s.
-func Racer(a, b string) (winner string, error error) {
-	return ConfigurableRacer(a, b, tenSecondTimeout)
+func Racer(a, b string, options ...RacerOption) (winner string, error error) {
+	return ConfigurableRacer(a, b, tenSecondTimeout, options...)
 }
 
 // ConfigurableRacer compares the response times of a and b, returning the fastest one.
-func ConfigurableRacer(a, b string, timeout time.Duration) (winner string, error error) {
+func ConfigurableRacer(a, b string, timeout time.Duration, options ...RacerOption) (winner string, error error) {
+	// cancelled once there's a winner, so the losing request doesn't carry on in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := race{measure: get(ctx)}
+	for _, option := range options {
+		option(&r)
+	}
+
+	if r.report != nil {
+		return r.reportedRace(a, b, timeout)
+	}
+
 	select {
-	case <-ping(a):
+	case <-r.ping(a):
 		return a, nil
-	case <-ping(b):
+	case <-r.ping(b):
 		return b, nil
 	case <-time.After(timeout):
 		return "", fmt.Errorf("timed out waiting for %s and %s", a, b)
 	}
 }
 
-func ping(url string) chan struct{} {
+func (r race) reportedRace(a, b string, timeout time.Duration) (string, error) {
+	timings := make(chan timing, 2)
+	for _, url := range []string{a, b} {
+		go func() {
+			timings <- timing{url, r.measure(url)}
+		}()
+	}
+
+	var finished []timing
+	deadline := time.After(timeout)
+	for len(finished) < 2 {
+		select {
+		case t := <-timings:
+			finished = append(finished, t)
+		case <-deadline:
+			writeReport(r.report, a, b, finished, timeout)
+			if len(finished) == 0 {
+				return "", fmt.Errorf("timed out waiting for %s and %s", a, b)
+			}
+			return finished[0].url, nil
+		}
+	}
+
+	writeReport(r.report, a, b, finished, timeout)
+	return fastest(finished).url, nil
+}
+
+func (r race) ping(url string) chan struct{} {
 	ch := make(chan struct{})
 	go func() {
-		http.Get(url)
+		r.measure(url)
 		close(ch)
 	}()
 	return ch
 }
+
+// get times an http.Get of url, giving up on it when ctx is cancelled.
+func get(ctx context.Context) Measurer {
+	return func(url string) time.Duration {
+		start := time.Now()
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return time.Since(start)
+		}
+		if response, err := http.DefaultClient.Do(request); err == nil {
+			response.Body.Close()
+		}
+		return time.Since(start)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/quii/learn-go-with-tests/concurrency/leaktest"
 )
 
 func TestRacer(t *testing.T) {
 
 	t.Run("compares speeds of servers, returning the url of the fastest one", func(t *testing.T) {
+		leaktest.Check(t)
 		slowServer := makeDelayedServer(20 * time.Millisecond)
 		fastServer := makeDelayedServer(0 * time.Millisecond)
 
@@ -32,6 +35,7 @@ func TestRacer(t *testing.T) {
 	})
 
 	t.Run("returns an error if a server doesn't respond within 10s", func(t *testing.T) {
+		leaktest.Check(t)
 		server := makeDelayedServer(25 * time.Millisecond)
 
 		defer server.Close()
@@ -42,6 +46,40 @@ func TestRacer(t *testing.T) {
 			t.Error("expected an error but didn't get one")
 		}
 	})
+
+	t.Run("gives up on the slower server once there's a winner", func(t *testing.T) {
+		fastServer := makeDelayedServer(0 * time.Millisecond)
+		defer fastServer.Close()
+		hangingServer := makeHangingServer(t)
+		leaktest.Check(t)
+
+		got, err := Racer(hangingServer.URL, fastServer.URL)
+
+		if err != nil {
+			t.Fatalf("did not expect an error but got one %v", err)
+		}
+		if got != fastServer.URL {
+			t.Errorf("got %q, want %q", got, fastServer.URL)
+		}
+	})
+}
+
+// makeHangingServer makes a server which doesn't respond until its client gives up, or the test
+// has finished. It is closed after any checks for leaks have been made, so it can't hide them by
+// waiting for requests that are still running.
+func makeHangingServer(t *testing.T) *httptest.Server {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+	return server
 }
 
 func makeDelayedServer(delay time.Duration) *httptest.Server {
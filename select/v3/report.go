@@ -0,0 +1,51 @@
+package racer
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// writeReport writes each url's latency in the order they were raced, then the winner and the
+// margin it won by. finished holds the urls that responded before the timeout.
+func writeReport(w io.Writer, a, b string, finished []timing, timeout time.Duration) {
+	latencies := map[string]time.Duration{}
+	for _, t := range finished {
+		latencies[t.url] = t.latency
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "URL\tLATENCY")
+	for _, url := range []string{a, b} {
+		if latency, ok := latencies[url]; ok {
+			fmt.Fprintf(table, "%s\t%v\n", url, latency.Round(time.Microsecond))
+		} else {
+			fmt.Fprintf(table, "%s\tno response within %v\n", url, timeout)
+		}
+	}
+	table.Flush()
+
+	switch len(finished) {
+	case 0:
+		fmt.Fprintln(w, "no winner, both timed out")
+	case 1:
+		fmt.Fprintf(w, "winner: %s, the other timed out\n", finished[0].url)
+	default:
+		winner, loser := finished[0], finished[1]
+		if loser.latency < winner.latency {
+			winner, loser = loser, winner
+		}
+		fmt.Fprintf(w, "winner: %s by %v\n", winner.url, (loser.latency - winner.latency).Round(time.Microsecond))
+	}
+}
+
+func fastest(timings []timing) timing {
+	best := timings[0]
+	for _, t := range timings[1:] {
+		if t.latency < best.latency {
+			best = t
+		}
+	}
+	return best
+}
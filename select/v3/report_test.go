@@ -0,0 +1,83 @@
+package racer
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestRacerReport(t *testing.T) {
+	const (
+		fast    = "http://fast.example"
+		slow    = "http://slow.example"
+		stalled = "http://stalled.example"
+	)
+
+	fakeMeasurer := func(url string) time.Duration {
+		switch url {
+		case fast:
+			return 12 * time.Millisecond
+		case slow:
+			return 40*time.Millisecond + 500*time.Microsecond
+		default:
+			time.Sleep(time.Second)
+			return time.Second
+		}
+	}
+
+	cases := []struct {
+		name       string
+		a, b       string
+		wantWinner string
+		wantErr    bool
+	}{
+		{name: "first url wins", a: fast, b: slow, wantWinner: fast},
+		{name: "second url wins", a: slow, b: fast, wantWinner: fast},
+		{name: "one url times out", a: stalled, b: slow, wantWinner: slow},
+		{name: "both urls time out", a: stalled, b: stalled + "/again", wantErr: true},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			var report bytes.Buffer
+
+			got, err := ConfigurableRacer(test.a, test.b, 100*time.Millisecond, WithMeasurer(fakeMeasurer), WithReport(&report))
+
+			if test.wantErr != (err != nil) {
+				t.Fatalf("got error %v, wanted an error: %v", err, test.wantErr)
+			}
+			if got != test.wantWinner {
+				t.Errorf("got winner %q, want %q", got, test.wantWinner)
+			}
+			assertGolden(t, report.Bytes())
+		})
+	}
+}
+
+func assertGolden(t *testing.T, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", filepath.Base(t.Name())+".golden.txt")
+
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("could not read golden file, run the tests with -update to create it, %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run the tests with -update if the change is intended\ngot:\n%s", golden, got)
+	}
+}
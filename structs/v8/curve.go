@@ -0,0 +1,40 @@
+package main
+
+import "math"
+
+// DefaultCurveSteps is how many pieces a Curve is cut into when Steps is not set.
+const DefaultCurveSteps = 1000
+
+// Curve is a closed shape traced by Point as t goes from 0 to 1.
+type Curve struct {
+	Point func(t float64) (x, y float64)
+	// Steps is how many straight lines the curve is approximated with; more steps is more precise.
+	Steps int
+}
+
+// Area approximates the area enclosed by the curve. It walks around the curve adding up the
+// signed area of each slice (Green's theorem, or the "shoelace formula" for the resulting polygon).
+func (c Curve) Area() float64 {
+	steps := c.Steps
+	if steps <= 0 {
+		steps = DefaultCurveSteps
+	}
+
+	sum := 0.0
+	x0, y0 := c.Point(0)
+	for i := 1; i <= steps; i++ {
+		x1, y1 := c.Point(float64(i) / float64(steps))
+		sum += x0*y1 - x1*y0
+		x0, y0 = x1, y1
+	}
+
+	return math.Abs(sum) / 2
+}
+
+// Ellipse returns the parametric function of an ellipse with the given semi-axes, centred on the origin.
+func Ellipse(a, b float64) func(t float64) (x, y float64) {
+	return func(t float64) (float64, float64) {
+		angle := 2 * math.Pi * t
+		return a * math.Cos(angle), b * math.Sin(angle)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCurveArea(t *testing.T) {
+	const tolerance = 1e-4
+
+	curveTests := []struct {
+		name    string
+		curve   Curve
+		hasArea float64
+	}{
+		{name: "Circle", curve: Curve{Point: Ellipse(10, 10)}, hasArea: Circle{Radius: 10}.Area()},
+		{name: "Ellipse", curve: Curve{Point: Ellipse(3, 2)}, hasArea: math.Pi * 3 * 2},
+		{name: "Ellipse traced clockwise", curve: Curve{Point: func(t float64) (float64, float64) {
+			return Ellipse(3, 2)(1 - t)
+		}}, hasArea: math.Pi * 3 * 2},
+		{name: "Square", curve: Curve{Point: square(4), Steps: 4}, hasArea: 16},
+	}
+
+	for _, tt := range curveTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.curve.Area()
+			if !withinTolerance(got, tt.hasArea, tolerance) {
+				t.Errorf("got %g want %g (within %g)", got, tt.hasArea, tolerance)
+			}
+		})
+	}
+
+	t.Run("more steps are more precise", func(t *testing.T) {
+		want := Circle{Radius: 1}.Area()
+		rough := Curve{Point: Ellipse(1, 1), Steps: 10}.Area()
+		fine := Curve{Point: Ellipse(1, 1), Steps: 10000}.Area()
+
+		if math.Abs(fine-want) >= math.Abs(rough-want) {
+			t.Errorf("expected %g to be closer to %g than %g", fine, want, rough)
+		}
+	})
+}
+
+// withinTolerance compares relative to the size of want, as floats are rarely exactly equal.
+func withinTolerance(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance*math.Abs(want)
+}
+
+// square visits the corners of a square with sides of length side at t = 0, 0.25, 0.5, 0.75.
+func square(side float64) func(t float64) (float64, float64) {
+	corners := [][2]float64{{0, 0}, {side, 0}, {side, side}, {0, side}, {0, 0}}
+	return func(t float64) (float64, float64) {
+		c := corners[int(math.Round(t*4))]
+		return c[0], c[1]
+	}
+}
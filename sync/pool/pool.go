@@ -0,0 +1,127 @@
+// Package pool runs tasks on a fixed number of goroutines, fed from a bounded queue. A task
+// that panics is recovered so it can't take its worker, or the program, down with it.
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrStopped is returned when a task is submitted to a pool that has been stopped.
+var ErrStopped = errors.New("pool has been stopped")
+
+// ErrQueueFull is returned by TrySubmit when there is no room left in the queue.
+var ErrQueueFull = errors.New("pool queue is full")
+
+// Task is a piece of work for the pool to run.
+type Task func()
+
+// PanicHandler is called with the value a task panicked with.
+type PanicHandler func(recovered any)
+
+// Option configures a Pool.
+type Option func(*Pool)
+
+// WithPanicHandler sets what happens when a task panics. By default the panic is ignored
+// once it has been recovered.
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(p *Pool) {
+		p.onPanic = handler
+	}
+}
+
+// Pool runs submitted tasks on a fixed number of workers.
+type Pool struct {
+	tasks   chan Task
+	onPanic PanicHandler
+
+	mu      sync.RWMutex
+	stopped bool
+	workers sync.WaitGroup
+}
+
+// New starts a pool of workers goroutines that can hold up to queueSize tasks waiting to run.
+func New(workers, queueSize int, options ...Option) *Pool {
+	if workers < 1 {
+		panic(fmt.Sprintf("pool: need at least 1 worker, got %d", workers))
+	}
+	if queueSize < 0 {
+		panic(fmt.Sprintf("pool: queue size can't be negative, got %d", queueSize))
+	}
+
+	p := &Pool{
+		tasks:   make(chan Task, queueSize),
+		onPanic: func(any) {},
+	}
+
+	for _, option := range options {
+		option(p)
+	}
+
+	p.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+// Submit queues task to be run, waiting for room in the queue if it is full.
+func (p *Pool) Submit(task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.stopped {
+		return ErrStopped
+	}
+
+	p.tasks <- task
+	return nil
+}
+
+// TrySubmit queues task to be run, returning ErrQueueFull rather than waiting if the queue is full.
+func (p *Pool) TrySubmit(task Task) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.stopped {
+		return ErrStopped
+	}
+
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Stop stops the pool accepting tasks and waits for the tasks already queued to finish.
+// It is safe to call more than once.
+func (p *Pool) Stop() {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	p.workers.Wait()
+}
+
+func (p *Pool) work() {
+	defer p.workers.Done()
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task Task) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			p.onPanic(recovered)
+		}
+	}()
+	task()
+}
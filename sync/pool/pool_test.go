@@ -0,0 +1,151 @@
+package pool_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/generics/assert"
+	"github.com/quii/learn-go-with-tests/sync/pool"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("runs every submitted task", func(t *testing.T) {
+		p := pool.New(4, 10)
+
+		var ran atomic.Int64
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, p.Submit(func() { ran.Add(1) }))
+		}
+		p.Stop()
+
+		if got := ran.Load(); got != 100 {
+			t.Errorf("got %d tasks run, want 100", got)
+		}
+	})
+
+	t.Run("runs no more tasks at once than there are workers", func(t *testing.T) {
+		const workers = 3
+		p := pool.New(workers, 0)
+
+		var running, most atomic.Int64
+		for i := 0; i < 30; i++ {
+			p.Submit(func() {
+				now := running.Add(1)
+				for {
+					seen := most.Load()
+					if now <= seen || most.CompareAndSwap(seen, now) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				running.Add(-1)
+			})
+		}
+		p.Stop()
+
+		if got := most.Load(); got > workers {
+			t.Errorf("got %d tasks running at once, want at most %d", got, workers)
+		}
+	})
+
+	t.Run("a panicking task doesn't stop its worker", func(t *testing.T) {
+		var panics []any
+		var mu sync.Mutex
+		p := pool.New(1, 2, pool.WithPanicHandler(func(recovered any) {
+			mu.Lock()
+			defer mu.Unlock()
+			panics = append(panics, recovered)
+		}))
+
+		ranAfterPanic := false
+		p.Submit(func() { panic("oh no") })
+		p.Submit(func() { ranAfterPanic = true })
+		p.Stop()
+
+		if !ranAfterPanic {
+			t.Error("task after the panic wasn't run")
+		}
+		if len(panics) != 1 || panics[0] != "oh no" {
+			t.Errorf("got panics %v, want [oh no]", panics)
+		}
+	})
+
+	t.Run("Stop waits for queued tasks to finish", func(t *testing.T) {
+		p := pool.New(1, 5)
+
+		var ran atomic.Int64
+		for i := 0; i < 5; i++ {
+			p.Submit(func() {
+				time.Sleep(2 * time.Millisecond)
+				ran.Add(1)
+			})
+		}
+		p.Stop()
+
+		if got := ran.Load(); got != 5 {
+			t.Errorf("got %d tasks finished when Stop returned, want 5", got)
+		}
+	})
+
+	t.Run("refuses tasks once stopped", func(t *testing.T) {
+		p := pool.New(1, 1)
+		p.Stop()
+		p.Stop()
+
+		assert.ErrorIs(t, p.Submit(func() {}), pool.ErrStopped)
+		assert.ErrorIs(t, p.TrySubmit(func() {}), pool.ErrStopped)
+	})
+
+	t.Run("TrySubmit doesn't wait for a full queue", func(t *testing.T) {
+		p := pool.New(1, 1)
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		p.Submit(func() {
+			close(started)
+			<-release
+		})
+		<-started
+		assert.NoError(t, p.TrySubmit(func() {}))
+		assert.ErrorIs(t, p.TrySubmit(func() {}), pool.ErrQueueFull)
+
+		close(release)
+		p.Stop()
+	})
+
+	t.Run("Submit and Stop can be called concurrently", func(t *testing.T) {
+		p := pool.New(2, 1)
+
+		var submitters sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			submitters.Add(1)
+			go func() {
+				defer submitters.Done()
+				for j := 0; j < 10; j++ {
+					if err := p.Submit(func() {}); err != nil && !errors.Is(err, pool.ErrStopped) {
+						t.Errorf("unexpected error %v", err)
+					}
+				}
+			}()
+		}
+
+		p.Stop()
+		submitters.Wait()
+	})
+}
+
+func BenchmarkPool(b *testing.B) {
+	p := pool.New(8, 64)
+	var wg sync.WaitGroup
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		p.Submit(func() { wg.Done() })
+	}
+	wg.Wait()
+	p.Stop()
+}
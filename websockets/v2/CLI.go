@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
+
+	"github.com/quii/learn-go-with-tests/http-server/playername"
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
 )
 
 // CLI helps players through a game of poker.
@@ -15,15 +17,28 @@ type CLI struct {
 	in          *bufio.Scanner
 	out         io.Writer
 	game        Game
+	language    Language
+	maxAttempts int
 }
 
-// NewCLI creates a CLI for playing poker.
-func NewCLI(in io.Reader, out io.Writer, game Game) *CLI {
-	return &CLI{
-		in:   bufio.NewScanner(in),
-		out:  out,
-		game: game,
+// CLIOption configures optional behaviour of a CLI.
+type CLIOption func(*CLI)
+
+// NewCLI creates a CLI for playing poker, talking to players in English unless given WithLanguage.
+func NewCLI(in io.Reader, out io.Writer, game Game, options ...CLIOption) *CLI {
+	cli := &CLI{
+		in:          bufio.NewScanner(in),
+		out:         out,
+		game:        game,
+		language:    English,
+		maxAttempts: defaultMaxAttempts,
+	}
+
+	for _, option := range options {
+		option(cli)
 	}
+
+	return cli
 }
 
 // PlayerPrompt is the text asking the user for the number of players.
@@ -35,38 +50,149 @@ const BadPlayerInputErrMsg = "Bad value received for number of players, please t
 // BadWinnerInputMsg is the text telling the user they declared the winner wrong.
 const BadWinnerInputMsg = "invalid winner input, expect format of 'PlayerName wins'"
 
-// PlayPoker starts the game.
-func (cli *CLI) PlayPoker() {
-	fmt.Fprint(cli.out, PlayerPrompt)
+// defaultMaxAttempts is how many goes players get at entering something before the CLI gives up.
+const defaultMaxAttempts = 3
 
-	numberOfPlayers, err := strconv.Atoi(cli.readLine())
+// WithMaxAttempts sets how many times players can enter the number of players, or declare the
+// winner, wrongly before the CLI gives up on the game.
+func WithMaxAttempts(attempts int) CLIOption {
+	return func(cli *CLI) {
+		cli.maxAttempts = attempts
+	}
+}
 
-	if err != nil {
-		fmt.Fprint(cli.out, BadPlayerInputErrMsg)
+// PlayPoker starts the game. Entering the players' names rather than how many there are seats
+// them at a Table, and until someone wins the players can then move the dealer button with
+// NextHandCommand or knock someone out with "{Name} is out". If the game is a StackKeeper they
+// can also buy in, rebuy, add on and say how many chips they have, as in "Chris rebuys 500".
+// Players who enter something wrong are told so and can try again, until they run out of attempts.
+func (cli *CLI) PlayPoker() {
+	numberOfPlayers, table, ok := cli.askForPlayers()
+
+	if !ok {
 		return
 	}
 
 	cli.game.Start(numberOfPlayers, cli.out)
 
-	winnerInput := cli.readLine()
-	winner, err := extractWinner(winnerInput)
+	if table != nil {
+		cli.showSeating(table.Seating())
+	}
 
-	if err != nil {
-		fmt.Fprint(cli.out, BadWinnerInputMsg)
+	for badWinners := 0; badWinners < cli.maxAttempts; {
+		input, ok := cli.readLine()
+
+		if !ok {
+			return
+		}
+
+		if table != nil {
+			if ok, err := cli.playAtTable(table, input); ok {
+				if err != nil {
+					fmt.Fprintln(cli.out, translateError(cli.language, err))
+				}
+				continue
+			}
+		}
+
+		winner, err := extractWinner(input)
+
+		if err != nil {
+			if apperrors.IsInvalid(err) {
+				fmt.Fprintln(cli.out, translateError(cli.language, err))
+			} else {
+				fmt.Fprintln(cli.out, cli.translate(BadWinnerInputMsg))
+			}
+			badWinners++
+			continue
+		}
+
+		cli.game.Finish(winner)
 		return
 	}
+}
+
+// askForPlayers prompts for the players until they are entered correctly, reporting false if
+// they still weren't after the last attempt or there was nothing more to read.
+func (cli *CLI) askForPlayers() (numberOfPlayers int, table *Table, ok bool) {
+	for attempt := 1; attempt <= cli.maxAttempts; attempt++ {
+		fmt.Fprint(cli.out, cli.translate(PlayerPrompt))
+
+		input, ok := cli.readLine()
+
+		if !ok {
+			return 0, nil, false
+		}
+
+		numberOfPlayers, table, err := parsePlayers(input)
+
+		if err == nil {
+			return numberOfPlayers, table, true
+		}
+
+		fmt.Fprintln(cli.out, translateError(cli.language, err))
+	}
+
+	return 0, nil, false
+}
+
+// playAtTable carries out a command given at table, showing the new seating or standings,
+// reporting whether it was one.
+func (cli *CLI) playAtTable(table *Table, input string) (ok bool, err error) {
+	if ok, err := table.play(input); ok {
+		if err == nil {
+			cli.showSeating(table.Seating())
+		}
+		return true, err
+	}
+
+	if ok, err := playStack(cli.game, table, input); ok {
+		if err == nil {
+			cli.showStandings(cli.game.(StackKeeper).Standings())
+		}
+		return true, err
+	}
+
+	return false, nil
+}
+
+func (cli *CLI) showStandings(standings []Stack) {
+	for _, stack := range standings {
+		fmt.Fprintf(cli.out, cli.translate("%s: %d chips")+"\n", stack.Player, stack.Chips)
+	}
+}
 
-	cli.game.Finish(winner)
+func (cli *CLI) showSeating(seating Seating) {
+	for _, seat := range seating.Seats {
+		format := "Seat %d: %s"
+		if seat.Number == seating.Dealer {
+			format = "Seat %d: %s (dealer)"
+		}
+		fmt.Fprintf(cli.out, cli.translate(format)+"\n", seat.Number, seat.Player)
+	}
 }
 
+// extractWinner reads the winner's name from "{Name} wins", tidied up as playername does.
 func extractWinner(userInput string) (string, error) {
 	if !strings.Contains(userInput, " wins") {
 		return "", errors.New(BadWinnerInputMsg)
 	}
-	return strings.Replace(userInput, " wins", "", 1), nil
+
+	winner, err := playername.Normalize(strings.Replace(userInput, " wins", "", 1))
+	if err != nil {
+		return "", apperrors.Invalid("winner", err.Error(), nil)
+	}
+	return winner, nil
 }
 
-func (cli *CLI) readLine() string {
-	cli.in.Scan()
-	return cli.in.Text()
+func (cli *CLI) translate(message string) string {
+	return Translate(cli.language, message)
+}
+
+// readLine returns the next line the players entered, or false once there are no more.
+func (cli *CLI) readLine() (string, bool) {
+	if !cli.in.Scan() {
+		return "", false
+	}
+	return cli.in.Text(), true
 }
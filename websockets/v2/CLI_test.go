@@ -3,10 +3,11 @@ package poker_test
 import (
 	"bytes"
 	"io"
+	"reflect"
 	"strings"
 	"testing"
-	"time"
 
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
 	poker "github.com/quii/learn-go-with-tests/websockets/v2"
 )
 
@@ -15,26 +16,6 @@ var dummyPlayerStore = &poker.StubPlayerStore{}
 var dummyStdIn = &bytes.Buffer{}
 var dummyStdOut = &bytes.Buffer{}
 
-type GameSpy struct {
-	StartCalled     bool
-	StartCalledWith int
-	BlindAlert      []byte
-
-	FinishedCalled   bool
-	FinishCalledWith string
-}
-
-func (g *GameSpy) Start(numberOfPlayers int, out io.Writer) {
-	g.StartCalled = true
-	g.StartCalledWith = numberOfPlayers
-	out.Write(g.BlindAlert)
-}
-
-func (g *GameSpy) Finish(winner string) {
-	g.FinishedCalled = true
-	g.FinishCalledWith = winner
-}
-
 func userSends(messages ...string) io.Reader {
 	return strings.NewReader(strings.Join(messages, "\n"))
 }
@@ -42,7 +23,7 @@ func userSends(messages ...string) io.Reader {
 func TestCLI(t *testing.T) {
 
 	t.Run("start game with 3 players and finish game with 'Chris' as winner", func(t *testing.T) {
-		game := &GameSpy{}
+		game := &poker.GameSpy{}
 
 		out := &bytes.Buffer{}
 		in := userSends("3", "Chris wins")
@@ -55,7 +36,7 @@ func TestCLI(t *testing.T) {
 	})
 
 	t.Run("start game with 8 players and record 'Cleo' as winner", func(t *testing.T) {
-		game := &GameSpy{}
+		game := &poker.GameSpy{}
 
 		in := userSends("8", "Cleo wins")
 
@@ -66,7 +47,7 @@ func TestCLI(t *testing.T) {
 	})
 
 	t.Run("it prints an error when a non numeric value is entered and does not start the game", func(t *testing.T) {
-		game := &GameSpy{}
+		game := &poker.GameSpy{}
 
 		out := &bytes.Buffer{}
 		in := userSends("pies")
@@ -74,11 +55,28 @@ func TestCLI(t *testing.T) {
 		poker.NewCLI(in, out, game).PlayPoker()
 
 		assertGameNotStarted(t, game)
-		assertMessagesSentToUser(t, out, poker.PlayerPrompt, poker.BadPlayerInputErrMsg)
+		assertMessagesSentToUser(t, out, poker.PlayerPrompt, poker.BadPlayerInputErrMsg+"\n", poker.PlayerPrompt)
+	})
+
+	t.Run("it prints what's wrong with the players' names and asks again", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("Chris, Chris", "Chris, ", "Chris, Cleo", "Chris wins")
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertGameStartedWith(t, game, 2)
+		assertMessagesSentToUser(t, out,
+			poker.PlayerPrompt, "player \"Chris\" conflicts with an existing one\n",
+			poker.PlayerPrompt, "invalid name: must not be empty\n",
+			poker.PlayerPrompt,
+			"Seat 1: Chris (dealer)\nSeat 2: Cleo\n",
+		)
 	})
 
 	t.Run("it prints an error when the winner is declared incorrectly", func(t *testing.T) {
-		game := &GameSpy{}
+		game := &poker.GameSpy{}
 
 		out := &bytes.Buffer{}
 		in := userSends("8", "Lloyd is a killer")
@@ -86,45 +84,219 @@ func TestCLI(t *testing.T) {
 		poker.NewCLI(in, out, game).PlayPoker()
 
 		assertGameNotFinished(t, game)
-		assertMessagesSentToUser(t, out, poker.PlayerPrompt, poker.BadWinnerInputMsg)
+		assertMessagesSentToUser(t, out, poker.PlayerPrompt, poker.BadWinnerInputMsg+"\n")
 	})
-}
 
-func assertGameStartedWith(t testing.TB, game *GameSpy, numberOfPlayersWanted int) {
-	t.Helper()
+	t.Run("it records the winner under the tidied up name", func(t *testing.T) {
+		game := &poker.GameSpy{}
 
-	passed := retryUntil(500*time.Millisecond, func() bool {
-		return game.StartCalledWith == numberOfPlayersWanted
+		in := userSends("3", "\u00a0Chris\u3000 Smith  wins")
+
+		poker.NewCLI(in, dummyStdOut, game).PlayPoker()
+
+		assertFinishCalledWith(t, game, "Chris Smith")
 	})
 
-	if !passed {
-		t.Errorf("wanted Start called with %d but got %d", numberOfPlayersWanted, game.StartCalledWith)
-	}
+	t.Run("it prints why a winner's name can't be used", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("3", "Chris\u202eSmith wins", strings.Repeat("a", 33)+" wins", "Chris wins")
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertFinishCalledWith(t, game, "Chris")
+		assertMessagesSentToUser(t, out,
+			poker.PlayerPrompt,
+			"invalid winner: cannot contain control characters\n",
+			"invalid winner: must be at most 32 characters\n",
+		)
+	})
+
+	t.Run("it asks for the number of players again until it gets a good one", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("pies", "Chris", "5", "Chris wins")
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertGameStartedWith(t, game, 5)
+		assertFinishCalledWith(t, game, "Chris")
+		assertMessagesSentToUser(t, out,
+			poker.PlayerPrompt, poker.BadPlayerInputErrMsg+"\n",
+			poker.PlayerPrompt, poker.BadPlayerInputErrMsg+"\n",
+			poker.PlayerPrompt,
+		)
+	})
+
+	t.Run("it waits for the winner to be declared properly", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("3", "Lloyd is a killer", "Cleo won", "Cleo wins")
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertGameStartedWith(t, game, 3)
+		assertFinishCalledWith(t, game, "Cleo")
+		assertMessagesSentToUser(t, out, poker.PlayerPrompt, poker.BadWinnerInputMsg+"\n", poker.BadWinnerInputMsg+"\n")
+	})
+
+	t.Run("it gives up after too many bad attempts", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("pies", "cake", "3")
+
+		poker.NewCLI(in, out, game, poker.WithMaxAttempts(2)).PlayPoker()
+
+		assertGameNotStarted(t, game)
+		assertMessagesSentToUser(t, out,
+			poker.PlayerPrompt, poker.BadPlayerInputErrMsg+"\n",
+			poker.PlayerPrompt, poker.BadPlayerInputErrMsg+"\n",
+		)
+	})
+
+	t.Run("it doesn't finish the game after too many badly declared winners", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		in := userSends("3", "Lloyd is a killer", "Cleo won", "Cleo wins")
+
+		poker.NewCLI(in, dummyStdOut, game, poker.WithMaxAttempts(2)).PlayPoker()
+
+		assertGameStartedWith(t, game, 3)
+		assertGameNotFinished(t, game)
+	})
+
+	t.Run("table commands don't use up attempts at declaring the winner", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		in := userSends("Chris, Cleo", "Cleo won", poker.NextHandCommand, "Lloyd is out", "Cleo wins")
+
+		poker.NewCLI(in, dummyStdOut, game, poker.WithMaxAttempts(2)).PlayPoker()
+
+		assertFinishCalledWith(t, game, "Cleo")
+	})
+
+	t.Run("naming the players seats them and shows the dealer button moving round", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("Chris, Cleo, Ruth", poker.NextHandCommand, "Cleo is out", poker.NextHandCommand, "Chris wins")
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertGameStartedWith(t, game, 3)
+		assertFinishCalledWith(t, game, "Chris")
+		assertMessagesSentToUser(t, out,
+			poker.PlayerPrompt,
+			"Seat 1: Chris (dealer)\nSeat 2: Cleo\nSeat 3: Ruth\n",
+			"Seat 1: Chris\nSeat 2: Cleo (dealer)\nSeat 3: Ruth\n",
+			"Seat 1: Chris\nSeat 3: Ruth\n",
+			"Seat 1: Chris\nSeat 3: Ruth (dealer)\n",
+		)
+	})
+
+	t.Run("it prints an error when someone who isn't seated is knocked out", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("Chris, Cleo", "Lloyd is out", "Chris wins")
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertFinishCalledWith(t, game, "Chris")
+		assertMessagesSentToUser(t, out,
+			poker.PlayerPrompt,
+			"Seat 1: Chris (dealer)\nSeat 2: Cleo\n",
+			"player \"Lloyd\" not found\n",
+		)
+	})
+
+	t.Run("seated players can change their stacks, which shows the standings", func(t *testing.T) {
+		game := &poker.GameSpy{Stacks: []poker.Stack{{Player: "Chris", Chips: 1500}, {Player: "Cleo", Chips: 500}}}
+
+		out := &bytes.Buffer{}
+		in := userSends("Chris, Cleo", "Chris buys in 1000", "Cleo rebuys 500", "Chris adds on 500", "Chris has 1500", "Chris wins")
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertFinishCalledWith(t, game, "Chris")
+		assertStackChanges(t, game,
+			poker.StackChange{Change: "buys in", Player: "Chris", Chips: 1000},
+			poker.StackChange{Change: "rebuys", Player: "Cleo", Chips: 500},
+			poker.StackChange{Change: "adds on", Player: "Chris", Chips: 500},
+			poker.StackChange{Change: "has", Player: "Chris", Chips: 1500},
+		)
+		standings := "Chris: 1500 chips\nCleo: 500 chips\n"
+		assertMessagesSentToUser(t, out, poker.PlayerPrompt, "Seat 1: Chris (dealer)\nSeat 2: Cleo\n", standings, standings, standings, standings)
+	})
+
+	t.Run("it prints an error when a stack can't be changed", func(t *testing.T) {
+		game := &poker.GameSpy{StackErr: apperrors.Invalid("rebuy", "the rebuy period is over", nil)}
+
+		out := &bytes.Buffer{}
+		in := userSends("Chris, Cleo", "Lloyd rebuys 500", "Chris rebuys lots", "Chris rebuys 500", "Chris wins")
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertFinishCalledWith(t, game, "Chris")
+		assertStackChanges(t, game, poker.StackChange{Change: "rebuys", Player: "Chris", Chips: 500})
+		assertMessagesSentToUser(t, out,
+			poker.PlayerPrompt,
+			"Seat 1: Chris (dealer)\nSeat 2: Cleo\n",
+			"player \"Lloyd\" not found\n",
+			"invalid chips: must be a whole number\n",
+			"invalid rebuy: the rebuy period is over\n",
+		)
+	})
+
+	t.Run("without named players there is no table to give commands to", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		out := &bytes.Buffer{}
+		in := userSends("3", poker.NextHandCommand)
+
+		poker.NewCLI(in, out, game).PlayPoker()
+
+		assertGameNotFinished(t, game)
+		assertMessagesSentToUser(t, out, poker.PlayerPrompt, poker.BadWinnerInputMsg+"\n")
+	})
 }
 
-func assertGameNotFinished(t testing.TB, game *GameSpy) {
+func assertStackChanges(t testing.TB, game *poker.GameSpy, want ...poker.StackChange) {
 	t.Helper()
-	if game.FinishedCalled {
-		t.Errorf("game should not have finished")
+	if got := game.StackChanges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got stack changes %v want %v", got, want)
 	}
 }
 
-func assertGameNotStarted(t testing.TB, game *GameSpy) {
+func assertGameStartedWith(t testing.TB, game *poker.GameSpy, numberOfPlayersWanted int) {
 	t.Helper()
-	if game.StartCalled {
-		t.Errorf("game should not have started")
+	if got := game.WaitForStart(t); got != numberOfPlayersWanted {
+		t.Errorf("wanted Start called with %d but got %d", numberOfPlayersWanted, got)
 	}
 }
 
-func assertFinishCalledWith(t testing.TB, game *GameSpy, winner string) {
+func assertGameNotFinished(t testing.TB, game *poker.GameSpy) {
 	t.Helper()
+	if calls := game.FinishCalls(); len(calls) > 0 {
+		t.Errorf("game should not have finished but got %v", calls)
+	}
+}
 
-	passed := retryUntil(500*time.Millisecond, func() bool {
-		return game.FinishCalledWith == winner
-	})
+func assertGameNotStarted(t testing.TB, game *poker.GameSpy) {
+	t.Helper()
+	if calls := game.StartCalls(); len(calls) > 0 {
+		t.Errorf("game should not have started but got %v", calls)
+	}
+}
 
-	if !passed {
-		t.Errorf("expected finish called with %q but got %q", winner, game.FinishCalledWith)
+func assertFinishCalledWith(t testing.TB, game *poker.GameSpy, winner string) {
+	t.Helper()
+	if got := game.WaitForFinish(t); got != winner {
+		t.Errorf("expected finish called with %q but got %q", winner, got)
 	}
 }
 
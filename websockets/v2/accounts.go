@@ -0,0 +1,244 @@
+package poker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	sessionCookieName    = "poker_session"
+	loginTemplatePath    = "login.html"
+	defaultSessionLength = 24 * time.Hour
+)
+
+// WithAccounts makes people register and log in before they can play a game or record a win,
+// so the server knows which player it is talking to. Accounts are kept in users.
+func WithAccounts(users UserStore, options ...AccountsOption) PlayerServerOption {
+	return func(p *PlayerServer) {
+		a := &accounts{users: users, sessions: newSessions(defaultSessionLength, realClock{})}
+		for _, option := range options {
+			option(a)
+		}
+		p.accounts = a
+	}
+}
+
+// AccountsOption changes how WithAccounts handles accounts.
+type AccountsOption func(*accounts)
+
+// WithSessionLength logs people out once they logged in length ago. It is a day otherwise.
+// Times are told by clock, which is the real one if nil.
+func WithSessionLength(length time.Duration, clock Clock) AccountsOption {
+	return func(a *accounts) {
+		a.sessions.length = length
+		if clock != nil {
+			a.sessions.clock = clock
+		}
+	}
+}
+
+// WithPasswordHashing hashes the passwords of people who register with options, such as
+// WithHashIterations, and checks logins for unknown names against a hash made the same way.
+func WithPasswordHashing(options ...UserOption) AccountsOption {
+	return func(a *accounts) {
+		a.hashing = options
+	}
+}
+
+// accounts handles registering, logging in and out, and remembering who is logged in.
+type accounts struct {
+	users    UserStore
+	sessions *sessions
+	login    *template.Template
+	hashing  []UserOption
+}
+
+func (a *accounts) routes() routingTable {
+	return routingTable{
+		{"/login", []string{http.MethodGet, http.MethodPost}, http.HandlerFunc(a.loginHandler)},
+		{"/register", []string{http.MethodPost}, http.HandlerFunc(a.register)},
+		{"/logout", []string{http.MethodPost}, http.HandlerFunc(a.logout)},
+	}
+}
+
+func (a *accounts) loginHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		a.login.Execute(w, nil)
+		return
+	}
+
+	name := r.FormValue("name")
+	user, err := a.users.GetUser(name)
+	if err != nil {
+		// check the password anyway, so unknown names take as long to turn away as wrong passwords
+		user = dummyUser(a.hashing...)
+	}
+
+	if !user.PasswordMatches(r.FormValue("password")) || err != nil {
+		writeMessage(w, r, "incorrect name or password", http.StatusUnauthorized)
+		return
+	}
+
+	a.startSession(w, r, name)
+}
+
+func (a *accounts) register(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+
+	user, err := NewUser(name, r.FormValue("password"), a.hashing...)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := a.users.AddUser(user); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	a.startSession(w, r, name)
+}
+
+func (a *accounts) logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		a.sessions.end(cookie.Value)
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Path: "/", MaxAge: -1})
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+func (a *accounts) startSession(w http.ResponseWriter, r *http.Request, name string) {
+	token, err := a.sessions.start(name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(a.sessions.length / time.Second),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/game", http.StatusSeeOther)
+}
+
+// identify is middleware which remembers who is logged in on the request's context.
+func (a *accounts) identify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(sessionCookieName); err == nil {
+			if name, ok := a.sessions.lookup(cookie.Value); ok {
+				r = r.WithContext(context.WithValue(r.Context(), playerKey{}, name))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type playerKey struct{}
+
+// sessionToken returns the token in r's session cookie, or "" if it doesn't have one.
+func sessionToken(r *http.Request) string {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// loggedInPlayer returns the name of the player who made r, if they are logged in.
+func loggedInPlayer(r *http.Request) (string, bool) {
+	name, ok := r.Context().Value(playerKey{}).(string)
+	return name, ok
+}
+
+// sessions maps the random tokens stored in cookies to who they were given to, until they expire.
+type sessions struct {
+	lock   sync.Mutex
+	owners map[string]session
+	length time.Duration
+	clock  Clock
+}
+
+type session struct {
+	name    string
+	expires time.Time
+}
+
+func newSessions(length time.Duration, clock Clock) *sessions {
+	return &sessions{owners: map[string]session{}, length: length, clock: clock}
+}
+
+func (s *sessions) start(name string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("problem generating session token, %v", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.removeExpired()
+	s.owners[token] = session{name: name, expires: s.clock.Now().Add(s.length)}
+
+	return token, nil
+}
+
+func (s *sessions) lookup(token string) (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	owner, ok := s.owners[token]
+	if !ok {
+		return "", false
+	}
+	if !s.clock.Now().Before(owner.expires) {
+		delete(s.owners, token)
+		return "", false
+	}
+	return owner.name, true
+}
+
+func (s *sessions) end(token string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.owners, token)
+	s.removeExpired()
+}
+
+// removeExpired forgets the sessions of people who never came back to log out. The lock must be held.
+func (s *sessions) removeExpired() {
+	now := s.clock.Now()
+	for token, owner := range s.owners {
+		if !now.Before(owner.expires) {
+			delete(s.owners, token)
+		}
+	}
+}
+
+// requireLogin reports whether a request may go ahead, responding if it may not. Without
+// accounts everyone may play.
+func (p *PlayerServer) requireLogin(w http.ResponseWriter, r *http.Request) bool {
+	if p.accounts == nil {
+		return true
+	}
+
+	if _, ok := loggedInPlayer(r); ok {
+		return true
+	}
+
+	if r.Method == http.MethodGet {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	} else {
+		writeMessage(w, r, "you must be logged in", http.StatusUnauthorized)
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package poker_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestAccounts(t *testing.T) {
+	users := poker.NewInMemoryUserStore()
+	if err := users.AddUser(mustMakeUser(t, "Chris", "correct horse")); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &poker.StubPlayerStore{}
+	clock := &fakeClock{now: time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)}
+	server, err := poker.NewPlayerServer(store, dummyGame, poker.WithAccounts(users,
+		poker.WithSessionLength(time.Hour, clock),
+		poker.WithPasswordHashing(fastHashing),
+	))
+	if err != nil {
+		t.Fatal("problem creating player server", err)
+	}
+
+	t.Run("the login page is served", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+		assertStatus(t, response, http.StatusOK)
+	})
+
+	t.Run("registering logs you in", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newFormRequest("/register", "Cleo", "another horse"))
+
+		assertStatus(t, response, http.StatusSeeOther)
+		assertHeader(t, response, "Location", "/game")
+		assertGamePlayer(t, server, sessionCookie(t, response), "Cleo")
+	})
+
+	t.Run("registering a taken name is a conflict", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newFormRequest("/register", "Chris", "correct horse"))
+
+		assertStatus(t, response, http.StatusConflict)
+	})
+
+	t.Run("registering with a short password is rejected", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newFormRequest("/register", "Ruth", "short"))
+
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+
+	t.Run("logging in with the right password starts a session", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newFormRequest("/login", "Chris", "correct horse"))
+
+		assertStatus(t, response, http.StatusSeeOther)
+		assertGamePlayer(t, server, sessionCookie(t, response), "Chris")
+	})
+
+	t.Run("logging in with the wrong password or an unknown name is unauthorised", func(t *testing.T) {
+		for _, name := range []string{"Chris", "Apollo"} {
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, newFormRequest("/login", name, "battery staple"))
+
+			assertStatus(t, response, http.StatusUnauthorized)
+			if len(response.Result().Cookies()) != 0 {
+				t.Errorf("did not expect a cookie for %s", name)
+			}
+		}
+	})
+
+	t.Run("the game page sends you to log in first", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/game", nil))
+
+		assertStatus(t, response, http.StatusSeeOther)
+		assertHeader(t, response, "Location", "/login")
+	})
+
+	t.Run("wins can only be recorded when logged in", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newPostWinRequest("Chris"))
+		assertStatus(t, response, http.StatusUnauthorized)
+
+		cookie := logIn(t, server, "Chris", "correct horse")
+
+		request := newPostWinRequest("Chris")
+		request.AddCookie(cookie)
+		response = httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusAccepted)
+		if got := store.WinCallsFor("Chris"); got != 1 {
+			t.Errorf("got %d wins for Chris want 1", got)
+		}
+	})
+
+	t.Run("players can't record wins for someone else", func(t *testing.T) {
+		cookie := logIn(t, server, "Chris", "correct horse")
+
+		request := newPostWinRequest("Pepper")
+		request.AddCookie(cookie)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusForbidden)
+		if got := store.WinCallsFor("Pepper"); got != 0 {
+			t.Errorf("got %d wins for Pepper want 0", got)
+		}
+	})
+
+	t.Run("sessions expire", func(t *testing.T) {
+		cookie := logIn(t, server, "Chris", "correct horse")
+		if cookie.MaxAge != int(time.Hour/time.Second) {
+			t.Errorf("got cookie max age %d want an hour", cookie.MaxAge)
+		}
+
+		clock.advance(time.Hour)
+
+		request := newPostWinRequest("Chris")
+		request.AddCookie(cookie)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusUnauthorized)
+	})
+
+	t.Run("the league is public", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newLeagueRequest())
+
+		assertStatus(t, response, http.StatusOK)
+	})
+
+	t.Run("logging out ends the session", func(t *testing.T) {
+		cookie := logIn(t, server, "Chris", "correct horse")
+
+		request := httptest.NewRequest(http.MethodPost, "/logout", nil)
+		request.AddCookie(cookie)
+		server.ServeHTTP(httptest.NewRecorder(), request)
+
+		request = newPostWinRequest("Chris")
+		request.AddCookie(cookie)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusUnauthorized)
+	})
+}
+
+func newFormRequest(path, name, password string) *http.Request {
+	form := url.Values{"name": {name}, "password": {password}}
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	request.Header.Set("content-type", "application/x-www-form-urlencoded")
+	return request
+}
+
+func logIn(t testing.TB, server http.Handler, name, password string) *http.Cookie {
+	t.Helper()
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, newFormRequest("/login", name, password))
+	return sessionCookie(t, response)
+}
+
+func sessionCookie(t testing.TB, response *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, cookie := range response.Result().Cookies() {
+		if cookie.Name == "poker_session" {
+			if !cookie.HttpOnly {
+				t.Error("session cookie should not be readable from JavaScript")
+			}
+			return cookie
+		}
+	}
+	t.Fatalf("no session cookie in %v", response.Result().Header)
+	return nil
+}
+
+func assertGamePlayer(t testing.TB, server http.Handler, cookie *http.Cookie, want string) {
+	t.Helper()
+
+	request := httptest.NewRequest(http.MethodGet, "/game", nil)
+	request.AddCookie(cookie)
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+
+	assertStatus(t, response, http.StatusOK)
+	if !strings.Contains(response.Body.String(), "Playing as "+want) {
+		t.Errorf("expected the game page to say it is playing as %s", want)
+	}
+}
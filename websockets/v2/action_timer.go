@@ -0,0 +1,153 @@
+package poker
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock tells the time and runs functions once some has passed. Tests use one they move on
+// themselves rather than waiting.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// realClock is the time package's clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// ActionTimer limits how long players seated at a table have to act when it is their turn.
+type ActionTimer struct {
+	// Limit is how long a player has to act each turn.
+	Limit time.Duration
+	// TimeBank is extra time each player has for the whole game, drawn on whenever they take
+	// longer than Limit.
+	TimeBank time.Duration
+	// HurryUp is how long before their time runs out a player is warned.
+	HurryUp time.Duration
+	// Tournament eliminates players who run out of time. Otherwise the action just passes on.
+	Tournament bool
+	// Clock is the real time's if nil.
+	Clock Clock
+}
+
+// The alerts sent to everyone at a table about the player whose turn it is.
+const (
+	hurryUpFormat             = "%s, hurry up! %v left to act\n"
+	outOfTimeFormat           = "%s ran out of time\n"
+	outOfTimeEliminatedFormat = "%s ran out of time and is out\n"
+)
+
+// WithActionTimer times the turns of players seated at a table in a game played over a websocket.
+func WithActionTimer(timer ActionTimer) PlayerServerOption {
+	return func(p *PlayerServer) {
+		if timer.Clock == nil {
+			timer.Clock = realClock{}
+		}
+		p.actionTimer = &timer
+	}
+}
+
+// turnTimer times the turns of the players at one table.
+type turnTimer struct {
+	ActionTimer
+
+	// banks is how much of their TimeBank each player has left, once they've used some
+	banks   map[string]time.Duration
+	player  string
+	started time.Time
+	stops   []func() bool
+}
+
+func newTurnTimer(timer ActionTimer) *turnTimer {
+	return &turnTimer{ActionTimer: timer, banks: map[string]time.Duration{}}
+}
+
+func (t *turnTimer) bank(player string) time.Duration {
+	if left, ok := t.banks[player]; ok {
+		return left
+	}
+	return t.TimeBank
+}
+
+// start times player's turn, warning them with hurryUp and calling expire if they run out of
+// time. The functions are called without any locks held.
+func (t *turnTimer) start(player string, hurryUp func(), expire func()) {
+	t.stop()
+	t.player, t.started = player, t.Clock.Now()
+
+	left := t.Limit + t.bank(player)
+	if warnAt := left - t.HurryUp; t.HurryUp > 0 && warnAt > 0 {
+		t.stops = append(t.stops, t.Clock.AfterFunc(warnAt, hurryUp))
+	}
+	t.stops = append(t.stops, t.Clock.AfterFunc(left, expire))
+}
+
+// finish stops timing the current turn, taking any time over Limit from the player's bank.
+func (t *turnTimer) finish() {
+	t.stop()
+	if t.player == "" {
+		return
+	}
+
+	if over := t.Clock.Now().Sub(t.started) - t.Limit; over > 0 {
+		t.banks[t.player] = max(t.bank(t.player)-over, 0)
+	}
+	t.player = ""
+}
+
+func (t *turnTimer) stop() {
+	for _, stop := range t.stops {
+		stop()
+	}
+	t.stops = nil
+}
+
+// timeTurn starts the clock on whoever's turn it is now. It must be called with the lock held.
+func (s *gameSession) timeTurn() {
+	if s.timer == nil || s.table == nil {
+		return
+	}
+
+	s.timer.finish()
+	if s.table.players() < minPlayers {
+		return
+	}
+
+	player, turn := s.table.ToAct().Player, s.table.turns
+	// the timers may fire just as the action moves on or the clock is stopped, so check it is
+	// still the same turn and still being timed
+	stillTheirTurn := func(do func()) func() {
+		return func() {
+			s.lock.Lock()
+			defer s.lock.Unlock()
+			if s.table != nil && s.table.turns == turn && s.timer.player == player {
+				do()
+			}
+		}
+	}
+
+	s.timer.start(player,
+		stillTheirTurn(func() { s.writeAlert([]byte(fmt.Sprintf(hurryUpFormat, player, s.timer.HurryUp))) }),
+		stillTheirTurn(func() { s.outOfTime(player) }),
+	)
+}
+
+// outOfTime passes the action on from player, or eliminates them in a tournament. It must be
+// called with the lock held.
+func (s *gameSession) outOfTime(player string) {
+	if s.timer.Tournament {
+		s.table.Eliminate(player)
+		s.writeAlert([]byte(fmt.Sprintf(outOfTimeEliminatedFormat, player)))
+		s.broadcastSeating()
+	} else {
+		s.table.Act(player)
+		s.writeAlert([]byte(fmt.Sprintf(outOfTimeFormat, player)))
+	}
+	s.timeTurn()
+}
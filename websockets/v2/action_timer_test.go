@@ -0,0 +1,137 @@
+package poker_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/quii/learn-go-with-tests/concurrency/leaktest"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestActionTimer(t *testing.T) {
+	leaktest.Check(t)
+
+	timer := poker.ActionTimer{Limit: 30 * time.Second, TimeBank: 20 * time.Second, HurryUp: 10 * time.Second}
+
+	// startTimedGame seats Chris, Cleo and Ruth, so Cleo, after the dealer, acts first.
+	startTimedGame := func(t *testing.T, timer poker.ActionTimer) (clock *fakeClock, ws *websocket.Conn, gameURL string) {
+		t.Helper()
+		clock = &fakeClock{now: time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)}
+		timer.Clock = clock
+
+		server, err := poker.NewPlayerServer(dummyPlayerStore, &poker.GameSpy{}, poker.WithActionTimer(timer))
+		if err != nil {
+			t.Fatal("problem creating player server", err)
+		}
+		httpServer := httptest.NewServer(server)
+		t.Cleanup(httpServer.Close)
+
+		gameURL = "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws/timed"
+		ws = mustDialWS(t, gameURL)
+		t.Cleanup(func() { ws.Close() })
+		assertPresence(t, ws, poker.Presence{Event: poker.PresenceJoin, Role: poker.RolePlayer})
+
+		writeWSMessage(t, ws, "Chris, Cleo, Ruth")
+		readServerMessage(t, ws) // the GameSpy's empty blind alert
+		if got := readServerMessage(t, ws); got.Seating == nil {
+			t.Fatalf("got %+v, want the seating", got)
+		}
+		return clock, ws, gameURL
+	}
+
+	t.Run("warns the player to act when their time is nearly up", func(t *testing.T) {
+		clock, ws, _ := startTimedGame(t, timer)
+
+		clock.advance(40 * time.Second)
+
+		assertAlertSent(t, ws, "Cleo, hurry up! 10s left to act\n")
+	})
+
+	t.Run("passes the action on when they run out of time", func(t *testing.T) {
+		clock, ws, _ := startTimedGame(t, timer)
+
+		clock.advance(50 * time.Second)
+
+		assertAlertSent(t, ws, "Cleo, hurry up! 10s left to act\n")
+		assertAlertSent(t, ws, "Cleo ran out of time\n")
+
+		clock.advance(40 * time.Second)
+		assertAlertSent(t, ws, "Ruth, hurry up! 10s left to act\n")
+	})
+
+	t.Run("eliminates players who run out of time in a tournament", func(t *testing.T) {
+		tournament := timer
+		tournament.Tournament = true
+		clock, ws, _ := startTimedGame(t, tournament)
+
+		clock.advance(50 * time.Second)
+
+		assertAlertSent(t, ws, "Cleo, hurry up! 10s left to act\n")
+		assertAlertSent(t, ws, "Cleo ran out of time and is out\n")
+		assertSeatingSent(t, ws, poker.Seating{
+			Seats:  []poker.Seat{{Number: 1, Player: "Chris"}, {Number: 3, Player: "Ruth"}},
+			Dealer: 1,
+		})
+	})
+
+	t.Run("acting in time stops the clock, taking anything over the limit from the time bank", func(t *testing.T) {
+		clock, ws, _ := startTimedGame(t, timer)
+
+		clock.advance(45 * time.Second)
+		assertAlertSent(t, ws, "Cleo, hurry up! 10s left to act\n")
+		actAndWait(t, ws, "Cleo")
+		actAndWait(t, ws, "Ruth")
+		actAndWait(t, ws, "Chris")
+
+		// Cleo has 5s of their bank left, so 35s in all and a warning after 25s
+		clock.advance(25 * time.Second)
+		assertAlertSent(t, ws, "Cleo, hurry up! 10s left to act\n")
+	})
+
+	t.Run("stops the clock while nobody is connected to the game", func(t *testing.T) {
+		clock, ws, gameURL := startTimedGame(t, timer)
+
+		ws.Close()
+		if !retryUntil(time.Second, func() bool { return !clockHasTimers(clock) }) {
+			t.Fatal("expected the turn to stop being timed once everyone had gone")
+		}
+		clock.advance(5 * time.Minute)
+
+		ws = mustDialWS(t, gameURL)
+		defer ws.Close()
+		assertNextMessageIsGameState(t, ws)
+		readServerMessage(t, ws) // the seating
+		assertPresence(t, ws, poker.Presence{Event: poker.PresenceJoin, Role: poker.RolePlayer})
+
+		clock.advance(40 * time.Second)
+		assertAlertSent(t, ws, "Cleo, hurry up! 10s left to act\n")
+	})
+
+	t.Run("players can't act out of turn", func(t *testing.T) {
+		_, ws, _ := startTimedGame(t, timer)
+
+		writeWSMessage(t, ws, "Ruth acts")
+
+		assertAlertSent(t, ws, "invalid player: it is not their turn")
+	})
+}
+
+// actAndWait says player has acted, and waits for the seating that follows so the server has
+// dealt with it before the test moves the clock on.
+func actAndWait(t testing.TB, ws *websocket.Conn, player string) {
+	t.Helper()
+	writeWSMessage(t, ws, player+" acts")
+	if got := readServerMessage(t, ws); got.Seating == nil {
+		t.Fatalf("got %+v, want the seating after %s acted", got, player)
+	}
+}
+
+func assertAlertSent(t testing.TB, ws *websocket.Conn, want string) {
+	t.Helper()
+	if got := readServerMessage(t, ws); got.Alert != want {
+		t.Errorf("got %+v, want the alert %q", got, want)
+	}
+}
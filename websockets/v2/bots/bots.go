@@ -0,0 +1,61 @@
+// Package bots plays poker games on a server with nobody at the keyboard. A Bot makes the
+// decisions a player would, and Play carries them out over a client.Client, which is handy for
+// simulations and for acceptance tests that want to run whole games.
+package bots
+
+import (
+	"context"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/client"
+)
+
+// Bot decides what a player does during a game.
+type Bot interface {
+	// OnGameStart is called once the game has been started for players.
+	OnGameStart(players []string)
+	// OnBlind is called with every blind alert the server sends.
+	OnBlind(alert string)
+	// DecideWinner is asked whether the game is over, and who won, once the game has started
+	// and after every blind.
+	DecideWinner() (winner string, over bool)
+}
+
+// Play starts a game for players on c and lets bot play it, returning the winner it declared.
+// It gives up when ctx is done, leaving the game unfinished.
+func Play(ctx context.Context, c *client.Client, players []string, bot Bot) (string, error) {
+	// reading from the server doesn't take a context, so disconnect to stop waiting for it
+	stop := context.AfterFunc(ctx, func() { c.Close() })
+	defer stop()
+
+	winner, err := play(c, players, bot)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return winner, err
+}
+
+func play(c *client.Client, players []string, bot Bot) (string, error) {
+	// the server starts a game with nobody in it for players it can't seat, so check them first
+	if _, err := poker.NewTable(players); err != nil {
+		return "", err
+	}
+	if err := c.SeatPlayers(players); err != nil {
+		return "", err
+	}
+	bot.OnGameStart(players)
+
+	for {
+		if winner, over := bot.DecideWinner(); over {
+			return winner, c.DeclareWinner(winner)
+		}
+
+		msg, err := c.Receive()
+		if err != nil {
+			return "", err
+		}
+		if msg.Alert != "" {
+			bot.OnBlind(msg.Alert)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package bots_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/concurrency/leaktest"
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/bots"
+	"github.com/quii/learn-go-with-tests/websockets/v2/client"
+)
+
+var players = []string{"Chris", "Ruth", "Cleo"}
+
+func TestPlay(t *testing.T) {
+	t.Run("a scripted bot plays a whole game", func(t *testing.T) {
+		leaktest.Check(t)
+		store, url := startServer(t)
+		bot := &bots.Scripted{Winner: "Ruth", AfterBlinds: 3}
+
+		got, err := bots.Play(context.Background(), dial(t, url), players, bot)
+
+		assertNoError(t, err)
+		if got != "Ruth" {
+			t.Errorf("got winner %q want %q", got, "Ruth")
+		}
+		if !reflect.DeepEqual(bot.Players, players) {
+			t.Errorf("bot was told the players were %v, want %v", bot.Players, players)
+		}
+		wantAlerts := []string{"Blind is now 100\n", "Blind is now 200\n", "Blind is now 300\n"}
+		if !reflect.DeepEqual(bot.Alerts, wantAlerts) {
+			t.Errorf("got alerts %q want %q", bot.Alerts, wantAlerts)
+		}
+		assertWinRecorded(t, store, "Ruth")
+	})
+
+	t.Run("over protobuf too", func(t *testing.T) {
+		store, url := startServer(t)
+		bot := &bots.Scripted{Winner: "Cleo", AfterBlinds: 1}
+
+		_, err := bots.Play(context.Background(), dial(t, url, client.WithProtobuf()), players, bot)
+
+		assertNoError(t, err)
+		if len(bot.Alerts) != 1 || bot.Alerts[0] != "Blind is now 100" {
+			t.Errorf("got alerts %q, want the first blind", bot.Alerts)
+		}
+		assertWinRecorded(t, store, "Cleo")
+	})
+
+	t.Run("a random bot picks one of the players", func(t *testing.T) {
+		store, url := startServer(t)
+
+		got, err := bots.Play(context.Background(), dial(t, url), players, bots.NewRandom(rand.New(rand.NewPCG(1, 2)), 5))
+
+		assertNoError(t, err)
+		if !slices.Contains(players, got) {
+			t.Fatalf("got winner %q, who wasn't playing", got)
+		}
+		assertWinRecorded(t, store, got)
+	})
+
+	t.Run("won't start a game the players can't be seated for", func(t *testing.T) {
+		_, url := startServer(t)
+
+		_, err := bots.Play(context.Background(), dial(t, url), []string{"Ruth"}, &bots.Scripted{Winner: "Ruth"})
+
+		if !apperrors.IsInvalid(err) {
+			t.Errorf("got error %v, want an invalid error", err)
+		}
+	})
+
+	t.Run("gives up when the context is done", func(t *testing.T) {
+		leaktest.Check(t)
+		_, url := startServer(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		bot := &bots.Scripted{Winner: "Ruth", AfterBlinds: 1000}
+		_, err := bots.Play(ctx, dial(t, url), players, bot)
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+func TestRandom(t *testing.T) {
+	t.Run("makes the same choices from the same seed", func(t *testing.T) {
+		first := playOffline(bots.NewRandom(rand.New(rand.NewPCG(7, 7)), 10))
+		second := playOffline(bots.NewRandom(rand.New(rand.NewPCG(7, 7)), 10))
+
+		if first != second {
+			t.Errorf("got %q then %q from the same seed", first, second)
+		}
+	})
+
+	t.Run("waits for at least one blind", func(t *testing.T) {
+		bot := bots.NewRandom(rand.New(rand.NewPCG(1, 1)), 3)
+		bot.OnGameStart(players)
+
+		if _, over := bot.DecideWinner(); over {
+			t.Error("did not expect the game to be over before any blinds")
+		}
+	})
+}
+
+// playOffline runs a game with bot without a server, describing who won after how many blinds.
+func playOffline(bot bots.Bot) string {
+	bot.OnGameStart(players)
+	for blinds := 1; ; blinds++ {
+		bot.OnBlind(fmt.Sprintf("Blind is now %d", blinds*100))
+		if winner, over := bot.DecideWinner(); over {
+			return fmt.Sprintf("%s after %d blinds", winner, blinds)
+		}
+	}
+}
+
+// startServer starts a poker server whose games send all their blind alerts as soon as they start.
+func startServer(t *testing.T) (*poker.StubPlayerStore, string) {
+	t.Helper()
+	alerter := poker.BlindAlerterFunc(func(_ time.Duration, amount int, to io.Writer) {
+		fmt.Fprintf(to, "Blind is now %d\n", amount)
+	})
+	store := &poker.StubPlayerStore{}
+
+	// game.html is loaded relative to the working directory
+	t.Chdir("..")
+	server, err := poker.NewPlayerServer(store, poker.NewTexasHoldem(alerter, store))
+	if err != nil {
+		t.Fatal("problem creating player server", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return store, "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws/" + strings.ReplaceAll(t.Name(), "/", "-")
+}
+
+func dial(t *testing.T, url string, options ...client.Option) *client.Client {
+	t.Helper()
+	c, err := client.Dial(context.Background(), url, options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func assertWinRecorded(t testing.TB, store *poker.StubPlayerStore, winner string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for store.WinCallsFor(winner) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a win to be recorded for %s", winner)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("did not expect an error but got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package bots
+
+import "math/rand/v2"
+
+// Random is a Bot which lets a random number of blinds go by, up to the most it was made with,
+// then declares one of the players the winner at random.
+type Random struct {
+	rand      *rand.Rand
+	maxBlinds int
+
+	players []string
+	blinds  int
+	waitFor int
+}
+
+// NewRandom creates a Random bot which waits for between 1 and maxBlinds blinds. Its choices come
+// from r, so a simulation can be repeated by seeding r the same way.
+func NewRandom(r *rand.Rand, maxBlinds int) *Random {
+	return &Random{rand: r, maxBlinds: max(maxBlinds, 1)}
+}
+
+// OnGameStart picks how many blinds to wait for.
+func (b *Random) OnGameStart(players []string) {
+	b.players = players
+	b.blinds = 0
+	b.waitFor = 1 + b.rand.IntN(b.maxBlinds)
+}
+
+// OnBlind counts the blinds.
+func (b *Random) OnBlind(string) {
+	b.blinds++
+}
+
+// DecideWinner picks one of the players once enough blinds have gone by.
+func (b *Random) DecideWinner() (string, bool) {
+	if b.blinds < b.waitFor {
+		return "", false
+	}
+	return b.players[b.rand.IntN(len(b.players))], true
+}
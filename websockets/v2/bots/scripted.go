@@ -0,0 +1,27 @@
+package bots
+
+// Scripted is a Bot which declares Winner once it has been sent AfterBlinds blind alerts, so
+// tests know how a game will go.
+type Scripted struct {
+	Winner      string
+	AfterBlinds int
+
+	// Players and Alerts are who the game was started for and the alerts sent during it.
+	Players []string
+	Alerts  []string
+}
+
+// OnGameStart remembers who is playing.
+func (s *Scripted) OnGameStart(players []string) {
+	s.Players = players
+}
+
+// OnBlind remembers alert.
+func (s *Scripted) OnBlind(alert string) {
+	s.Alerts = append(s.Alerts, alert)
+}
+
+// DecideWinner declares Winner after AfterBlinds alerts.
+func (s *Scripted) DecideWinner() (string, bool) {
+	return s.Winner, len(s.Alerts) >= s.AfterBlinds
+}
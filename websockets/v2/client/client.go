@@ -0,0 +1,95 @@
+// Package client plays a game on a poker server over its websocket, as game.html does in a
+// browser, for programs which want to take part in games rather than people.
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+// Client is a player's connection to one game, such as ws://localhost:5000/ws/table-1.
+type Client struct {
+	conn   *websocket.Conn
+	decode func([]byte) (poker.ServerMessage, error)
+}
+
+// Option changes how a Client connects.
+type Option func(*websocket.Dialer)
+
+// WithJar sends the cookies in jar when connecting. Servers with accounts need the session cookie
+// of a player who has logged in.
+func WithJar(jar http.CookieJar) Option {
+	return func(d *websocket.Dialer) {
+		d.Jar = jar
+	}
+}
+
+// WithProtobuf asks the server to send messages with poker.ProtobufEncoding.
+func WithProtobuf() Option {
+	return func(d *websocket.Dialer) {
+		d.Subprotocols = []string{poker.ProtobufSubprotocol}
+	}
+}
+
+// Dial connects to the game at url.
+func Dial(ctx context.Context, url string, options ...Option) (*Client, error) {
+	dialer := *websocket.DefaultDialer
+	for _, option := range options {
+		option(&dialer)
+	}
+
+	conn, response, err := dialer.DialContext(ctx, url, nil)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("problem connecting to %s, got %s, %w", url, response.Status, err)
+		}
+		return nil, fmt.Errorf("problem connecting to %s, %w", url, err)
+	}
+
+	c := &Client{conn: conn, decode: poker.TextEncoding{}.Decode}
+	if conn.Subprotocol() == poker.ProtobufSubprotocol {
+		c.decode = poker.ProtobufEncoding{}.Decode
+	}
+	return c, nil
+}
+
+// StartGame starts a game for numberOfPlayers unnamed players.
+func (c *Client) StartGame(numberOfPlayers int) error {
+	return c.Send(strconv.Itoa(numberOfPlayers))
+}
+
+// SeatPlayers starts a game for players, seating them at a table in order.
+func (c *Client) SeatPlayers(players []string) error {
+	return c.Send(strings.Join(players, ","))
+}
+
+// DeclareWinner finishes the game.
+func (c *Client) DeclareWinner(winner string) error {
+	return c.Send(winner)
+}
+
+// Send sends a line to the game as a player would type it, such as poker.NextHandCommand.
+func (c *Client) Send(line string) error {
+	return c.conn.WriteMessage(websocket.TextMessage, []byte(line))
+}
+
+// Receive waits for the next message from the server.
+func (c *Client) Receive() (poker.ServerMessage, error) {
+	_, data, err := c.conn.ReadMessage()
+	if err != nil {
+		return poker.ServerMessage{}, err
+	}
+	return c.decode(data)
+}
+
+// Close disconnects from the game. A game which hasn't finished carries on, so the player can
+// connect to it again.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
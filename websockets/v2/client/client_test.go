@@ -0,0 +1,65 @@
+package client_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/client"
+)
+
+func TestClient(t *testing.T) {
+	t.Chdir("..")
+	server, err := poker.NewPlayerServer(&poker.StubPlayerStore{}, &poker.GameSpy{})
+	if err != nil {
+		t.Fatal("problem creating player server", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	wsURL := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	for _, encoding := range []struct {
+		name    string
+		options []client.Option
+	}{
+		{"text", nil},
+		{"protobuf", []client.Option{client.WithProtobuf()}},
+	} {
+		t.Run("receives the seating of named players over "+encoding.name, func(t *testing.T) {
+			c, err := client.Dial(context.Background(), wsURL+"/ws/"+encoding.name, encoding.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			if err := c.SeatPlayers([]string{"Chris", "Ruth"}); err != nil {
+				t.Fatal(err)
+			}
+
+			want := poker.Seating{Seats: []poker.Seat{{Number: 1, Player: "Chris"}, {Number: 2, Player: "Ruth"}}, Dealer: 1}
+			for {
+				msg, err := c.Receive()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if msg.Seating != nil {
+					if !reflect.DeepEqual(*msg.Seating, want) {
+						t.Errorf("got seating %+v want %+v", *msg.Seating, want)
+					}
+					return
+				}
+			}
+		})
+	}
+
+	t.Run("explains why it couldn't connect", func(t *testing.T) {
+		_, err := client.Dial(context.Background(), wsURL+"/nowhere")
+
+		if err == nil || !strings.Contains(err.Error(), "404") {
+			t.Errorf("got error %v, want it to mention the 404", err)
+		}
+	})
+}
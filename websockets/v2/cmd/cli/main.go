@@ -1,27 +1,72 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
 
 	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/render"
+	"github.com/quii/learn-go-with-tests/websockets/v2/simulate"
+	"github.com/quii/learn-go-with-tests/websockets/v2/stats"
 )
 
 const dbFileName = "game.db.json"
+const winLogFileName = "wins.log"
+
+var simulatePlayers = flag.Int("simulate", 0, "preview the blinds for a game with this many players, without playing it")
 
 func main() {
-	store, close, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
+	flag.Parse()
+
+	if *simulatePlayers > 0 {
+		if _, err := simulate.Run(*simulatePlayers).WriteTo(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	winLog, closeWinLog, err := poker.WinLogFromFile(winLogFileName)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeWinLog()
+
+	if flag.NArg() == 2 && flag.Arg(0) == "stats" {
+		fmt.Println(stats.For(flag.Arg(1), winLog.Winners()))
+		return
+	}
+
+	fileStore, close, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
 
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer close()
 
+	if flag.NArg() == 1 && flag.Arg(0) == "league" {
+		if err := render.LeagueTable(os.Stdout, fileStore.GetLeague()); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	store := poker.NewHistoryPlayerStore(fileStore, winLog)
+
 	game := poker.NewTexasHoldem(poker.BlindAlerterFunc(poker.Alerter), store)
-	cli := poker.NewCLI(os.Stdin, os.Stdout, game)
+	var options []poker.CLIOption
+	if lang, ok := poker.ParseLanguage(os.Getenv("LANG")); ok {
+		options = append(options, poker.WithLanguage(lang))
+	}
+	cli := poker.NewCLI(os.Stdin, os.Stdout, game, options...)
 
 	fmt.Println("Let's play poker")
+	fmt.Println("Name the players, separated by commas, to seat them and keep track of the dealer")
+	fmt.Println("Type next hand to move the dealer button, or {Name} is out when someone is knocked out")
 	fmt.Println("Type {Name} wins to record a win")
+	fmt.Println("Run with stats {Name} to see how a player is doing, or league to see the table")
+	fmt.Println("Run with -simulate {number of players} to preview the blinds for a game")
 	cli.PlayPoker()
 }
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/render"
+)
+
+const usage = `usage: pokeradmin <command> [flags]
+
+commands:
+  export   write the league to stdout
+  league   show the league as a table
+  import   merge a league read from stdin into the league, adding up wins
+  migrate-store
+           copy the league from one store to another, replaying the win history if there is one`
+
+var codecs = map[string]poker.Codec{
+	"csv":  poker.CSVCodec{},
+	"json": poker.JSONCodec{},
+}
+
+// run carries out the command in args, reading and writing the league file the store uses.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	command, args := args[0], args[1:]
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dbFileName := flags.String("db", "game.db.json", "league file to use")
+	format := flags.String("format", "csv", "format to export or import, csv or json")
+	from := flags.String("from", "game.db.json", "store to migrate the league from")
+	to := flags.String("to", "", "store to migrate the league to")
+	fromWins := flags.String("from-wins", "", "win log kept alongside the store migrated from, if there is one")
+	toWins := flags.String("to-wins", "", "win log to keep alongside the store migrated to, if there is one")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	codec, ok := codecs[*format]
+	if !ok {
+		return fmt.Errorf("unknown format %q, use csv or json", *format)
+	}
+
+	switch command {
+	case "export":
+		return export(*dbFileName, codec, stdout)
+	case "import":
+		return importLeague(*dbFileName, codec, stdin, stdout)
+	case "league":
+		return showLeague(*dbFileName, stdout)
+	case "migrate-store":
+		return migrateStore(*from, *fromWins, *to, *toWins, stdout)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+}
+
+func export(dbFileName string, codec poker.Codec, out io.Writer) error {
+	store, closeStore, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	return codec.Encode(out, store.GetLeague())
+}
+
+func showLeague(dbFileName string, out io.Writer) error {
+	store, closeStore, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	return render.LeagueTable(out, store.GetLeague())
+}
+
+func importLeague(dbFileName string, codec poker.Codec, in io.Reader, out io.Writer) error {
+	imported, err := codec.Decode(in)
+	if err != nil {
+		return fmt.Errorf("problem reading league to import, %w", err)
+	}
+
+	if err := imported.Validate(); err != nil {
+		return fmt.Errorf("problem reading league to import, %w", err)
+	}
+
+	store, closeStore, err := openLeague(dbFileName, os.O_RDWR|os.O_CREATE)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	return importInto(store, imported, out)
+}
+
+// importInto adds the wins in imported to store's league. They are all written when the store is
+// flushed, which re-reads the file with it locked, so wins other processes have saved since the
+// store was opened are kept, and the file stays in the store's format.
+func importInto(store *poker.FileSystemPlayerStore, imported poker.League, out io.Writer) error {
+	for _, player := range imported {
+		store.AddWins(player.Name, player.Wins)
+	}
+
+	if err := store.Flush(); err != nil {
+		return fmt.Errorf("problem writing the imported league, %v", err)
+	}
+
+	fmt.Fprintf(out, "imported %d players, the league now has %d\n", len(imported), len(store.GetLeague()))
+	return nil
+}
+
+func migrateStore(from, fromWins, to, toWins string, out io.Writer) error {
+	if to == "" {
+		return errors.New("migrate-store needs a store to migrate to, use -to")
+	}
+	if from == to {
+		return fmt.Errorf("can't migrate %s to itself", from)
+	}
+
+	source, closeSource, err := openStore(from, fromWins, true)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+
+	destination, closeDestination, err := openStore(to, toWins, false)
+	if err != nil {
+		return err
+	}
+	defer closeDestination()
+
+	migration, err := poker.MigrateStore(source, destination)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "migrated %d players and %d wins", migration.Players, migration.Wins)
+	if migration.History > 0 {
+		fmt.Fprintf(out, ", %d of them in the order they were played", migration.History)
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// openStore opens the league file at location with openLeague, along with the win log at wins if
+// there is one. A store opened to read from must already exist, and is never written to.
+func openStore(location, wins string, readOnly bool) (poker.PlayerStore, func(), error) {
+	if strings.Contains(location, "://") {
+		return nil, nil, fmt.Errorf("can't migrate %s, only league files are supported", location)
+	}
+
+	mode := os.O_RDWR | os.O_CREATE
+	if readOnly {
+		mode = os.O_RDONLY
+	}
+
+	league, closeLeague, err := openLeague(location, mode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if wins == "" {
+		return league, closeLeague, nil
+	}
+
+	log, closeLog, err := openWinLog(wins, readOnly)
+	if err != nil {
+		closeLeague()
+		return nil, nil, err
+	}
+
+	store := historyStore{poker.NewHistoryPlayerStore(league, log), league}
+	return store, func() {
+		closeLog()
+		closeLeague()
+	}, nil
+}
+
+// openWinLog opens the win log at path, creating it unless it is only to be read.
+func openWinLog(path string, readOnly bool) (*poker.WinLog, func(), error) {
+	if !readOnly {
+		return poker.WinLogFromFile(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
+	}
+
+	log, err := poker.NewWinLog(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return log, func() { file.Close() }, nil
+}
+
+// openLeague opens the league file at location, passing mode to os.OpenFile. Wins recorded to it
+// are only written to the file when it is flushed or closed.
+func openLeague(location string, mode int) (*poker.FileSystemPlayerStore, func(), error) {
+	db, err := os.OpenFile(location, mode, 0666)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem opening %s %v", location, err)
+	}
+
+	league, err := poker.NewFileSystemPlayerStore(db, poker.WithBatching(math.MaxInt, nil))
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("problem creating file system player store, %v", err)
+	}
+
+	return league, func() {
+		league.Close()
+		db.Close()
+	}, nil
+}
+
+// historyStore is a HistoryPlayerStore which can still flush the league file it wraps.
+type historyStore struct {
+	*poker.HistoryPlayerStore
+	league *poker.FileSystemPlayerStore
+}
+
+// Flush writes the wins waiting to go to the league file.
+func (h historyStore) Flush() error {
+	return h.league.Flush()
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestAdmin(t *testing.T) {
+	newDB := func(t *testing.T, data string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "game.db.json")
+		if err := os.WriteFile(path, []byte(data), 0666); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("exports the league as csv", func(t *testing.T) {
+		db := newDB(t, `[{"Name": "Cleo", "Wins": 10}, {"Name": "Chris", "Wins": 33}]`)
+		out := &bytes.Buffer{}
+
+		if err := run([]string{"export", "-db", db}, nil, out); err != nil {
+			t.Fatal(err)
+		}
+
+		want := "name,wins\nChris,33\nCleo,10\n"
+		if out.String() != want {
+			t.Errorf("got %q want %q", out.String(), want)
+		}
+	})
+
+	t.Run("shows the league as a table", func(t *testing.T) {
+		db := newDB(t, `[{"Name": "Cleo", "Wins": 10}, {"Name": "Chris", "Wins": 33}]`)
+		out := &bytes.Buffer{}
+
+		if err := run([]string{"league", "-db", db}, nil, out); err != nil {
+			t.Fatal(err)
+		}
+
+		want := "RANK  NAME   WINS\n1     Chris  33\n2     Cleo   10\n"
+		if out.String() != want {
+			t.Errorf("got %q want %q", out.String(), want)
+		}
+	})
+
+	t.Run("imports a csv league, adding up wins", func(t *testing.T) {
+		db := newDB(t, `[{"Name": "Cleo", "Wins": 10}, {"Name": "Chris", "Wins": 33}]`)
+		in := strings.NewReader("name,wins\nCleo,5\nPepper,2\n")
+
+		if err := run([]string{"import", "-db", db}, in, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+
+		store, closeStore, err := poker.FileSystemPlayerStoreFromFile(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer closeStore()
+
+		for name, want := range map[string]int{"Cleo": 15, "Chris": 33, "Pepper": 2} {
+			if got := store.GetPlayerScore(name); got != want {
+				t.Errorf("got %d wins for %s, want %d", got, name, want)
+			}
+		}
+	})
+
+	t.Run("keeps wins saved by another process while importing", func(t *testing.T) {
+		db := newDB(t, `[{"Name": "Cleo", "Wins": 10}]`)
+
+		store, closeStore, err := openLeague(db, os.O_RDWR)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer closeStore()
+
+		webserver, closeWebserver, err := poker.FileSystemPlayerStoreFromFile(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		webserver.RecordWin("Chris")
+		closeWebserver()
+
+		err = importInto(store, poker.League{{Name: "Cleo", Wins: 5}, {Name: "Pepper", Wins: 2}}, &bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reopened, closeReopened, err := poker.FileSystemPlayerStoreFromFile(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer closeReopened()
+
+		for name, want := range map[string]int{"Cleo": 15, "Chris": 1, "Pepper": 2} {
+			if got := reopened.GetPlayerScore(name); got != want {
+				t.Errorf("got %d wins for %s, want %d", got, name, want)
+			}
+		}
+	})
+
+	t.Run("imports huge numbers of wins and players with none", func(t *testing.T) {
+		db := newDB(t, `[{"Name": "Cleo", "Wins": 10}]`)
+		in := strings.NewReader("name,wins\nChris,1000000000\nPepper,0\n")
+
+		if err := run([]string{"import", "-db", db}, in, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+
+		store, closeStore, err := poker.FileSystemPlayerStoreFromFile(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer closeStore()
+
+		want := poker.League{{Name: "Chris", Wins: 1_000_000_000}, {Name: "Cleo", Wins: 10}, {Name: "Pepper", Wins: 0}}
+		if got := store.GetLeague(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got league %v want %v", got, want)
+		}
+	})
+
+	t.Run("rejects an invalid import without touching the league", func(t *testing.T) {
+		original := `[{"Name": "Cleo", "Wins": 10}]`
+		db := newDB(t, original)
+		in := strings.NewReader("name,wins\nCleo,lots\n")
+
+		if err := run([]string{"import", "-db", db}, in, &bytes.Buffer{}); err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+
+		got, _ := os.ReadFile(db)
+		if string(got) != original {
+			t.Errorf("league file changed to %q", got)
+		}
+	})
+
+	t.Run("migrates the league and win history to another store", func(t *testing.T) {
+		from := newDB(t, `[{"Name": "Cleo", "Wins": 2}, {"Name": "Chris", "Wins": 1}]`)
+		fromWins := filepath.Join(t.TempDir(), "wins.log")
+		if err := os.WriteFile(fromWins, []byte("Cleo\nChris\n"), 0666); err != nil {
+			t.Fatal(err)
+		}
+		to := filepath.Join(t.TempDir(), "migrated.db.json")
+		toWins := filepath.Join(t.TempDir(), "migrated-wins.log")
+		out := &bytes.Buffer{}
+
+		err := run([]string{"migrate-store", "-from", from, "-from-wins", fromWins, "-to", to, "-to-wins", toWins}, nil, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "migrated 2 players and 3 wins, 2 of them in the order they were played\n"
+		if out.String() != want {
+			t.Errorf("got %q want %q", out.String(), want)
+		}
+
+		store, closeStore, err := poker.FileSystemPlayerStoreFromFile(to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer closeStore()
+
+		for name, want := range map[string]int{"Cleo": 2, "Chris": 1} {
+			if got := store.GetPlayerScore(name); got != want {
+				t.Errorf("got %d wins for %s, want %d", got, name, want)
+			}
+		}
+
+		if got, _ := os.ReadFile(toWins); string(got) != "Cleo\nChris\nCleo\n" {
+			t.Errorf("got win log %q", got)
+		}
+	})
+
+	t.Run("won't migrate from a store which doesn't exist", func(t *testing.T) {
+		from := filepath.Join(t.TempDir(), "mistyped.db.json")
+		to := filepath.Join(t.TempDir(), "migrated.db.json")
+
+		err := run([]string{"migrate-store", "-from", from, "-to", to}, nil, &bytes.Buffer{})
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+		if _, err := os.Stat(from); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be created, %v", from, err)
+		}
+	})
+
+	t.Run("copies players without any wins to a league file", func(t *testing.T) {
+		from := newDB(t, `[{"Name": "Cleo", "Wins": 2}, {"Name": "Pepper", "Wins": 0}]`)
+		to := filepath.Join(t.TempDir(), "migrated.db.json")
+
+		if err := run([]string{"migrate-store", "-from", from, "-to", to}, nil, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+
+		store, closeStore, err := poker.FileSystemPlayerStoreFromFile(to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer closeStore()
+
+		want := poker.League{{Name: "Cleo", Wins: 2}, {Name: "Pepper", Wins: 0}}
+		if got := store.GetLeague(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got league %v want %v", got, want)
+		}
+	})
+
+	t.Run("only migrates between league files", func(t *testing.T) {
+		from := newDB(t, `[{"Name": "Cleo", "Wins": 2}]`)
+
+		err := run([]string{"migrate-store", "-from", from, "-to", "postgres://localhost/poker"}, nil, &bytes.Buffer{})
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		err := run([]string{"export", "-format", "xml"}, nil, &bytes.Buffer{})
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+	})
+}
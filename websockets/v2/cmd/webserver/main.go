@@ -1,34 +1,136 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"github.com/quii/learn-go-with-tests/acceptance-tests/gracefulshutdown"
+	"github.com/quii/learn-go-with-tests/http-server/idempotency"
 	"github.com/quii/learn-go-with-tests/websockets/v2"
 	"log"
 	"net/http"
 	"os"
+	"time"
 )
 
-const dbFileName = "game.db.json"
+const shutdownTimeout = 10 * time.Second
+
+var (
+	port       = flag.String("port", "5000", "port to listen on")
+	dbFileName = flag.String("db", "game.db.json", "file to store the league in")
+	usersFile  = flag.String("users", "users.db.json", "file to store player accounts in")
+	winsFile   = flag.String("wins", "wins.log", "file to log the winner of every game to")
+	gamesFile  = flag.String("games", "games.log", "file to log when every game started and finished to")
+	language   = flag.String("lang", "en", "language to talk to clients in when they don't ask for one we speak: en, es or fr")
+	keyTTL     = flag.Duration("idempotency-ttl", 24*time.Hour, "how long to replay responses to requests retried with the same Idempotency-Key")
+	turnLimit  = flag.Duration("turn-limit", 0, "how long players seated at a table have to act each turn, or 0 for as long as they like")
+	timeBank   = flag.Duration("time-bank", time.Minute, "extra time each player can draw on over a game once their turn limit runs out")
+	tournament = flag.Bool("tournament", false, "eliminate players who run out of time to act, rather than passing the action on")
+)
 
 func main() {
-	db, err := os.OpenFile(dbFileName, os.O_RDWR|os.O_CREATE, 0666)
+	webhooks := poker.NewWebhooks(poker.WithWebhookErrorHandler(func(url string, err error) {
+		log.Println(err)
+	}))
+	flag.Func("webhook", "URL to POST the winner and league to when a game finishes, can be given more than once", webhooks.Register)
+	flag.Parse()
+
+	db, err := os.OpenFile(*dbFileName, os.O_RDWR|os.O_CREATE, 0666)
 
 	if err != nil {
-		log.Fatalf("problem opening %s %v", dbFileName, err)
+		log.Fatalf("problem opening %s %v", *dbFileName, err)
 	}
 
-	store, err := poker.NewFileSystemPlayerStore(db)
+	fileStore, err := poker.NewFileSystemPlayerStore(db)
 
 	if err != nil {
 		log.Fatalf("problem creating file system player store, %v ", err)
 	}
 
-	game := poker.NewTexasHoldem(poker.BlindAlerterFunc(poker.Alerter), store)
+	winLog, closeWinLog, err := poker.WinLogFromFile(*winsFile)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	store := poker.NewObservablePlayerStore(poker.NewHistoryPlayerStore(fileStore, winLog))
+
+	gameLog, closeGameLog, err := poker.GameLogFromFile(*gamesFile)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	game := poker.NewTexasHoldem(poker.BlindAlerterFunc(poker.Alerter), store, poker.WithGameStore(gameLog), poker.WithWebhooks(webhooks))
+
+	usersDB, err := os.OpenFile(*usersFile, os.O_RDWR|os.O_CREATE, 0600)
+
+	if err != nil {
+		log.Fatalf("problem opening %s %v", *usersFile, err)
+	}
 
-	server, err := poker.NewPlayerServer(store, game)
+	users, err := poker.NewFileSystemUserStore(usersDB)
+
+	if err != nil {
+		log.Fatalf("problem creating file system user store, %v ", err)
+	}
+
+	lang, ok := poker.ParseLanguage(*language)
+
+	if !ok {
+		log.Fatalf("no translations for language %q", *language)
+	}
+
+	options := []poker.PlayerServerOption{
+		poker.WithAccounts(users),
+		poker.WithWinHistory(winLog),
+		poker.WithGameHistory(gameLog),
+		poker.WithDefaultLanguage(lang),
+		poker.WithIdempotencyKeys(idempotency.NewMemoryStore(time.Now), *keyTTL),
+	}
+
+	if *turnLimit > 0 {
+		options = append(options, poker.WithActionTimer(poker.ActionTimer{
+			Limit:      *turnLimit,
+			TimeBank:   *timeBank,
+			HurryUp:    min(10*time.Second, *turnLimit/2),
+			Tournament: *tournament,
+		}))
+	}
+
+	playerServer, err := poker.NewPlayerServer(store, game, options...)
 
 	if err != nil {
 		log.Fatalf("problem creating player server %v", err)
 	}
 
-	log.Fatal(http.ListenAndServe(":5000", server))
+	server := gracefulshutdown.NewServer(
+		&http.Server{Addr: ":" + *port, Handler: playerServer},
+		gracefulshutdown.WithTimeout(shutdownTimeout),
+		gracefulshutdown.WithHook("deliver webhooks", webhooks.Wait),
+		gracefulshutdown.WithHook("close player store", func(context.Context) error {
+			return fileStore.Close()
+		}),
+		gracefulshutdown.WithHook("close "+*dbFileName, func(context.Context) error {
+			return db.Close()
+		}),
+		gracefulshutdown.WithHook("close "+*usersFile, func(context.Context) error {
+			return usersDB.Close()
+		}),
+		gracefulshutdown.WithHook("close "+*winsFile, func(context.Context) error {
+			closeWinLog()
+			return nil
+		}),
+		gracefulshutdown.WithHook("close "+*gamesFile, func(context.Context) error {
+			closeGameLog()
+			return nil
+		}),
+	)
+
+	log.Printf("listening on :%s", *port)
+
+	if err := server.ListenAndServe(context.Background()); err != nil {
+		log.Fatalf("problem shutting down gracefully %v", err)
+	}
+
+	log.Println("shut down gracefully")
 }
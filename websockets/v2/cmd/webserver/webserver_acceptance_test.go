@@ -0,0 +1,129 @@
+package main_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/acceptance-tests/gracetest"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/bots"
+	"github.com/quii/learn-go-with-tests/websockets/v2/client"
+)
+
+func TestWebserverShutsDownGracefully(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs the webserver")
+	}
+
+	binPath := gracetest.BuildBinary(t, ".")
+	port := gracetest.FreePort(t)
+	leagueURL := "http://localhost:" + port + "/v1/league"
+	readyURL := "http://localhost:" + port + "/readyz"
+
+	// game.html is loaded relative to the working directory, so run from the poker package.
+	program := gracetest.Start(t, binPath,
+		gracetest.WithArgs("-port", port,
+			"-db", filepath.Join(t.TempDir(), "game.db.json"),
+			"-users", filepath.Join(t.TempDir(), "users.db.json"),
+			"-wins", filepath.Join(t.TempDir(), "wins.log"),
+			"-games", filepath.Join(t.TempDir(), "games.log"),
+		),
+		gracetest.WithDir("../.."),
+	)
+
+	if err := program.WaitUntilReady(5*time.Second, gracetest.ReadinessProbe(readyURL)); err != nil {
+		t.Fatal(err)
+	}
+
+	gracetest.CanGet(t, leagueURL)
+
+	if err := program.Interrupt(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := program.WaitForExit(5 * time.Second); err != nil {
+		t.Errorf("expected a clean exit but got %v", err)
+	}
+
+	gracetest.CantGet(t, leagueURL)
+}
+
+func TestBotsPlayAGameOnTheWebserver(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs the webserver")
+	}
+
+	binPath := gracetest.BuildBinary(t, ".")
+	port := gracetest.FreePort(t)
+	baseURL := "http://localhost:" + port
+
+	program := gracetest.Start(t, binPath,
+		gracetest.WithArgs("-port", port,
+			"-db", filepath.Join(t.TempDir(), "game.db.json"),
+			"-users", filepath.Join(t.TempDir(), "users.db.json"),
+			"-wins", filepath.Join(t.TempDir(), "wins.log"),
+			"-games", filepath.Join(t.TempDir(), "games.log"),
+		),
+		gracetest.WithDir("../.."),
+	)
+	defer program.Interrupt()
+
+	if err := program.WaitUntilReady(5*time.Second, gracetest.ReadinessProbe(baseURL+"/readyz")); err != nil {
+		t.Fatal(err)
+	}
+
+	jar, _ := cookiejar.New(nil)
+	httpClient := &http.Client{Jar: jar}
+	response, err := httpClient.PostForm(baseURL+"/register", url.Values{"name": {"Ruth"}, "password": {"correct horse battery staple"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	c, err := client.Dial(context.Background(), "ws://localhost:"+port+"/ws/acceptance", client.WithJar(jar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	bot := &bots.Scripted{Winner: "Ruth", AfterBlinds: 1}
+	if _, err := bots.Play(ctx, c, []string{"Chris", "Ruth", "Cleo"}, bot); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []poker.Player{{Name: "Ruth", Wins: 1}}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		league := getLeague(t, baseURL+"/v1/league")
+		if reflect.DeepEqual(league, want) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got league %v want %v", league, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func getLeague(t testing.TB, url string) []poker.Player {
+	t.Helper()
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	league, err := poker.NewLeague(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return league
+}
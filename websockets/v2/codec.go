@@ -0,0 +1,47 @@
+package poker
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Codec converts a League to and from the format it is stored in.
+type Codec interface {
+	Encode(w io.Writer, league League) error
+	Decode(r io.Reader) (League, error)
+}
+
+// JSONCodec stores a League as JSON, which is easy for humans to read and edit.
+type JSONCodec struct{}
+
+// Encode writes league as JSON.
+func (JSONCodec) Encode(w io.Writer, league League) error {
+	return json.NewEncoder(w).Encode(league)
+}
+
+// Decode reads a League from JSON.
+func (JSONCodec) Decode(r io.Reader) (League, error) {
+	return NewLeague(r)
+}
+
+// GobCodec stores a League using encoding/gob, which is smaller and faster but only readable by Go programs.
+type GobCodec struct{}
+
+// Encode writes league as a gob.
+func (GobCodec) Encode(w io.Writer, league League) error {
+	return gob.NewEncoder(w).Encode(league)
+}
+
+// Decode reads a League from a gob.
+func (GobCodec) Decode(r io.Reader) (League, error) {
+	var league League
+	err := gob.NewDecoder(r).Decode(&league)
+
+	if err != nil {
+		err = fmt.Errorf("problem parsing League, %v", err)
+	}
+
+	return league, err
+}
@@ -0,0 +1,126 @@
+package poker_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+var codecs = []struct {
+	name  string
+	codec poker.Codec
+}{
+	{"json", poker.JSONCodec{}},
+	{"gob", poker.GobCodec{}},
+	{"csv", poker.CSVCodec{}},
+}
+
+func TestCodecs(t *testing.T) {
+	for _, c := range codecs {
+		t.Run(c.name+" round trips a league", func(t *testing.T) {
+			want := poker.League{{Name: "Chris", Wins: 33}, {Name: "Cleo", Wins: 10}}
+
+			buf := bytes.Buffer{}
+			assertNoError(t, c.codec.Encode(&buf, want))
+
+			got, err := c.codec.Decode(&buf)
+			assertNoError(t, err)
+
+			assertLeague(t, got, want)
+		})
+
+		t.Run(c.name+" can back a FileSystemPlayerStore", func(t *testing.T) {
+			database, cleanDatabase := createTempFile(t, "")
+			defer cleanDatabase()
+
+			store, err := poker.NewFileSystemPlayerStore(database, poker.WithCodec(c.codec))
+			assertNoError(t, err)
+			store.RecordWin("Chris")
+
+			reopened, err := poker.NewFileSystemPlayerStore(database, poker.WithCodec(c.codec))
+			assertNoError(t, err)
+
+			assertScoreEquals(t, reopened.GetPlayerScore("Chris"), 1)
+		})
+	}
+}
+
+func TestCSVCodec(t *testing.T) {
+	t.Run("merges rows for the same player", func(t *testing.T) {
+		got, err := poker.CSVCodec{}.Decode(strings.NewReader("name,wins\nChris,3\nCleo,10\n Chris ,4\n"))
+		assertNoError(t, err)
+
+		assertLeague(t, got, poker.League{{Name: "Chris", Wins: 7}, {Name: "Cleo", Wins: 10}})
+	})
+
+	t.Run("quotes names containing commas", func(t *testing.T) {
+		buf := bytes.Buffer{}
+		assertNoError(t, poker.CSVCodec{}.Encode(&buf, poker.League{{Name: "Smith, Jo", Wins: 2}}))
+
+		if got, want := buf.String(), "name,wins\n\"Smith, Jo\",2\n"; got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+	})
+
+	invalid := []struct {
+		name, csv, wantErr string
+	}{
+		{"empty input", "", "header"},
+		{"wrong header", "player,score\nChris,3\n", `header must be "name,wins"`},
+		{"missing column", "name,wins\nChris\n", "wrong number of fields"},
+		{"extra column", "name,wins,losses\nChris,3,1\n", "wrong number of fields"},
+		{"wins not a number", "name,wins\nChris,3\nCleo,lots\n", `line 3: wins must be a whole number, got "lots"`},
+	}
+
+	for _, c := range invalid {
+		t.Run("rejects "+c.name, func(t *testing.T) {
+			_, err := poker.CSVCodec{}.Decode(strings.NewReader(c.csv))
+
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("got error %v want it to contain %q", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestMergeLeagues(t *testing.T) {
+	existing := poker.League{{Name: "Chris", Wins: 3}, {Name: "Cleo", Wins: 10}}
+	imported := poker.League{{Name: "Cleo", Wins: 2}, {Name: "Ruth", Wins: 1}}
+
+	got := poker.MergeLeagues(existing, imported)
+
+	assertLeague(t, got, poker.League{{Name: "Chris", Wins: 3}, {Name: "Cleo", Wins: 12}, {Name: "Ruth", Wins: 1}})
+	assertLeague(t, existing, poker.League{{Name: "Chris", Wins: 3}, {Name: "Cleo", Wins: 10}})
+}
+
+func BenchmarkCodecs(b *testing.B) {
+	league := make(poker.League, 1000)
+	for i := range league {
+		league[i] = poker.Player{Name: fmt.Sprintf("Player %d", i), Wins: i}
+	}
+
+	for _, c := range codecs {
+		b.Run(c.name+"/encode", func(b *testing.B) {
+			buf := bytes.Buffer{}
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				c.codec.Encode(&buf, league)
+			}
+			b.ReportMetric(float64(buf.Len()), "file-bytes")
+		})
+
+		b.Run(c.name+"/decode", func(b *testing.B) {
+			encoded := bytes.Buffer{}
+			c.codec.Encode(&encoded, league)
+			data := encoded.Bytes()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.codec.Decode(bytes.NewReader(data))
+			}
+		})
+	}
+}
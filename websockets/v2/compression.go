@@ -0,0 +1,95 @@
+package poker
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Compressors and buffers are reused between responses as making a gzip.Writer allocates a lot.
+var (
+	gzipWriters = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	gzipBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+)
+
+// gzipped compresses responses from next for clients which accept gzip. The whole response is
+// compressed before it is sent so that Content-Length can be set to its compressed size.
+func gzipped(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := gzipBuffers.Get().(*bytes.Buffer)
+		buf.Reset()
+		defer gzipBuffers.Put(buf)
+
+		gz := gzipWriters.Get().(*gzip.Writer)
+		gz.Reset(buf)
+		defer gzipWriters.Put(gz)
+
+		gw := &gzipResponseWriter{ResponseWriter: w, gz: gz, status: http.StatusOK}
+		next.ServeHTTP(gw, r)
+
+		if err := gz.Close(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.WriteHeader(gw.status)
+		w.Write(buf.Bytes())
+	})
+}
+
+// gzipResponseWriter holds on to the status and compresses the body until the handler is done.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz     *gzip.Writer
+	status int
+}
+
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	g.status = status
+}
+
+func (g *gzipResponseWriter) Write(p []byte) (int, error) {
+	return g.gz.Write(p)
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, which it doesn't if it
+// is given a quality of zero. An entry for gzip itself wins over one for *, wherever it is.
+func acceptsGzip(acceptEncoding string) bool {
+	wildcard := false
+	for _, coding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(coding, ";")
+
+		switch strings.TrimSpace(name) {
+		case "gzip":
+			return acceptable(params)
+		case "*":
+			wildcard = acceptable(params)
+		}
+	}
+	return wildcard
+}
+
+// acceptable reports whether the parameters of an Accept-Encoding entry give it a quality above
+// zero, which it has if they don't give one at all.
+func acceptable(params string) bool {
+	q, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+	if !found {
+		return true
+	}
+
+	quality, err := strconv.ParseFloat(q, 64)
+	return err == nil && quality > 0
+}
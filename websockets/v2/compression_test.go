@@ -0,0 +1,90 @@
+package poker_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestLeagueCompression(t *testing.T) {
+	var league []poker.Player
+	for i := 0; i < 100; i++ {
+		league = append(league, poker.Player{Name: fmt.Sprintf("Player %d", i), Wins: 100 - i})
+	}
+	store := &poker.StubPlayerStore{League: league}
+	server := mustMakePlayerServer(t, store, dummyGame)
+
+	t.Run("compresses the league for clients accepting gzip", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"gzip", "deflate, gzip;q=0.8", "*", "*;q=0, gzip"} {
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, newCompressedLeagueRequest(acceptEncoding))
+
+			assertStatus(t, response, http.StatusOK)
+			assertHeader(t, response, "Content-Encoding", "gzip")
+			assertHeader(t, response, "Vary", "Accept-Encoding")
+			assertHeader(t, response, "Content-Length", strconv.Itoa(response.Body.Len()))
+			assertContentType(t, response, "application/json")
+
+			gz, err := gzip.NewReader(response.Body)
+			if err != nil {
+				t.Fatalf("could not read gzipped body for Accept-Encoding %q, %v", acceptEncoding, err)
+			}
+			assertLeague(t, getLeagueFromResponse(t, gz), league)
+		}
+	})
+
+	t.Run("does not compress for clients which don't accept gzip", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"", "br", "gzip;q=0", "identity, gzip;q=0.0", "gzip;q=nonsense", "gzip;q=0, *"} {
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, newCompressedLeagueRequest(acceptEncoding))
+
+			assertHeader(t, response, "Content-Encoding", "")
+
+			var got []poker.Player
+			if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+				t.Errorf("expected plain JSON for Accept-Encoding %q, %v", acceptEncoding, err)
+			}
+		}
+	})
+
+	t.Run("HEAD gives the compressed length without a body", func(t *testing.T) {
+		get := httptest.NewRecorder()
+		server.ServeHTTP(get, newCompressedLeagueRequest("gzip"))
+
+		head := newCompressedLeagueRequest("gzip")
+		head.Method = http.MethodHead
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, head)
+
+		assertHeader(t, response, "Content-Length", strconv.Itoa(get.Body.Len()))
+		if response.Body.Len() != 0 {
+			t.Errorf("expected no body but got %d bytes", response.Body.Len())
+		}
+	})
+}
+
+func BenchmarkLeagueCompression(b *testing.B) {
+	var league []poker.Player
+	for i := 0; i < 100; i++ {
+		league = append(league, poker.Player{Name: fmt.Sprintf("Player %d", i), Wins: 100 - i})
+	}
+	server := mustMakePlayerServer(b, &poker.StubPlayerStore{League: league}, dummyGame)
+	request := newCompressedLeagueRequest("gzip")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		server.ServeHTTP(httptest.NewRecorder(), request)
+	}
+}
+
+func newCompressedLeagueRequest(acceptEncoding string) *http.Request {
+	request := newLeagueRequest()
+	request.Header.Set("Accept-Encoding", acceptEncoding)
+	return request
+}
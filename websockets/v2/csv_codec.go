@@ -0,0 +1,87 @@
+package poker
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var csvHeader = []string{"name", "wins"}
+
+// CSVCodec stores a League as CSV with a name,wins header, for use in spreadsheets. Decoding
+// merges rows for the same player by adding up their wins.
+type CSVCodec struct{}
+
+// Encode writes league as CSV.
+func (CSVCodec) Encode(w io.Writer, league League) error {
+	out := csv.NewWriter(w)
+	out.Write(csvHeader)
+
+	for _, p := range league {
+		out.Write([]string{p.Name, strconv.Itoa(p.Wins)})
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// Decode reads a League from CSV, checking the header is name,wins.
+func (CSVCodec) Decode(r io.Reader) (League, error) {
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = len(csvHeader)
+
+	header, err := in.Read()
+	if err != nil {
+		return nil, fmt.Errorf("problem reading CSV header, %v", err)
+	}
+
+	for i, want := range csvHeader {
+		if strings.TrimSpace(strings.ToLower(header[i])) != want {
+			return nil, fmt.Errorf("CSV header must be %q, got %q", strings.Join(csvHeader, ","), strings.Join(header, ","))
+		}
+	}
+
+	league := League{}
+
+	for {
+		record, err := in.Read()
+		if errors.Is(err, io.EOF) {
+			return league, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing League, %v", err)
+		}
+
+		line, _ := in.FieldPos(0)
+		wins, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: wins must be a whole number, got %q", line, record[1])
+		}
+
+		name := strings.TrimSpace(record[0])
+		if player := league.Find(name); player != nil {
+			player.Wins += wins
+		} else {
+			league = append(league, Player{Name: name, Wins: wins})
+		}
+	}
+}
+
+// MergeLeagues returns a League with every player from both leagues, adding up the wins of
+// players who are in both.
+func MergeLeagues(a, b League) League {
+	merged := append(League{}, a...)
+
+	for _, p := range b {
+		if player := merged.Find(p.Name); player != nil {
+			player.Wins += p.Wins
+		} else {
+			merged = append(merged, p)
+		}
+	}
+
+	return merged
+}
@@ -0,0 +1,118 @@
+package poker_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+// failingUserStore can't store anyone, like a database that has gone away.
+type failingUserStore struct {
+	poker.UserStore
+}
+
+func (failingUserStore) AddUser(poker.User) error {
+	return errors.New("disk full")
+}
+
+func TestErrorResponses(t *testing.T) {
+	server := mustMakePlayerServer(t, &poker.StubPlayerStore{}, dummyGame)
+	brokenAccounts, err := poker.NewPlayerServer(&poker.StubPlayerStore{}, dummyGame, poker.WithAccounts(failingUserStore{}))
+	if err != nil {
+		t.Fatal("problem creating player server", err)
+	}
+
+	cases := []struct {
+		name       string
+		server     http.Handler
+		request    *http.Request
+		wantStatus int
+		want       apperrors.Response
+	}{
+		{
+			name:       "400 for invalid input",
+			server:     server,
+			request:    newPostWinRequest(""),
+			wantStatus: http.StatusBadRequest,
+			want: apperrors.Response{
+				Code:    "bad_request",
+				Message: "invalid player: a name is required to record a win",
+				Details: map[string]string{"field": "player", "reason": "a name is required to record a win"},
+			},
+		},
+		{
+			name:       "404 for a missing player",
+			server:     server,
+			request:    newGetScoreRequest("Apollo"),
+			wantStatus: http.StatusNotFound,
+			want: apperrors.Response{
+				Code:    "not_found",
+				Message: `player "Apollo" not found`,
+				Details: map[string]string{"resource": "player", "id": "Apollo"},
+			},
+		},
+		{
+			name:       "404 for a route that doesn't exist",
+			server:     server,
+			request:    httptest.NewRequest(http.MethodGet, "/nowhere", nil),
+			wantStatus: http.StatusNotFound,
+			want:       apperrors.Response{Code: "not_found", Message: "page not found"},
+		},
+		{
+			name:       "405 for a method a route doesn't allow",
+			server:     server,
+			request:    httptest.NewRequest(http.MethodDelete, "/league", nil),
+			wantStatus: http.StatusMethodNotAllowed,
+			want:       apperrors.Response{Code: "method_not_allowed", Message: "method not allowed"},
+		},
+		{
+			name:       "500 when something goes wrong on our side",
+			server:     brokenAccounts,
+			request:    newFormRequest("/register", "Ruth", "correct horse battery staple"),
+			wantStatus: http.StatusInternalServerError,
+			want:       apperrors.Response{Code: "internal_server_error", Message: "disk full"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+
+			c.server.ServeHTTP(response, c.request)
+
+			assertStatus(t, response, c.wantStatus)
+			assertErrorResponse(t, response, c.want)
+		})
+	}
+}
+
+func decodeErrorResponse(t testing.TB, response *httptest.ResponseRecorder) apperrors.Response {
+	t.Helper()
+	assertContentType(t, response, "application/json")
+
+	var got apperrors.Response
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatalf("could not decode error response %q, %v", response.Body.String(), err)
+	}
+	return got
+}
+
+func assertErrorResponse(t testing.TB, response *httptest.ResponseRecorder, want apperrors.Response) {
+	t.Helper()
+	if got := decodeErrorResponse(t, response); !reflect.DeepEqual(got, want) {
+		t.Errorf("got error response %#v want %#v", got, want)
+	}
+}
+
+func assertErrorMessage(t testing.TB, response *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	if got := decodeErrorResponse(t, response).Message; got != want {
+		t.Errorf("got error message %q want %q", got, want)
+	}
+}
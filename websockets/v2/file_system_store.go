@@ -1,41 +1,100 @@
 package poker
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"io"
+	"log"
 	"os"
+	"slices"
 	"sort"
+	"sync"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/websockets/v2/filelock"
 )
 
-// FileSystemPlayerStore stores players in the filesystem.
+// FileSystemPlayerStore stores players in the filesystem. The file is locked while it is read
+// or written, and re-read every time, so several processes, such as the CLI and the webserver,
+// can share it without losing each other's wins.
 type FileSystemPlayerStore struct {
-	database *json.Encoder
-	league   League
+	file     *os.File
+	database io.Writer
+	codec    Codec
+	saved    League
+	lock     sync.Mutex
+
+	batchSize int
+	// unflushed is the wins not yet written to the file, and waiting how many there are
+	unflushed   League
+	waiting     int
+	flushes     <-chan time.Time
+	stopFlusher chan struct{}
+	flusherDone chan struct{}
+	// failed is the first error flushing wins for RecordWin, AddWins or on a tick, kept for Flush
+	// to return as they can't
+	failed error
+}
+
+// FileSystemPlayerStoreOption configures optional behaviour of a FileSystemPlayerStore.
+type FileSystemPlayerStoreOption func(*FileSystemPlayerStore)
+
+// WithBatching stops RecordWin writing to disk on every call. Instead the league is written once
+// batchSize wins are waiting, whenever flushes receives a value (pass a time.Ticker's C), or when
+// Flush or Close are called.
+//
+// This trades durability for throughput: if the process dies before a flush, the wins recorded
+// since the last one are lost.
+func WithBatching(batchSize int, flushes <-chan time.Time) FileSystemPlayerStoreOption {
+	return func(f *FileSystemPlayerStore) {
+		f.batchSize = batchSize
+		f.flushes = flushes
+	}
+}
+
+// WithCodec changes the format the league is stored in, which is JSON by default.
+func WithCodec(codec Codec) FileSystemPlayerStoreOption {
+	return func(f *FileSystemPlayerStore) {
+		f.codec = codec
+	}
 }
 
 // NewFileSystemPlayerStore creates a FileSystemPlayerStore initialising the store if needed.
-func NewFileSystemPlayerStore(file *os.File) (*FileSystemPlayerStore, error) {
+func NewFileSystemPlayerStore(file *os.File, options ...FileSystemPlayerStoreOption) (*FileSystemPlayerStore, error) {
+	store := &FileSystemPlayerStore{
+		file:     file,
+		database: &Tape{file},
+		codec:    JSONCodec{},
+	}
 
-	err := initialisePlayerDBFile(file)
+	for _, option := range options {
+		option(store)
+	}
 
-	if err != nil {
+	if err := filelock.Lock(file); err != nil {
+		return nil, fmt.Errorf("problem locking player db file, %v", err)
+	}
+	defer filelock.Unlock(file)
+
+	if err := initialisePlayerDBFile(file, store.codec); err != nil {
 		return nil, fmt.Errorf("problem initialising player db file, %v", err)
 	}
 
-	league, err := NewLeague(file)
+	if err := store.reload(); err != nil {
+		return nil, err
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("problem loading player store from file %s, %v", file.Name(), err)
+	if store.flushes != nil {
+		store.stopFlusher = make(chan struct{})
+		store.flusherDone = make(chan struct{})
+		go store.flushOnTick()
 	}
 
-	return &FileSystemPlayerStore{
-		database: json.NewEncoder(&Tape{file}),
-		league:   league,
-	}, nil
+	return store, nil
 }
 
 // FileSystemPlayerStoreFromFile creates a PlayerStore from the contents of a JSON file found at path.
+// Closing it logs any wins which couldn't be written.
 func FileSystemPlayerStoreFromFile(path string) (*FileSystemPlayerStore, func(), error) {
 	db, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
 
@@ -43,20 +102,23 @@ func FileSystemPlayerStoreFromFile(path string) (*FileSystemPlayerStore, func(),
 		return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
 	}
 
-	closeFunc := func() {
-		db.Close()
-	}
-
 	store, err := NewFileSystemPlayerStore(db)
 
 	if err != nil {
 		return nil, nil, fmt.Errorf("problem creating file system player store, %v ", err)
 	}
 
+	closeFunc := func() {
+		if err := store.Close(); err != nil {
+			log.Printf("problem writing the last wins to %s, %v", path, err)
+		}
+		db.Close()
+	}
+
 	return store, closeFunc, nil
 }
 
-func initialisePlayerDBFile(file *os.File) error {
+func initialisePlayerDBFile(file *os.File, codec Codec) error {
 	file.Seek(0, io.SeekStart)
 
 	info, err := file.Stat()
@@ -66,7 +128,9 @@ func initialisePlayerDBFile(file *os.File) error {
 	}
 
 	if info.Size() == 0 {
-		file.Write([]byte("[]"))
+		if err := codec.Encode(file, League{}); err != nil {
+			return fmt.Errorf("problem writing empty league to file %s, %v", file.Name(), err)
+		}
 		file.Seek(0, io.SeekStart)
 	}
 
@@ -75,16 +139,22 @@ func initialisePlayerDBFile(file *os.File) error {
 
 // GetLeague returns the Scores of all the players.
 func (f *FileSystemPlayerStore) GetLeague() League {
-	sort.Slice(f.league, func(i, j int) bool {
-		return f.league[i].Wins > f.league[j].Wins
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	league := f.current()
+	sort.Slice(league, func(i, j int) bool {
+		return league[i].Wins > league[j].Wins
 	})
-	return f.league
+	return league
 }
 
 // GetPlayerScore retrieves a player's score.
 func (f *FileSystemPlayerStore) GetPlayerScore(name string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
 
-	player := f.league.Find(name)
+	player := f.current().Find(name)
 
 	if player != nil {
 		return player.Wins
@@ -95,13 +165,152 @@ func (f *FileSystemPlayerStore) GetPlayerScore(name string) int {
 
 // RecordWin will store a win for a player, incrementing wins if already known.
 func (f *FileSystemPlayerStore) RecordWin(name string) {
-	player := f.league.Find(name)
+	f.AddWins(name, 1)
+}
 
-	if player != nil {
-		player.Wins++
-	} else {
-		f.league = append(f.league, Player{name, 1})
+// AddWins stores wins for a player in one go, adding them to any they already have.
+func (f *FileSystemPlayerStore) AddWins(name string, wins int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.unflushed = append(f.unflushed, Player{name, wins})
+	f.waiting += wins
+	if f.waiting >= f.batchSize {
+		f.flushOrRemember()
+	}
+}
+
+// Flush writes any wins which have not yet been written to disk. Wins which couldn't be written
+// earlier are kept and tried again, and if they now are Flush still returns the first error
+// from writing them since it was last called, so failures don't go unnoticed.
+func (f *FileSystemPlayerStore) Flush() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	err := f.flush()
+	if err == nil {
+		err = f.failed
+	}
+	f.failed = nil
+	return err
+}
+
+// Close stops any background flushing and writes outstanding wins to disk, returning any error
+// Flush would.
+func (f *FileSystemPlayerStore) Close() error {
+	if f.stopFlusher != nil {
+		close(f.stopFlusher)
+		<-f.flusherDone
+		f.stopFlusher = nil
+	}
+	return f.Flush()
+}
+
+// current is the league as last read from the file, plus the wins not yet written to it.
+// Another process may have written to the file since, so it is read again if we can get a lock.
+func (f *FileSystemPlayerStore) current() League {
+	if err := filelock.RLock(f.file); err == nil {
+		// if the file has been broken the last league read from it is the best we have
+		f.reload()
+		filelock.Unlock(f.file)
 	}
 
-	f.database.Encode(f.league)
+	return withWins(f.saved, f.unflushed)
+}
+
+// reload reads the league from the file, which must be locked.
+func (f *FileSystemPlayerStore) reload() error {
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("problem reading player store from file %s, %v", f.file.Name(), err)
+	}
+
+	league, err := f.codec.Decode(f.file)
+
+	if err != nil {
+		return fmt.Errorf("problem loading player store from file %s, %v", f.file.Name(), err)
+	}
+
+	if err := league.Validate(); err != nil {
+		return fmt.Errorf("problem loading player store from file %s, %w", f.file.Name(), err)
+	}
+
+	f.saved = league
+	return nil
+}
+
+// flush adds the unflushed wins to the league in the file. The file stays locked from reading it
+// to writing it back, so wins another process flushes at the same time aren't overwritten.
+func (f *FileSystemPlayerStore) flush() error {
+	if len(f.unflushed) == 0 {
+		return nil
+	}
+
+	if err := filelock.Lock(f.file); err != nil {
+		return fmt.Errorf("problem locking league file, %v", err)
+	}
+	defer filelock.Unlock(f.file)
+
+	if err := f.reload(); err != nil {
+		return err
+	}
+
+	league := withWins(f.saved, f.unflushed)
+
+	// Tape rewrites the file on every Write, so the league must reach it in a single call
+	// even if the codec writes in several chunks.
+	var buf bytes.Buffer
+	if err := f.codec.Encode(&buf, league); err != nil {
+		return fmt.Errorf("problem encoding league, %v", err)
+	}
+
+	if _, err := f.database.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("problem writing league to disk, %v", err)
+	}
+
+	f.saved = league
+	f.unflushed, f.waiting = nil, 0
+	return nil
+}
+
+// flushOrRemember flushes, keeping the first error for Flush to return.
+func (f *FileSystemPlayerStore) flushOrRemember() {
+	if err := f.flush(); err != nil && f.failed == nil {
+		f.failed = err
+	}
+}
+
+// withWins returns a copy of league with the wins of each of added added to them. Players who
+// aren't in league yet join it, in the order they were added.
+func withWins(league League, added League) League {
+	league = slices.Clone(league)
+
+	index := make(map[string]int, len(league))
+	for i, player := range league {
+		index[player.Name] = i
+	}
+
+	for _, player := range added {
+		if i, ok := index[player.Name]; ok {
+			league[i].Wins += player.Wins
+		} else {
+			index[player.Name] = len(league)
+			league = append(league, player)
+		}
+	}
+
+	return league
+}
+
+func (f *FileSystemPlayerStore) flushOnTick() {
+	defer close(f.flusherDone)
+	for {
+		select {
+		case <-f.flushes:
+			f.lock.Lock()
+			f.flushOrRemember()
+			f.lock.Unlock()
+		case <-f.stopFlusher:
+			return
+		}
+	}
 }
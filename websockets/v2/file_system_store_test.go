@@ -1,8 +1,13 @@
 package poker_test
 
 import (
+	"errors"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	poker "github.com/quii/learn-go-with-tests/websockets/v2"
 )
@@ -111,6 +116,238 @@ func TestFileSystemStore(t *testing.T) {
 	})
 }
 
+func TestFileSystemStoreValidation(t *testing.T) {
+	t.Run("rejects records with fields a player doesn't have", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[{"Name": "Cleo", "Wins": 10, "Losses": 2}]`)
+		defer cleanDatabase()
+
+		_, err := poker.NewFileSystemPlayerStore(database)
+
+		if err == nil || !strings.Contains(err.Error(), "Losses") {
+			t.Errorf("expected an error naming the unknown field, got %v", err)
+		}
+	})
+
+	t.Run("rejects files which aren't a league", func(t *testing.T) {
+		for _, contents := range []string{`{"Name": "Cleo"}`, `[{"Name": "Cleo", "Wins": "ten"}]`, `[{"Name": "Cleo"`} {
+			database, cleanDatabase := createTempFile(t, contents)
+
+			if _, err := poker.NewFileSystemPlayerStore(database); err == nil {
+				t.Errorf("expected an error loading %s", contents)
+			}
+			cleanDatabase()
+		}
+	})
+
+	t.Run("reports every invalid player", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[
+			{"Name": "Cleo", "Wins": 10},
+			{"Name": "", "Wins": 3},
+			{"Name": "Chris", "Wins": -1},
+			{"Name": "", "Wins": -5}]`)
+		defer cleanDatabase()
+
+		_, err := poker.NewFileSystemPlayerStore(database)
+
+		var got *poker.LeagueValidationError
+		if !errors.As(err, &got) {
+			t.Fatalf("expected a LeagueValidationError, got %v", err)
+		}
+
+		want := []poker.InvalidPlayer{
+			{Index: 1, Player: poker.Player{Name: "", Wins: 3}, Problems: []string{"name must not be empty"}},
+			{Index: 2, Player: poker.Player{Name: "Chris", Wins: -1}, Problems: []string{"wins must not be negative, got -1"}},
+			{Index: 3, Player: poker.Player{Name: "", Wins: -5}, Problems: []string{"name must not be empty", "wins must not be negative, got -5"}},
+		}
+		if !reflect.DeepEqual(got.Invalid, want) {
+			t.Errorf("got invalid players %+v want %+v", got.Invalid, want)
+		}
+
+		wantMsg := `league has 3 invalid players
+  player 1 (""): name must not be empty
+  player 2 ("Chris"): wins must not be negative, got -1
+  player 3 (""): name must not be empty, wins must not be negative, got -5`
+		if !strings.HasSuffix(err.Error(), wantMsg) {
+			t.Errorf("got error %q want it to end with %q", err, wantMsg)
+		}
+	})
+
+	t.Run("a valid league passes", func(t *testing.T) {
+		league := poker.League{{Name: "Cleo", Wins: 0}}
+		assertNoError(t, league.Validate())
+	})
+}
+
+func TestFileSystemStoreBatching(t *testing.T) {
+	t.Run("wins are written to disk once the batch is full", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := poker.NewFileSystemPlayerStore(database, poker.WithBatching(3, nil))
+		assertNoError(t, err)
+
+		store.RecordWin("Chris")
+		store.RecordWin("Chris")
+		assertScoreEquals(t, scoreOnDisk(t, database, "Chris"), 0)
+
+		store.RecordWin("Chris")
+		assertScoreEquals(t, scoreOnDisk(t, database, "Chris"), 3)
+	})
+
+	t.Run("wins are written to disk when the ticker fires", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		ticks := make(chan time.Time)
+		store, err := poker.NewFileSystemPlayerStore(database, poker.WithBatching(100, ticks))
+		assertNoError(t, err)
+		defer store.Close()
+
+		store.RecordWin("Chris")
+		ticks <- time.Now()
+
+		flushed := retryUntil(500*time.Millisecond, func() bool {
+			return scoreOnDisk(t, database, "Chris") == 1
+		})
+
+		if !flushed {
+			t.Error("expected the win to be flushed to disk after a tick")
+		}
+	})
+
+	t.Run("Flush and Close write outstanding wins", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := poker.NewFileSystemPlayerStore(database, poker.WithBatching(100, make(chan time.Time)))
+		assertNoError(t, err)
+
+		store.RecordWin("Chris")
+		assertNoError(t, store.Flush())
+		assertScoreEquals(t, scoreOnDisk(t, database, "Chris"), 1)
+
+		store.RecordWin("Chris")
+		assertNoError(t, store.Close())
+		assertScoreEquals(t, scoreOnDisk(t, database, "Chris"), 2)
+	})
+
+	t.Run("wins which couldn't be written are kept, and Flush reports why", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := poker.NewFileSystemPlayerStore(database)
+		assertNoError(t, err)
+
+		assertNoError(t, os.WriteFile(database.Name(), []byte("not a league"), 0666))
+		store.RecordWin("Chris")
+
+		assertNoError(t, os.WriteFile(database.Name(), []byte("[]"), 0666))
+		if err := store.Flush(); err == nil {
+			t.Error("expected the earlier failure to be reported")
+		}
+		assertScoreEquals(t, scoreOnDisk(t, database, "Chris"), 1)
+
+		assertNoError(t, store.Flush())
+	})
+
+	t.Run("Close reports wins which still can't be written", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		ticks := make(chan time.Time)
+		store, err := poker.NewFileSystemPlayerStore(database, poker.WithBatching(100, ticks))
+		assertNoError(t, err)
+
+		store.RecordWin("Chris")
+		assertNoError(t, os.WriteFile(database.Name(), []byte("not a league"), 0666))
+		ticks <- time.Now()
+
+		if err := store.Close(); err == nil {
+			t.Error("expected an error closing the store")
+		}
+	})
+
+	t.Run("wins which have not been flushed are lost if the process crashes", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := poker.NewFileSystemPlayerStore(database, poker.WithBatching(100, nil))
+		assertNoError(t, err)
+
+		store.RecordWin("Chris")
+		assertScoreEquals(t, store.GetPlayerScore("Chris"), 1)
+
+		// "crash" by abandoning the store without flushing, then start again from the file
+		restarted, err := poker.NewFileSystemPlayerStore(database)
+		assertNoError(t, err)
+
+		assertScoreEquals(t, restarted.GetPlayerScore("Chris"), 0)
+	})
+}
+
+func TestFileSystemStoreSharedFile(t *testing.T) {
+	database, cleanDatabase := createTempFile(t, "")
+	defer cleanDatabase()
+
+	// each store opens the file for itself, as the CLI and webserver would in separate processes
+	openStore := func() *poker.FileSystemPlayerStore {
+		file, err := os.OpenFile(database.Name(), os.O_RDWR, 0666)
+		assertNoError(t, err)
+		t.Cleanup(func() { file.Close() })
+
+		store, err := poker.NewFileSystemPlayerStore(file)
+		assertNoError(t, err)
+		return store
+	}
+
+	cli, webserver := openStore(), openStore()
+
+	t.Run("each store sees the other's wins", func(t *testing.T) {
+		cli.RecordWin("Chris")
+		webserver.RecordWin("Cleo")
+
+		assertScoreEquals(t, webserver.GetPlayerScore("Chris"), 1)
+		assertScoreEquals(t, cli.GetPlayerScore("Cleo"), 1)
+	})
+
+	t.Run("wins recorded at the same time are all kept", func(t *testing.T) {
+		const wins = 50
+
+		var wg sync.WaitGroup
+		for _, store := range []*poker.FileSystemPlayerStore{cli, webserver} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < wins; i++ {
+					store.RecordWin("Chris")
+				}
+			}()
+		}
+		wg.Wait()
+
+		assertScoreEquals(t, scoreOnDisk(t, database, "Chris"), 1+2*wins)
+		assertScoreEquals(t, openStore().GetPlayerScore("Chris"), 1+2*wins)
+	})
+}
+
+func scoreOnDisk(t testing.TB, database *os.File, name string) int {
+	t.Helper()
+
+	file, err := os.Open(database.Name())
+	assertNoError(t, err)
+	defer file.Close()
+
+	league, err := poker.NewLeague(file)
+	if err != nil {
+		return 0
+	}
+
+	if player := league.Find(name); player != nil {
+		return player.Wins
+	}
+	return 0
+}
+
 func assertScoreEquals(t testing.TB, got, want int) {
 	t.Helper()
 	if got != want {
@@ -0,0 +1,32 @@
+// Package filelock takes advisory locks on files, so that processes sharing a file, such as the
+// CLI and the webserver both using game.db.json, can take turns reading and writing it.
+//
+// The locks are advisory: they only keep out other processes which also lock the file. A lock
+// belongs to the *os.File it was taken on, so two *os.Files opened on the same path, even in one
+// process, lock each other out.
+package filelock
+
+import "os"
+
+// Lock waits for, then takes, an exclusive lock on f, for writing it.
+func Lock(f *os.File) error {
+	return lock(f, exclusive)
+}
+
+// RLock waits for, then takes, a shared lock on f, for reading it. Any number of shared locks
+// can be held at once, but not while someone holds an exclusive one.
+func RLock(f *os.File) error {
+	return lock(f, shared)
+}
+
+// Unlock releases the lock held on f.
+func Unlock(f *os.File) error {
+	return unlock(f)
+}
+
+type lockType int
+
+const (
+	shared lockType = iota
+	exclusive
+)
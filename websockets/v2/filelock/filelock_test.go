@@ -0,0 +1,80 @@
+package filelock_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/websockets/v2/filelock"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "league.json")
+	first, second := openTwice(t, path)
+
+	t.Run("an exclusive lock waits for the other to be released", func(t *testing.T) {
+		assertNoError(t, filelock.Lock(first))
+
+		locked := lockInBackground(filelock.Lock, second)
+		assertStillWaiting(t, locked)
+
+		assertNoError(t, filelock.Unlock(first))
+		assertNoError(t, <-locked)
+		assertNoError(t, filelock.Unlock(second))
+	})
+
+	t.Run("a shared lock waits for an exclusive lock", func(t *testing.T) {
+		assertNoError(t, filelock.Lock(first))
+
+		locked := lockInBackground(filelock.RLock, second)
+		assertStillWaiting(t, locked)
+
+		assertNoError(t, filelock.Unlock(first))
+		assertNoError(t, <-locked)
+		assertNoError(t, filelock.Unlock(second))
+	})
+
+	t.Run("shared locks can be held together", func(t *testing.T) {
+		assertNoError(t, filelock.RLock(first))
+		assertNoError(t, filelock.RLock(second))
+
+		assertNoError(t, filelock.Unlock(first))
+		assertNoError(t, filelock.Unlock(second))
+	})
+}
+
+func openTwice(t *testing.T, path string) (*os.File, *os.File) {
+	t.Helper()
+
+	open := func() *os.File {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+		assertNoError(t, err)
+		t.Cleanup(func() { f.Close() })
+		return f
+	}
+
+	return open(), open()
+}
+
+func lockInBackground(lock func(*os.File) error, f *os.File) <-chan error {
+	locked := make(chan error, 1)
+	go func() { locked <- lock(f) }()
+	return locked
+}
+
+func assertStillWaiting(t testing.TB, locked <-chan error) {
+	t.Helper()
+	select {
+	case err := <-locked:
+		t.Fatalf("expected the lock to wait, but it was taken with error %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build darwin || dragonfly || freebsd || illumos || linux || netbsd || openbsd
+
+package filelock
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func lock(f *os.File, lt lockType) error {
+	how := syscall.LOCK_SH
+	if lt == exclusive {
+		how = syscall.LOCK_EX
+	}
+	return flock(f, how)
+}
+
+func unlock(f *os.File) error {
+	return flock(f, syscall.LOCK_UN)
+}
+
+func flock(f *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		if !errors.Is(err, syscall.EINTR) {
+			return wrap(f, err)
+		}
+	}
+}
+
+func wrap(f *os.File, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &os.PathError{Op: "flock", Path: f.Name(), Err: err}
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package filelock
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lock the whole file, however big it gets
+const allBytes = ^uint32(0)
+
+func lock(f *os.File, lt lockType) error {
+	var flags uint32
+	if lt == exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+
+	ol := new(windows.Overlapped)
+	return wrap(f, "LockFileEx", windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, allBytes, allBytes, ol))
+}
+
+func unlock(f *os.File) error {
+	ol := new(windows.Overlapped)
+	return wrap(f, "UnlockFileEx", windows.UnlockFileEx(windows.Handle(f.Fd()), 0, allBytes, allBytes, ol))
+}
+
+func wrap(f *os.File, op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &os.PathError{Op: op, Path: f.Name(), Err: err}
+}
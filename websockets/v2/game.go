@@ -7,3 +7,10 @@ type Game interface {
 	Start(numberOfPlayers int, alertsDestination io.Writer)
 	Finish(winner string)
 }
+
+// GameMaker is a Game which can make more games like itself. Each game played over /ws/{id} is
+// made by NewGame, so games played at the same time keep their own state.
+type GameMaker interface {
+	Game
+	NewGame() Game
+}
@@ -0,0 +1,359 @@
+package poker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultResumeWindow is how long a game nobody is connected to is kept for its player to come back.
+const defaultResumeWindow = 10 * time.Minute
+
+// WithResumeWindow keeps a game played over /ws/{id} for window after everyone has disconnected,
+// so its player can reconnect and carry on. It is 10 minutes otherwise. Games which never
+// started are forgotten straight away. Times are told by clock, which is the real one if nil.
+func WithResumeWindow(window time.Duration, clock Clock) PlayerServerOption {
+	return func(p *PlayerServer) {
+		p.resumeWindow = window
+		if clock != nil {
+			p.clock = clock
+		}
+	}
+}
+
+// errGameTaken is returned by join when someone else started the game.
+var errGameTaken = errors.New(gameTakenMsg)
+
+// gameSessions remembers games being played over websockets by their ID, so that a player whose
+// connection drops can reconnect to the same game and others can watch it.
+type gameSessions struct {
+	lock     sync.Mutex
+	sessions map[string]*gameSession
+	timer    *ActionTimer
+	window   time.Duration
+	clock    Clock
+	// newGame makes the Game each session plays
+	newGame func() Game
+}
+
+// gameSession is everyone connected to one game. Blind alerts written to it go to the player and
+// every spectator.
+type gameSession struct {
+	// owner is who started the game, if the server has accounts
+	owner string
+	game  Game
+	// visitors is how many connections have joined or are watching the game, and forget is
+	// set while nobody is. Both are guarded by gameSessions' lock.
+	visitors int
+	forget   func() bool
+
+	lock       sync.Mutex
+	started    bool
+	table      *Table
+	player     *playerServerWS
+	spectators []*playerServerWS
+	// timer times the turns of players at the table, if there is an ActionTimer
+	timer *turnTimer
+}
+
+// Presence is sent to everyone connected to a game when someone joins or leaves it.
+type Presence struct {
+	Event      string `json:"presence"`
+	Role       string `json:"role"`
+	Spectators int    `json:"spectators"`
+}
+
+// Presence events and roles.
+const (
+	PresenceJoin  = "join"
+	PresenceLeave = "leave"
+
+	RolePlayer    = "player"
+	RoleSpectator = "spectator"
+)
+
+// GamePresence is who is connected to a game right now.
+type GamePresence struct {
+	Game            string `json:"game"`
+	PlayerConnected bool   `json:"playerConnected"`
+	Spectators      int    `json:"spectators"`
+}
+
+func newGameSessions(newGame func() Game, timer *ActionTimer, window time.Duration, clock Clock) *gameSessions {
+	return &gameSessions{sessions: map[string]*gameSession{}, newGame: newGame, timer: timer, window: window, clock: clock}
+}
+
+// join lets owner play the game with id, creating it if it is new. Only whoever created a game
+// can play it; without accounts everyone's owner is "", so anyone who knows the ID can. Call
+// leave once the player has disconnected.
+func (g *gameSessions) join(id, owner string) (*gameSession, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	session, exists := g.sessions[id]
+	if !exists {
+		session = &gameSession{owner: owner, game: g.newGame()}
+		if g.timer != nil {
+			session.timer = newTurnTimer(*g.timer)
+		}
+		g.sessions[id] = session
+	}
+	if session.owner != owner {
+		return nil, errGameTaken
+	}
+
+	g.visit(session)
+	return session, nil
+}
+
+// watch finds the game with id for a spectator. Call leave once they have disconnected.
+func (g *gameSessions) watch(id string) (*gameSession, bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	session, ok := g.sessions[id]
+	if ok {
+		g.visit(session)
+	}
+	return session, ok
+}
+
+// visit must be called with the lock held. The first visitor to a game nobody was connected to
+// starts the clock on whoever's turn it is again.
+func (g *gameSessions) visit(session *gameSession) {
+	session.visitors++
+	if session.forget != nil {
+		session.forget()
+		session.forget = nil
+
+		session.lock.Lock()
+		session.timeTurn()
+		session.lock.Unlock()
+	}
+}
+
+// leave is called when a connection to the game with id closes. Once nobody is connected a
+// game which never started is forgotten, and one which did is kept for the resume window.
+func (g *gameSessions) leave(id string, session *gameSession) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	session.visitors--
+	if session.visitors > 0 || g.sessions[id] != session {
+		return
+	}
+
+	// nobody is there to act, so stop the clock until someone comes back
+	session.lock.Lock()
+	started := session.started
+	if session.timer != nil {
+		session.timer.finish()
+	}
+	session.lock.Unlock()
+
+	if !started {
+		g.remove(id)
+		return
+	}
+	session.forget = g.clock.AfterFunc(g.window, func() {
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		if g.sessions[id] == session && session.visitors == 0 {
+			g.remove(id)
+		}
+	})
+}
+
+// find returns the game with id, if anyone is connected to it or it is waiting for its player
+// to come back.
+func (g *gameSessions) find(id string) (*gameSession, bool) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	session, ok := g.sessions[id]
+	return session, ok
+}
+
+func (g *gameSessions) markStarted(id string) {
+	if session, ok := g.find(id); ok {
+		session.lock.Lock()
+		session.started = true
+		session.lock.Unlock()
+	}
+}
+
+func (g *gameSessions) end(id string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.remove(id)
+}
+
+// remove must be called with the lock held.
+func (g *gameSessions) remove(id string) {
+	session, ok := g.sessions[id]
+	if !ok {
+		return
+	}
+	delete(g.sessions, id)
+
+	if session.forget != nil {
+		session.forget()
+		session.forget = nil
+	}
+	if session.timer != nil {
+		session.lock.Lock()
+		session.timer.stop()
+		session.lock.Unlock()
+	}
+}
+
+func (s *gameSession) connectPlayer(player *playerServerWS) (resumed bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.player = player
+	return s.started
+}
+
+func (s *gameSession) disconnectPlayer(player *playerServerWS) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.player != player {
+		return
+	}
+	s.player = nil
+	s.broadcast(PresenceLeave, RolePlayer)
+}
+
+// addSpectator connects spectator to the game, reporting whether the game has started.
+func (s *gameSession) addSpectator(spectator *playerServerWS) (started bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.spectators = append(s.spectators, spectator)
+	return s.started
+}
+
+func (s *gameSession) removeSpectator(spectator *playerServerWS) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for i, ws := range s.spectators {
+		if ws == spectator {
+			s.spectators = append(s.spectators[:i], s.spectators[i+1:]...)
+			s.broadcast(PresenceLeave, RoleSpectator)
+			return
+		}
+	}
+}
+
+func (s *gameSession) presence(id string) GamePresence {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return GamePresence{Game: id, PlayerConnected: s.player != nil, Spectators: len(s.spectators)}
+}
+
+// announce tells everyone connected, including whoever just joined, that someone joined.
+func (s *gameSession) announce(role string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.broadcast(PresenceJoin, role)
+}
+
+// broadcast sends a Presence to everyone connected. It must be called with the lock held, so
+// that everyone sees joins and leaves in the same order.
+func (s *gameSession) broadcast(event, role string) {
+	presence := Presence{Event: event, Role: role, Spectators: len(s.spectators)}
+	for _, ws := range s.connections() {
+		ws.writePresence(presence)
+	}
+}
+
+func (s *gameSession) connections() []*playerServerWS {
+	if s.player == nil {
+		return s.spectators
+	}
+	return append([]*playerServerWS{s.player}, s.spectators...)
+}
+
+// seat remembers the table the game is played at and tells everyone connected who is sitting where.
+func (s *gameSession) seat(table *Table) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.table = table
+	s.broadcastSeating()
+	s.timeTurn()
+}
+
+// seating is who is sitting where, if the players were seated at a table.
+func (s *gameSession) seating() (Seating, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.table == nil {
+		return Seating{}, false
+	}
+	return s.table.Seating(), true
+}
+
+// play carries out a command at the game's table, telling everyone connected about the new
+// seating. It reports whether command was one; anything else is the winner being declared.
+func (s *gameSession) play(command string) (ok bool, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.table == nil {
+		return false, nil
+	}
+
+	turn := s.table.turns
+	ok, err = s.table.play(command)
+	if ok && err == nil {
+		s.broadcastSeating()
+		if s.table.turns != turn {
+			s.timeTurn()
+		}
+	}
+	return ok, err
+}
+
+// playStack carries out a command changing the stack of a player at the game's table, reporting
+// whether it was one.
+func (s *gameSession) playStack(command string) (ok bool, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.table == nil {
+		return false, nil
+	}
+	return playStack(s.game, s.table, command)
+}
+
+// broadcastSeating must be called with the lock held.
+func (s *gameSession) broadcastSeating() {
+	seating := s.table.Seating()
+	for _, ws := range s.connections() {
+		ws.writeSeating(seating)
+	}
+}
+
+// Write sends a blind alert to everyone connected. Alerts sent while the player is disconnected
+// are dropped for them; a player who reconnects is sent a GameState instead.
+func (s *gameSession) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.writeAlert(p)
+}
+
+// writeAlert must be called with the lock held.
+func (s *gameSession) writeAlert(p []byte) (int, error) {
+	for _, spectator := range s.spectators {
+		spectator.Write(p)
+	}
+
+	if s.player == nil {
+		return len(p), nil
+	}
+	return s.player.Write(p)
+}
@@ -0,0 +1,124 @@
+package poker
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// GameRecord is a game that was played to the end.
+type GameRecord struct {
+	Winner     string    `json:"winner"`
+	Players    int       `json:"players"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// Duration is how long the game went on for.
+func (g GameRecord) Duration() time.Duration {
+	return g.FinishedAt.Sub(g.StartedAt)
+}
+
+// GameHistory is every game played to the end, in the order they finished.
+type GameHistory interface {
+	Games() []GameRecord
+}
+
+// GameStore keeps a GameHistory.
+type GameStore interface {
+	GameHistory
+	RecordGame(game GameRecord) error
+}
+
+// WithGameStore records every game TexasHoldem finishes in games, timed with its clock.
+func WithGameStore(games GameStore) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.games = games
+	}
+}
+
+// WithGameHistory adds the average length of the games in history to the stats served at
+// /players/{name}/stats.
+func WithGameHistory(history GameHistory) PlayerServerOption {
+	return func(p *PlayerServer) {
+		p.gameHistory = history
+	}
+}
+
+// GameLog is a GameStore kept by appending each game to a file as a line of JSON.
+type GameLog struct {
+	lock  sync.RWMutex
+	out   io.Writer
+	games []GameRecord
+}
+
+// NewGameLog reads the games already in rw, then appends new ones to it.
+func NewGameLog(rw io.ReadWriter) (*GameLog, error) {
+	log := &GameLog{out: rw}
+
+	scanner := bufio.NewScanner(rw)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var game GameRecord
+		if err := json.Unmarshal(scanner.Bytes(), &game); err != nil {
+			return nil, fmt.Errorf("problem reading game log line %d, %v", line, err)
+		}
+		log.games = append(log.games, game)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("problem reading game log, %v", err)
+	}
+
+	return log, nil
+}
+
+// GameLogFromFile opens, or creates, the game log at path.
+func GameLogFromFile(path string) (*GameLog, func(), error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
+	}
+
+	log, err := NewGameLog(file)
+
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return log, func() { file.Close() }, nil
+}
+
+// RecordGame appends game to the log.
+func (g *GameLog) RecordGame(game GameRecord) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	line, err := json.Marshal(game)
+	if err != nil {
+		return fmt.Errorf("problem recording game won by %s, %v", game.Winner, err)
+	}
+
+	if _, err := fmt.Fprintf(g.out, "%s\n", line); err != nil {
+		return fmt.Errorf("problem recording game won by %s, %v", game.Winner, err)
+	}
+
+	g.games = append(g.games, game)
+	return nil
+}
+
+// Games returns every game logged so far, oldest first.
+func (g *GameLog) Games() []GameRecord {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return append([]GameRecord(nil), g.games...)
+}
@@ -0,0 +1,58 @@
+package poker_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestGameLog(t *testing.T) {
+	start := time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)
+	chrisWins := poker.GameRecord{Winner: "Chris", Players: 3, StartedAt: start, FinishedAt: start.Add(30 * time.Minute)}
+	cleoWins := poker.GameRecord{Winner: "Cleo", Players: 6, StartedAt: start.Add(time.Hour), FinishedAt: start.Add(2 * time.Hour)}
+
+	t.Run("reads the games already logged and appends new ones", func(t *testing.T) {
+		file := &bytes.Buffer{}
+		first, _ := poker.NewGameLog(file)
+		assertNoError(t, first.RecordGame(chrisWins))
+
+		log, err := poker.NewGameLog(bytes.NewBuffer(file.Bytes()))
+		assertNoError(t, err)
+		assertNoError(t, log.RecordGame(cleoWins))
+
+		assertGames(t, log.Games(), []poker.GameRecord{chrisWins, cleoWins})
+	})
+
+	t.Run("games survive reopening a log file", func(t *testing.T) {
+		path := t.TempDir() + "/games.log"
+
+		log, closeLog, err := poker.GameLogFromFile(path)
+		assertNoError(t, err)
+		assertNoError(t, log.RecordGame(chrisWins))
+		closeLog()
+
+		reopened, closeLog, err := poker.GameLogFromFile(path)
+		assertNoError(t, err)
+		defer closeLog()
+
+		assertGames(t, reopened.Games(), []poker.GameRecord{chrisWins})
+	})
+
+	t.Run("a line that isn't a game is an error", func(t *testing.T) {
+		_, err := poker.NewGameLog(bytes.NewBufferString("{\"winner\":\"Chris\"}\nnot json\n"))
+
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}
+
+func assertGames(t testing.TB, got, want []poker.GameRecord) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got games %v want %v", got, want)
+	}
+}
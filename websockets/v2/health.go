@@ -0,0 +1,157 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthCheck reports whether something the server depends on is working, giving up when ctx is done.
+type HealthCheck func(ctx context.Context) error
+
+// The statuses in a HealthReport.
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+)
+
+// readinessTimeout is how long /readyz waits for its checks. A check that takes longer is failing.
+const readinessTimeout = 2 * time.Second
+
+// HealthReport is the JSON body of /healthz and /readyz.
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult is how one of the checks in a HealthReport went.
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// WithReadinessCheck adds a check to /readyz, alongside the ones for the store and blind alerter.
+func WithReadinessCheck(name string, check HealthCheck) PlayerServerOption {
+	return func(p *PlayerServer) {
+		if p.readinessChecks == nil {
+			p.readinessChecks = map[string]HealthCheck{}
+		}
+		p.readinessChecks[name] = check
+	}
+}
+
+// healthRoutes are for whatever runs the server, like a load balancer, rather than players.
+// /healthz says the process is up and /readyz whether it can actually serve requests.
+func (p *PlayerServer) healthRoutes() routingTable {
+	return routingTable{
+		{"/healthz", []string{http.MethodGet}, http.HandlerFunc(p.healthz)},
+		{"/readyz", []string{http.MethodGet}, http.HandlerFunc(p.readyz)},
+	}
+}
+
+func (p *PlayerServer) healthz(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, HealthReport{Status: StatusOK})
+}
+
+func (p *PlayerServer) readyz(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]HealthCheck{"store": p.checkStore}
+	if game, ok := p.game.(interface{ CheckAlerter(context.Context) error }); ok {
+		checks["blind alerter"] = game.CheckAlerter
+	}
+	for name, check := range p.readinessChecks {
+		checks[name] = check
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	writeHealthReport(w, runChecks(ctx, checks))
+}
+
+// runChecks runs checks at the same time, so one slow check doesn't use up the others' time.
+func runChecks(ctx context.Context, checks map[string]HealthCheck) HealthReport {
+	report := HealthReport{Status: StatusOK, Checks: map[string]CheckResult{}}
+
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := check(ctx)
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if err != nil {
+				report.Status = StatusFailing
+				report.Checks[name] = CheckResult{Status: StatusFailing, Error: err.Error()}
+				return
+			}
+			report.Checks[name] = CheckResult{Status: StatusOK}
+		}()
+	}
+
+	wg.Wait()
+	return report
+}
+
+func writeHealthReport(w http.ResponseWriter, report HealthReport) {
+	w.Header().Set("content-type", jsonContentType)
+	w.Header().Set("Cache-Control", "no-store")
+
+	if report.Status != StatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
+
+// checkStore reads the league. PlayerStore can't say it failed, but a store stuck waiting on
+// something, like a lock on its file held by another process, never answers.
+func (p *PlayerServer) checkStore(ctx context.Context) error {
+	read := make(chan struct{})
+	go func() {
+		p.store.GetLeague()
+		close(read)
+	}()
+
+	select {
+	case <-read:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("could not read the league: %w", ctx.Err())
+	}
+}
+
+// ErrAlertNotDelivered means a blind alert was scheduled but never arrived.
+var ErrAlertNotDelivered = errors.New("blind alert was not delivered")
+
+// CheckAlerter schedules an immediate alert, which nobody sees, and waits for it to be delivered.
+func (p *TexasHoldem) CheckAlerter(ctx context.Context) error {
+	delivered := &signalWriter{written: make(chan struct{})}
+	p.alerter.ScheduleAlertAt(0, 0, delivered)
+
+	select {
+	case <-delivered.written:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrAlertNotDelivered, ctx.Err())
+	}
+}
+
+// signalWriter discards what is written to it, closing written the first time.
+type signalWriter struct {
+	once    sync.Once
+	written chan struct{}
+}
+
+func (s *signalWriter) Write(b []byte) (int, error) {
+	s.once.Do(func() { close(s.written) })
+	return len(b), nil
+}
@@ -0,0 +1,127 @@
+package poker_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestHealthz(t *testing.T) {
+	server := mustMakePlayerServer(t, &poker.StubPlayerStore{}, dummyGame)
+
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assertStatus(t, response, http.StatusOK)
+	assertContentType(t, response, "application/json")
+	assertHealthReport(t, response, poker.HealthReport{Status: poker.StatusOK})
+}
+
+func TestReadyz(t *testing.T) {
+	// delivers every alert straight away, as poker.Alerter does for alerts due now
+	immediateAlerter := poker.BlindAlerterFunc(func(duration time.Duration, amount int, to io.Writer) {
+		fmt.Fprintf(to, "Blind is now %d\n", amount)
+	})
+
+	t.Run("ready when the store can be read and alerts are delivered", func(t *testing.T) {
+		store := &poker.StubPlayerStore{}
+		game := poker.NewTexasHoldem(immediateAlerter, store)
+		server := mustMakePlayerServer(t, store, game)
+
+		response := readyz(context.Background(), server)
+
+		assertStatus(t, response, http.StatusOK)
+		assertHealthReport(t, response, poker.HealthReport{
+			Status: poker.StatusOK,
+			Checks: map[string]poker.CheckResult{
+				"store":         {Status: poker.StatusOK},
+				"blind alerter": {Status: poker.StatusOK},
+			},
+		})
+	})
+
+	t.Run("not ready when alerts are never delivered", func(t *testing.T) {
+		store := &poker.StubPlayerStore{}
+		game := poker.NewTexasHoldem(&poker.SpyBlindAlerter{}, store)
+		server := mustMakePlayerServer(t, store, game)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		response := readyz(ctx, server)
+
+		assertStatus(t, response, http.StatusServiceUnavailable)
+
+		report := decodeHealthReport(t, response)
+		if report.Status != poker.StatusFailing || report.Checks["blind alerter"].Status != poker.StatusFailing {
+			t.Errorf("expected the blind alerter check to fail, got %+v", report)
+		}
+		if report.Checks["store"].Status != poker.StatusOK {
+			t.Errorf("expected the store check to pass, got %+v", report.Checks["store"])
+		}
+	})
+
+	t.Run("games without a blind alerter only check the store", func(t *testing.T) {
+		server := mustMakePlayerServer(t, &poker.StubPlayerStore{}, dummyGame)
+
+		response := readyz(context.Background(), server)
+
+		assertStatus(t, response, http.StatusOK)
+		assertHealthReport(t, response, poker.HealthReport{
+			Status: poker.StatusOK,
+			Checks: map[string]poker.CheckResult{"store": {Status: poker.StatusOK}},
+		})
+	})
+
+	t.Run("extra checks are run too", func(t *testing.T) {
+		server, err := poker.NewPlayerServer(&poker.StubPlayerStore{}, dummyGame,
+			poker.WithReadinessCheck("users", func(context.Context) error {
+				return errors.New("users file is missing")
+			}),
+		)
+		assertNoError(t, err)
+
+		response := readyz(context.Background(), server)
+
+		assertStatus(t, response, http.StatusServiceUnavailable)
+		assertHealthReport(t, response, poker.HealthReport{
+			Status: poker.StatusFailing,
+			Checks: map[string]poker.CheckResult{
+				"store": {Status: poker.StatusOK},
+				"users": {Status: poker.StatusFailing, Error: "users file is missing"},
+			},
+		})
+	})
+}
+
+func readyz(ctx context.Context, server http.Handler) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx))
+	return response
+}
+
+func decodeHealthReport(t testing.TB, response *httptest.ResponseRecorder) poker.HealthReport {
+	t.Helper()
+
+	var report poker.HealthReport
+	if err := json.NewDecoder(response.Body).Decode(&report); err != nil {
+		t.Fatalf("could not decode health report %q, %v", response.Body, err)
+	}
+	return report
+}
+
+func assertHealthReport(t testing.TB, response *httptest.ResponseRecorder, want poker.HealthReport) {
+	t.Helper()
+
+	if got := decodeHealthReport(t, response); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package poker
+
+import (
+	"sort"
+	"sync"
+)
+
+// InMemoryPlayerStore collects data about players in memory.
+type InMemoryPlayerStore struct {
+	store map[string]int
+	// A mutex is used to synchronize read/write access to the map
+	lock sync.RWMutex
+}
+
+// NewInMemoryPlayerStore initialises an empty player store.
+func NewInMemoryPlayerStore() *InMemoryPlayerStore {
+	return &InMemoryPlayerStore{
+		store: map[string]int{},
+	}
+}
+
+// RecordWin will record a player's win.
+func (i *InMemoryPlayerStore) RecordWin(name string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.store[name]++
+}
+
+// AddWins records wins for a player in one go.
+func (i *InMemoryPlayerStore) AddWins(name string, wins int) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.store[name] += wins
+}
+
+// GetPlayerScore retrieves scores for a given player.
+func (i *InMemoryPlayerStore) GetPlayerScore(name string) int {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.store[name]
+}
+
+// GetLeague returns a collection of Players, sorted by wins.
+func (i *InMemoryPlayerStore) GetLeague() League {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	var league League
+	for name, wins := range i.store {
+		league = append(league, Player{name, wins})
+	}
+	sort.Slice(league, func(a, b int) bool {
+		return league[a].Wins > league[b].Wins
+	})
+	return league
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // League stores a collection of players.
@@ -19,10 +20,13 @@ func (l League) Find(name string) *Player {
 	return nil
 }
 
-// NewLeague creates a League from JSON.
+// NewLeague creates a League from JSON. Fields a Player doesn't have are an error, as they
+// are more likely to be a typo than something to ignore.
 func NewLeague(rdr io.Reader) (League, error) {
 	var league []Player
-	err := json.NewDecoder(rdr).Decode(&league)
+	decoder := json.NewDecoder(rdr)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&league)
 
 	if err != nil {
 		err = fmt.Errorf("problem parsing League, %v", err)
@@ -30,3 +34,54 @@ func NewLeague(rdr io.Reader) (League, error) {
 
 	return league, err
 }
+
+// Validate checks every player has a name and no negative wins, returning a
+// *LeagueValidationError describing all of the players which don't.
+func (l League) Validate() error {
+	var invalid []InvalidPlayer
+
+	for i, p := range l {
+		var problems []string
+
+		if p.Name == "" {
+			problems = append(problems, "name must not be empty")
+		}
+
+		if p.Wins < 0 {
+			problems = append(problems, fmt.Sprintf("wins must not be negative, got %d", p.Wins))
+		}
+
+		if problems != nil {
+			invalid = append(invalid, InvalidPlayer{Index: i, Player: p, Problems: problems})
+		}
+	}
+
+	if invalid != nil {
+		return &LeagueValidationError{Invalid: invalid}
+	}
+
+	return nil
+}
+
+// InvalidPlayer is a player in a League which failed validation, and why.
+type InvalidPlayer struct {
+	Index    int
+	Player   Player
+	Problems []string
+}
+
+// LeagueValidationError lists every invalid player in a League, so they can all be fixed at once.
+type LeagueValidationError struct {
+	Invalid []InvalidPlayer
+}
+
+func (e *LeagueValidationError) Error() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "league has %d invalid players", len(e.Invalid))
+
+	for _, p := range e.Invalid {
+		fmt.Fprintf(&msg, "\n  player %d (%q): %s", p.Index, p.Player.Name, strings.Join(p.Problems, ", "))
+	}
+
+	return msg.String()
+}
@@ -0,0 +1,36 @@
+package poker_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func FuzzLeagueRoundTrip(f *testing.F) {
+	f.Add([]byte(`[{"Name": "Cleo", "Wins": 10}, {"Name": "Chris", "Wins": 33}]`))
+	f.Add([]byte(`[]`))
+	f.Add([]byte(`null`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		league, err := poker.NewLeague(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := (poker.JSONCodec{}).Encode(&buf, league); err != nil {
+			t.Fatalf("could not encode %v, %v", league, err)
+		}
+
+		got, err := poker.JSONCodec{}.Decode(&buf)
+		if err != nil {
+			t.Fatalf("could not decode %q, %v", buf.String(), err)
+		}
+
+		if !reflect.DeepEqual(got, league) {
+			t.Errorf("round trip changed the league, got %v want %v", got, league)
+		}
+	})
+}
@@ -0,0 +1,297 @@
+package poker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+)
+
+// Language is a language the CLI and server can talk to players in, as an ISO 639-1 code.
+type Language string
+
+// The languages there are translations for.
+const (
+	English Language = "en"
+	Spanish Language = "es"
+	French  Language = "fr"
+)
+
+// Languages are all the languages there are translations for, English first.
+var Languages = []Language{English, Spanish, French}
+
+// catalog translates the English text of every message we show players. Anything missing is
+// shown in English. Formats keep their verbs in the same order, so arguments can be passed as-is.
+var catalog = map[Language]map[string]string{
+	Spanish: {
+		PlayerPrompt:         "Por favor, introduce el número de jugadores: ",
+		BadPlayerInputErrMsg: "Valor incorrecto para el número de jugadores, inténtalo de nuevo con un número",
+		BadWinnerInputMsg:    "entrada de ganador no válida, se espera el formato 'NombreDelJugador wins'",
+
+		"Seat %d: %s":          "Asiento %d: %s",
+		"Seat %d: %s (dealer)": "Asiento %d: %s (repartidor)",
+		"%s: %d chips":         "%s: %d fichas",
+
+		notFoundFormat: "no se encontró %s %q",
+		conflictFormat: "%s %q entra en conflicto con uno existente",
+		invalidFormat:  "valor no válido para %s: %s",
+
+		"player":           "jugador",
+		"game":             "partida",
+		"game ID":          "ID de partida",
+		"stats for player": "estadísticas del jugador",
+		"user":             "usuario",
+		"name":             "nombre",
+		"password":         "contraseña",
+		"players":          "jugadores",
+		"winner":           "ganador",
+		"chips":            "fichas",
+		"buy-in":           "compra inicial",
+		"rebuy":            "recompra",
+
+		"must not be empty":                  "no puede estar vacío",
+		"a name is required to record a win": "hace falta un nombre para registrar una victoria",
+		"names cannot contain /":             "los nombres no pueden contener /",
+		"must be at least %d characters":     "debe tener al menos %d caracteres",
+		"must be at most %d characters":      "debe tener como mucho %d caracteres",
+		"must be valid UTF-8":                "debe ser UTF-8 válido",
+		"cannot contain control characters":  "no puede contener caracteres de control",
+		"cannot contain line breaks":         "no puede contener saltos de línea",
+		"is reserved":                        "está reservado",
+		"need at least %d":                   "hacen falta al menos %d",
+		"no more than %d fit at a table":     "no caben más de %d en una mesa",
+		"incorrect name or password":         "nombre o contraseña incorrectos",
+		"you must be logged in":              "tienes que iniciar sesión",
+		ownWinsOnlyMsg:                       "solo puedes registrar tus propias victorias",
+		gameTakenMsg:                         "esta partida pertenece a otro jugador",
+		"it is not their turn":               "no es su turno",
+		"has not started":                    "no ha empezado",
+		"must be a whole number":             "debe ser un número entero",
+		"must be more than 0":                "debe ser mayor que 0",
+		atMostFormat:                         "debe ser como mucho %d",
+		"must not be negative":               "no puede ser negativo",
+		"the rebuy period is over":           "el periodo de recompra ha terminado",
+
+		pageNotFoundMsg:     "página no encontrada",
+		methodNotAllowedMsg: "método no permitido",
+	},
+	French: {
+		PlayerPrompt:         "Veuillez saisir le nombre de joueurs : ",
+		BadPlayerInputErrMsg: "Valeur incorrecte pour le nombre de joueurs, veuillez réessayer avec un nombre",
+		BadWinnerInputMsg:    "saisie du gagnant invalide, format attendu 'NomDuJoueur wins'",
+
+		"Seat %d: %s":          "Siège %d : %s",
+		"Seat %d: %s (dealer)": "Siège %d : %s (donneur)",
+		"%s: %d chips":         "%s : %d jetons",
+
+		notFoundFormat: "%s %q introuvable",
+		conflictFormat: "%s %q est en conflit avec un existant",
+		invalidFormat:  "%s invalide : %s",
+
+		"player":           "joueur",
+		"game":             "partie",
+		"game ID":          "ID de partie",
+		"stats for player": "statistiques du joueur",
+		"user":             "utilisateur",
+		"name":             "nom",
+		"password":         "mot de passe",
+		"players":          "joueurs",
+		"winner":           "gagnant",
+		"chips":            "jetons",
+		"buy-in":           "cave",
+		"rebuy":            "recave",
+
+		"must not be empty":                  "ne doit pas être vide",
+		"a name is required to record a win": "un nom est nécessaire pour enregistrer une victoire",
+		"names cannot contain /":             "les noms ne peuvent pas contenir /",
+		"must be at least %d characters":     "doit contenir au moins %d caractères",
+		"must be at most %d characters":      "doit contenir au plus %d caractères",
+		"must be valid UTF-8":                "doit être en UTF-8 valide",
+		"cannot contain control characters":  "ne doit pas contenir de caractères de contrôle",
+		"cannot contain line breaks":         "ne doit pas contenir de retours à la ligne",
+		"is reserved":                        "est réservé",
+		"need at least %d":                   "il en faut au moins %d",
+		"no more than %d fit at a table":     "pas plus de %d par table",
+		"incorrect name or password":         "nom ou mot de passe incorrect",
+		"you must be logged in":              "vous devez être connecté",
+		ownWinsOnlyMsg:                       "vous ne pouvez enregistrer que vos propres victoires",
+		gameTakenMsg:                         "cette partie appartient à un autre joueur",
+		"it is not their turn":               "ce n'est pas son tour",
+		"has not started":                    "n'a pas commencé",
+		"must be a whole number":             "doit être un nombre entier",
+		"must be more than 0":                "doit être supérieur à 0",
+		atMostFormat:                         "doit être au plus %d",
+		"must not be negative":               "ne doit pas être négatif",
+		"the rebuy period is over":           "la période de recave est terminée",
+
+		pageNotFoundMsg:     "page introuvable",
+		methodNotAllowedMsg: "méthode non autorisée",
+	},
+}
+
+// messages for requests the server has no handler for
+const (
+	pageNotFoundMsg     = "page not found"
+	methodNotAllowedMsg = "method not allowed"
+)
+
+// messages for logged in players trying to do something only someone else can
+const (
+	ownWinsOnlyMsg = "you can only record your own wins"
+	gameTakenMsg   = "this game belongs to another player"
+)
+
+// atMostFormat is the reason a number is too big.
+const atMostFormat = "must be at most %d"
+
+// the English formats of apperrors' messages, as keys into the catalog
+const (
+	notFoundFormat = "%s %q not found"
+	conflictFormat = "%s %q conflicts with an existing one"
+	invalidFormat  = "invalid %s: %s"
+)
+
+// Translate returns the message, given in English, in lang.
+func Translate(lang Language, message string) string {
+	if translated, ok := catalog[lang][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// ParseLanguage finds the Language of a tag such as "fr", "fr-CA" or the "fr_FR.UTF-8" of a LANG
+// environment variable. It reports false if there's no translation for it.
+func ParseLanguage(tag string) (Language, bool) {
+	tag, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(tag)), ".")
+	base, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+
+	lang := Language(base)
+	return lang, slices.Contains(Languages, lang)
+}
+
+// negotiateLanguage picks the language the client most prefers out of an Accept-Language header
+// like "fr-CA, fr;q=0.9, en;q=0.8", or fallback if we speak none of them.
+func negotiateLanguage(acceptLanguage string, fallback Language) Language {
+	best, bestQuality := fallback, 0.0
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		if lang, ok := ParseLanguage(tag); ok && quality > bestQuality {
+			best, bestQuality = lang, quality
+		}
+	}
+
+	return best
+}
+
+// WithDefaultLanguage sets the language the server talks to clients in when their Accept-Language
+// header doesn't ask for one it speaks. It is English otherwise.
+func WithDefaultLanguage(lang Language) PlayerServerOption {
+	return func(p *PlayerServer) {
+		p.language = lang
+	}
+}
+
+// WithLanguage makes the CLI talk to players in lang.
+func WithLanguage(lang Language) CLIOption {
+	return func(cli *CLI) {
+		cli.language = lang
+	}
+}
+
+type languageKey struct{}
+
+// localised works out the language each request should be answered in, for writeError.
+func localised(fallback Language, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := negotiateLanguage(r.Header.Get("Accept-Language"), fallback)
+		w.Header().Set("Content-Language", string(lang))
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), languageKey{}, lang)))
+	})
+}
+
+func languageOf(ctx context.Context) Language {
+	if lang, ok := ctx.Value(languageKey{}).(Language); ok {
+		return lang
+	}
+	return English
+}
+
+// writeError responds with err's status and an apperrors.Response as JSON, explaining it in the
+// request's language.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, response := apperrors.ToResponse(err)
+	response.Message = translateError(languageOf(r.Context()), err)
+	writeErrorResponse(w, status, response)
+}
+
+// writeMessage responds with status and an apperrors.Response as JSON, with message translated
+// into the request's language.
+func writeMessage(w http.ResponseWriter, r *http.Request, message string, status int) {
+	writeErrorResponse(w, status, apperrors.Response{
+		Code:    apperrors.Code(status),
+		Message: Translate(languageOf(r.Context()), message),
+	})
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, response apperrors.Response) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("content-type", jsonContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// translateError translates the application's kinds of error. Anything else is the server's
+// fault, and isn't something we can say in other languages, so it is shown as it is.
+func translateError(lang Language, err error) string {
+	if lang == English {
+		return err.Error()
+	}
+
+	tr := func(message string) string { return Translate(lang, message) }
+
+	var notFound apperrors.NotFoundError
+	var conflict apperrors.ConflictError
+	var invalid apperrors.InvalidError
+
+	switch {
+	case errors.As(err, &notFound):
+		return fmt.Sprintf(tr(notFoundFormat), tr(notFound.Resource), notFound.ID)
+	case errors.As(err, &conflict):
+		return fmt.Sprintf(tr(conflictFormat), tr(conflict.Resource), conflict.ID)
+	case errors.As(err, &invalid):
+		return fmt.Sprintf(tr(invalidFormat), tr(invalid.Field), translateReason(lang, invalid.Reason))
+	}
+	return tr(err.Error())
+}
+
+// translateReason translates reasons with numbers in them, like "must be at least 8 characters",
+// by matching them against the catalog's formats.
+func translateReason(lang Language, reason string) string {
+	for english, translated := range catalog[lang] {
+		if !strings.Contains(english, "%d") {
+			continue
+		}
+		var n int
+		if _, err := fmt.Sscanf(reason, english, &n); err == nil && fmt.Sprintf(english, n) == reason {
+			return fmt.Sprintf(translated, n)
+		}
+	}
+	return Translate(lang, reason)
+}
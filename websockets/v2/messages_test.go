@@ -0,0 +1,194 @@
+package poker_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestCLIInOtherLanguages(t *testing.T) {
+	cases := []struct {
+		lang                          poker.Language
+		prompt, badPlayers, badWinner string
+	}{
+		{
+			lang:       poker.English,
+			prompt:     poker.PlayerPrompt,
+			badPlayers: poker.BadPlayerInputErrMsg,
+			badWinner:  poker.BadWinnerInputMsg,
+		},
+		{
+			lang:       poker.Spanish,
+			prompt:     "Por favor, introduce el número de jugadores: ",
+			badPlayers: "Valor incorrecto para el número de jugadores, inténtalo de nuevo con un número",
+			badWinner:  "entrada de ganador no válida, se espera el formato 'NombreDelJugador wins'",
+		},
+		{
+			lang:       poker.French,
+			prompt:     "Veuillez saisir le nombre de joueurs : ",
+			badPlayers: "Valeur incorrecte pour le nombre de joueurs, veuillez réessayer avec un nombre",
+			badWinner:  "saisie du gagnant invalide, format attendu 'NomDuJoueur wins'",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.lang), func(t *testing.T) {
+			t.Run("prompts for the number of players", func(t *testing.T) {
+				out := &bytes.Buffer{}
+
+				poker.NewCLI(userSends("3", "Chris wins"), out, &poker.GameSpy{}, poker.WithLanguage(c.lang)).PlayPoker()
+
+				assertMessagesSentToUser(t, out, c.prompt)
+			})
+
+			t.Run("explains a bad number of players", func(t *testing.T) {
+				out := &bytes.Buffer{}
+
+				poker.NewCLI(userSends("pies"), out, &poker.GameSpy{}, poker.WithLanguage(c.lang)).PlayPoker()
+
+				assertMessagesSentToUser(t, out, c.prompt, c.badPlayers+"\n", c.prompt)
+			})
+
+			t.Run("explains a badly declared winner", func(t *testing.T) {
+				out := &bytes.Buffer{}
+
+				poker.NewCLI(userSends("8", "Lloyd is a killer"), out, &poker.GameSpy{}, poker.WithLanguage(c.lang)).PlayPoker()
+
+				assertMessagesSentToUser(t, out, c.prompt, c.badWinner+"\n")
+			})
+		})
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	cases := []struct {
+		tag  string
+		want poker.Language
+		ok   bool
+	}{
+		{"en", poker.English, true},
+		{"es", poker.Spanish, true},
+		{"fr-CA", poker.French, true},
+		{"FR", poker.French, true},
+		{"es_ES.UTF-8", poker.Spanish, true},
+		{"de-DE", "de", false},
+		{"", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.tag, func(t *testing.T) {
+			got, ok := poker.ParseLanguage(c.tag)
+			if got != c.want || ok != c.ok {
+				t.Errorf("got %q, %v want %q, %v", got, ok, c.want, c.ok)
+			}
+		})
+	}
+}
+
+func TestServerErrorsInOtherLanguages(t *testing.T) {
+	cases := []struct {
+		name           string
+		acceptLanguage string
+		options        []poker.PlayerServerOption
+		wantLanguage   string
+		wantMessage    string
+	}{
+		{
+			name:         "English without an Accept-Language",
+			wantLanguage: "en",
+			wantMessage:  "player \"Apollo\" not found",
+		},
+		{
+			name:           "Spanish",
+			acceptLanguage: "es-ES,es;q=0.9",
+			wantLanguage:   "es",
+			wantMessage:    "no se encontró jugador \"Apollo\"",
+		},
+		{
+			name:           "French",
+			acceptLanguage: "fr-CA",
+			wantLanguage:   "fr",
+			wantMessage:    "joueur \"Apollo\" introuvable",
+		},
+		{
+			name:           "the language the client prefers most",
+			acceptLanguage: "es;q=0.5, fr;q=0.8, de",
+			wantLanguage:   "fr",
+			wantMessage:    "joueur \"Apollo\" introuvable",
+		},
+		{
+			name:           "the server's default when we don't speak the client's language",
+			acceptLanguage: "de",
+			options:        []poker.PlayerServerOption{poker.WithDefaultLanguage(poker.Spanish)},
+			wantLanguage:   "es",
+			wantMessage:    "no se encontró jugador \"Apollo\"",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server, err := poker.NewPlayerServer(&poker.StubPlayerStore{}, dummyGame, c.options...)
+			if err != nil {
+				t.Fatal("problem creating player server", err)
+			}
+
+			request := newGetScoreRequest("Apollo")
+			request.Header.Set("Accept-Language", c.acceptLanguage)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, http.StatusNotFound)
+			assertErrorMessage(t, response, c.wantMessage)
+			if got := response.Header().Get("Content-Language"); got != c.wantLanguage {
+				t.Errorf("got Content-Language %q want %q", got, c.wantLanguage)
+			}
+		})
+	}
+
+	t.Run("reasons for invalid input are translated", func(t *testing.T) {
+		server := mustMakePlayerServer(t, &poker.StubPlayerStore{}, dummyGame)
+
+		request := newPostWinRequest("")
+		request.Header.Set("Accept-Language", "fr")
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusBadRequest)
+		assertErrorMessage(t, response, "joueur invalide : un nom est nécessaire pour enregistrer une victoire")
+	})
+
+	t.Run("reasons a name can't be used are translated", func(t *testing.T) {
+		server := mustMakePlayerServer(t, &poker.StubPlayerStore{}, dummyGame)
+
+		request := newPostWinRequest(strings.Repeat("a", 33))
+		request.Header.Set("Accept-Language", "es")
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusBadRequest)
+		assertErrorMessage(t, response, "valor no válido para jugador: debe tener como mucho 32 caracteres")
+	})
+}
+
+func TestAccountErrorsInOtherLanguages(t *testing.T) {
+	server, err := poker.NewPlayerServer(&poker.StubPlayerStore{}, dummyGame, poker.WithAccounts(poker.NewInMemoryUserStore(), poker.WithPasswordHashing(fastHashing)))
+	if err != nil {
+		t.Fatal("problem creating player server", err)
+	}
+
+	request := newFormRequest("/register", "Ruth", "short")
+	request.Header.Set("Accept-Language", "es")
+	response := httptest.NewRecorder()
+
+	server.ServeHTTP(response, request)
+
+	assertStatus(t, response, http.StatusBadRequest)
+	assertErrorMessage(t, response, "valor no válido para contraseña: debe tener al menos 8 caracteres")
+}
@@ -0,0 +1,74 @@
+package poker
+
+import "fmt"
+
+// Migration is what MigrateStore copied.
+type Migration struct {
+	Players int
+	Wins    int
+	// History is how many of the wins were copied in the order they were played.
+	History int
+}
+
+// MigrateStore copies every player's wins from one PlayerStore to another, adding them to any
+// wins already in to. It only uses the PlayerStore interface, so it works between any two stores,
+// but wins go to a WinAdder a player at a time rather than a win at a time. Players without any
+// wins are only copied to a WinAdder.
+//
+// When from is also a WinHistory its winners are replayed oldest first, so a store keeping a
+// history, such as a HistoryPlayerStore, gets the same one. Any wins in the league which aren't in
+// the history are copied after them. Once everything is copied, and flushed if to has a Flush
+// method, each player's wins in to are checked.
+func MigrateStore(from, to PlayerStore) (Migration, error) {
+	league := from.GetLeague()
+
+	before := map[string]int{}
+	remaining := map[string]int{}
+	for _, player := range league {
+		before[player.Name] = to.GetPlayerScore(player.Name)
+		remaining[player.Name] = player.Wins
+	}
+
+	migration := Migration{Players: len(league)}
+	record := func(name string) {
+		to.RecordWin(name)
+		remaining[name]--
+		migration.Wins++
+	}
+
+	if history, ok := from.(WinHistory); ok {
+		for _, winner := range history.Winners() {
+			if remaining[winner] > 0 {
+				record(winner)
+				migration.History++
+			}
+		}
+	}
+
+	adder, canAdd := to.(WinAdder)
+	for _, player := range league {
+		if canAdd {
+			adder.AddWins(player.Name, remaining[player.Name])
+			migration.Wins += remaining[player.Name]
+			remaining[player.Name] = 0
+		}
+		for remaining[player.Name] > 0 {
+			record(player.Name)
+		}
+	}
+
+	if flusher, ok := to.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return migration, fmt.Errorf("problem saving the migrated wins, %w", err)
+		}
+	}
+
+	for _, player := range league {
+		want := before[player.Name] + player.Wins
+		if got := to.GetPlayerScore(player.Name); got != want {
+			return migration, fmt.Errorf("migration check failed, %s has %d wins in the new store, want %d", player.Name, got, want)
+		}
+	}
+
+	return migration, nil
+}
@@ -0,0 +1,109 @@
+package poker_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestMigrateStore(t *testing.T) {
+	t.Run("copies a file store into a memory store", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[
+			{"Name": "Cleo", "Wins": 10},
+			{"Name": "Chris", "Wins": 33}]`)
+		defer cleanDatabase()
+
+		from, err := poker.NewFileSystemPlayerStore(database)
+		assertNoError(t, err)
+		to := poker.NewInMemoryPlayerStore()
+
+		migration, err := poker.MigrateStore(from, to)
+		assertNoError(t, err)
+
+		assertMigration(t, migration, poker.Migration{Players: 2, Wins: 43})
+		assertLeague(t, to.GetLeague(), from.GetLeague())
+	})
+
+	t.Run("copies a memory store into a file store, saving batched wins", func(t *testing.T) {
+		from := poker.NewInMemoryPlayerStore()
+		recordWins(from, "Chris", 3)
+		recordWins(from, "Cleo", 1)
+
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+		to, err := poker.NewFileSystemPlayerStore(database, poker.WithBatching(100, make(chan time.Time)))
+		assertNoError(t, err)
+
+		migration, err := poker.MigrateStore(from, to)
+		assertNoError(t, err)
+		assertMigration(t, migration, poker.Migration{Players: 2, Wins: 4})
+
+		reopened, err := poker.NewFileSystemPlayerStore(database)
+		assertNoError(t, err)
+		assertLeague(t, reopened.GetLeague(), []poker.Player{{"Chris", 3}, {"Cleo", 1}})
+	})
+
+	t.Run("adds to the wins already in the new store", func(t *testing.T) {
+		from := poker.NewInMemoryPlayerStore()
+		recordWins(from, "Chris", 2)
+		to := poker.NewInMemoryPlayerStore()
+		recordWins(to, "Chris", 5)
+
+		_, err := poker.MigrateStore(from, to)
+		assertNoError(t, err)
+
+		assertScoreEquals(t, to.GetPlayerScore("Chris"), 7)
+	})
+
+	t.Run("replays the history in order, then any wins it doesn't have", func(t *testing.T) {
+		fromLog, err := poker.NewWinLog(bytes.NewBufferString("Cleo\nChris\nCleo\n"))
+		assertNoError(t, err)
+		league := poker.NewInMemoryPlayerStore()
+		recordWins(league, "Chris", 2)
+		recordWins(league, "Cleo", 2)
+		from := poker.NewHistoryPlayerStore(league, fromLog)
+
+		toLog, err := poker.NewWinLog(&bytes.Buffer{})
+		assertNoError(t, err)
+		to := poker.NewHistoryPlayerStore(poker.NewInMemoryPlayerStore(), toLog)
+
+		migration, err := poker.MigrateStore(from, to)
+		assertNoError(t, err)
+
+		assertMigration(t, migration, poker.Migration{Players: 2, Wins: 4, History: 3})
+		assertWinners(t, to.Winners(), []string{"Cleo", "Chris", "Cleo", "Chris"})
+	})
+
+	t.Run("fails when the new store doesn't keep the wins", func(t *testing.T) {
+		from := poker.NewInMemoryPlayerStore()
+		recordWins(from, "Chris", 1)
+
+		_, err := poker.MigrateStore(from, &forgetfulPlayerStore{})
+
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+	})
+}
+
+// forgetfulPlayerStore is a PlayerStore which loses every win recorded in it.
+type forgetfulPlayerStore struct {
+	poker.StubPlayerStore
+}
+
+func (f *forgetfulPlayerStore) RecordWin(name string) {}
+
+func recordWins(store poker.PlayerStore, name string, wins int) {
+	for range wins {
+		store.RecordWin(name)
+	}
+}
+
+func assertMigration(t testing.TB, got, want poker.Migration) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got migration %+v want %+v", got, want)
+	}
+}
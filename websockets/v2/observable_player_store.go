@@ -0,0 +1,67 @@
+package poker
+
+import "sync"
+
+// LeagueNotifier is implemented by stores which can tell subscribers when the league changes.
+type LeagueNotifier interface {
+	Subscribe(onChange func(League)) (unsubscribe func())
+}
+
+// ObservablePlayerStore wraps a PlayerStore, notifying subscribers of the new League every time a win is recorded.
+type ObservablePlayerStore struct {
+	PlayerStore
+
+	lock        sync.Mutex
+	notifying   sync.Mutex
+	nextID      int
+	subscribers map[int]func(League)
+}
+
+// NewObservablePlayerStore creates an ObservablePlayerStore around store.
+func NewObservablePlayerStore(store PlayerStore) *ObservablePlayerStore {
+	return &ObservablePlayerStore{
+		PlayerStore: store,
+		subscribers: map[int]func(League){},
+	}
+}
+
+// Subscribe registers onChange to be called with the League after every win. Subscribers are
+// called one at a time, so they should hand the League off rather than doing slow work.
+func (o *ObservablePlayerStore) Subscribe(onChange func(League)) (unsubscribe func()) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	id := o.nextID
+	o.nextID++
+	o.subscribers[id] = onChange
+
+	return func() {
+		o.lock.Lock()
+		defer o.lock.Unlock()
+		delete(o.subscribers, id)
+	}
+}
+
+// RecordWin records the win in the wrapped store and then notifies subscribers.
+func (o *ObservablePlayerStore) RecordWin(name string) {
+	o.notifying.Lock()
+	defer o.notifying.Unlock()
+
+	o.PlayerStore.RecordWin(name)
+	league := append(League(nil), o.PlayerStore.GetLeague()...)
+
+	for _, onChange := range o.currentSubscribers() {
+		onChange(league)
+	}
+}
+
+func (o *ObservablePlayerStore) currentSubscribers() []func(League) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	subscribers := make([]func(League), 0, len(o.subscribers))
+	for _, s := range o.subscribers {
+		subscribers = append(subscribers, s)
+	}
+	return subscribers
+}
@@ -0,0 +1,48 @@
+package poker_test
+
+import (
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestObservablePlayerStore(t *testing.T) {
+	t.Run("subscribers are told the new league when a win is recorded", func(t *testing.T) {
+		store := poker.NewObservablePlayerStore(poker.NewInMemoryPlayerStore())
+
+		var got poker.League
+		store.Subscribe(func(league poker.League) {
+			got = league
+		})
+
+		store.RecordWin("Pepper")
+
+		assertLeague(t, got, []poker.Player{{Name: "Pepper", Wins: 1}})
+	})
+
+	t.Run("unsubscribed functions are not called", func(t *testing.T) {
+		store := poker.NewObservablePlayerStore(poker.NewInMemoryPlayerStore())
+
+		calls := 0
+		unsubscribe := store.Subscribe(func(poker.League) {
+			calls++
+		})
+
+		store.RecordWin("Pepper")
+		unsubscribe()
+		store.RecordWin("Pepper")
+
+		if calls != 1 {
+			t.Errorf("got %d calls, want 1", calls)
+		}
+	})
+
+	t.Run("it still records wins in the wrapped store", func(t *testing.T) {
+		wrapped := &poker.StubPlayerStore{}
+		store := poker.NewObservablePlayerStore(wrapped)
+
+		store.RecordWin("Pepper")
+
+		poker.AssertPlayerWin(t, wrapped, "Pepper")
+	})
+}
@@ -4,36 +4,99 @@ import (
 	"github.com/gorilla/websocket"
 	"log"
 	"net/http"
+	"sync"
 )
 
+// playerServerWS is a websocket connection which is safe to write to from blind alerts firing
+// while the handler is also writing.
 type playerServerWS struct {
 	*websocket.Conn
+	encoding  Encoding
+	writeLock sync.Mutex
 }
 
+// Write sends p, a blind alert, in the connection's Encoding.
 func (w *playerServerWS) Write(p []byte) (n int, err error) {
-	err = w.WriteMessage(websocket.TextMessage, p)
-
+	msg, err := w.encoding.EncodeAlert(string(p))
 	if err != nil {
 		return 0, err
 	}
 
+	if err := w.writeMessage(msg); err != nil {
+		return 0, err
+	}
+
 	return len(p), nil
 }
 
-func newPlayerServerWS(w http.ResponseWriter, r *http.Request) *playerServerWS {
-	conn, err := wsUpgrader.Upgrade(w, r, nil)
+func (w *playerServerWS) writeGameState(state GameState) error {
+	msg, err := w.encoding.EncodeGameState(state)
+	if err != nil {
+		return err
+	}
+	return w.writeMessage(msg)
+}
+
+func (w *playerServerWS) writePresence(presence Presence) error {
+	msg, err := w.encoding.EncodePresence(presence)
+	if err != nil {
+		return err
+	}
+	return w.writeMessage(msg)
+}
+
+func (w *playerServerWS) writeSeating(seating Seating) error {
+	msg, err := w.encoding.EncodeSeating(seating)
+	if err != nil {
+		return err
+	}
+	return w.writeMessage(msg)
+}
+
+func (w *playerServerWS) writeMessage(msg []byte) error {
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+	return w.WriteMessage(w.encoding.MessageType(), msg)
+}
+
+func (w *playerServerWS) WriteJSON(v interface{}) error {
+	w.writeLock.Lock()
+	defer w.writeLock.Unlock()
+	return w.Conn.WriteJSON(v)
+}
+
+func newPlayerServerWS(w http.ResponseWriter, r *http.Request) (*playerServerWS, error) {
+	return upgradeWS(wsUpgrader, w, r)
+}
+
+// newGameWS upgrades a connection for playing a game, using the Encoding for the subprotocol
+// the client asked for.
+func newGameWS(w http.ResponseWriter, r *http.Request) (*playerServerWS, error) {
+	return upgradeWS(gameWSUpgrader, w, r)
+}
+
+// upgradeWS upgrades the connection to a websocket. If it can't, the client has already been
+// told why, so the caller only needs to stop.
+func upgradeWS(upgrader websocket.Upgrader, w http.ResponseWriter, r *http.Request) (*playerServerWS, error) {
+	conn, err := upgrader.Upgrade(w, r, nil)
 
 	if err != nil {
 		log.Printf("problem upgrading connection to websockets %v\n", err)
+		return nil, err
 	}
 
-	return &playerServerWS{conn}
+	return &playerServerWS{Conn: conn, encoding: encodingFor(conn.Subprotocol())}, nil
 }
 
 func (w *playerServerWS) WaitForMsg() string {
-	_, msg, err := w.ReadMessage()
+	msg, err := w.readMsg()
 	if err != nil {
 		log.Printf("error reading from websocket %v\n", err)
 	}
-	return string(msg)
+	return msg
+}
+
+func (w *playerServerWS) readMsg() (string, error) {
+	_, msg, err := w.ReadMessage()
+	return string(msg), err
 }
@@ -0,0 +1,32 @@
+package poker_test
+
+import (
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/playerstoretest"
+)
+
+func TestStubPlayerStoreContract(t *testing.T) {
+	playerstoretest.Test(t, func(t *testing.T) poker.PlayerStore {
+		return &poker.StubPlayerStore{}
+	})
+}
+
+func TestInMemoryPlayerStore(t *testing.T) {
+	playerstoretest.Test(t, func(t *testing.T) poker.PlayerStore {
+		return poker.NewInMemoryPlayerStore()
+	})
+}
+
+func TestFileSystemPlayerStoreContract(t *testing.T) {
+	playerstoretest.Test(t, func(t *testing.T) poker.PlayerStore {
+		database, cleanDatabase := createTempFile(t, "")
+		t.Cleanup(cleanDatabase)
+
+		store, err := poker.NewFileSystemPlayerStore(database)
+		assertNoError(t, err)
+
+		return store
+	})
+}
@@ -0,0 +1,97 @@
+// Package playerstoretest holds the behaviour every PlayerStore is expected to have, so that
+// fakes like StubPlayerStore and real implementations can be checked against the same contract.
+package playerstoretest
+
+import (
+	"reflect"
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+// Factory creates a new, empty PlayerStore. Any cleanup should be registered with t.Cleanup.
+type Factory func(t *testing.T) poker.PlayerStore
+
+// Test runs the PlayerStore contract against stores created by newStore.
+func Test(t *testing.T, newStore Factory) {
+	t.Run("unknown players have a score of zero", func(t *testing.T) {
+		store := newStore(t)
+
+		assertScore(t, store, "Apollo", 0)
+	})
+
+	t.Run("recording a win increments a player's score", func(t *testing.T) {
+		store := newStore(t)
+
+		for want := 1; want <= 3; want++ {
+			store.RecordWin("Pepper")
+			assertScore(t, store, "Pepper", want)
+		}
+	})
+
+	t.Run("recording a win does not affect other players", func(t *testing.T) {
+		store := newStore(t)
+
+		store.RecordWin("Pepper")
+
+		assertScore(t, store, "Floyd", 0)
+	})
+
+	t.Run("an empty store has an empty league", func(t *testing.T) {
+		store := newStore(t)
+
+		if league := store.GetLeague(); len(league) != 0 {
+			t.Errorf("expected an empty league but got %v", league)
+		}
+	})
+
+	t.Run("league contains every player sorted by wins", func(t *testing.T) {
+		store := newStore(t)
+
+		store.RecordWin("Cleo")
+		store.RecordWin("Chris")
+		store.RecordWin("Chris")
+		store.RecordWin("Chris")
+		store.RecordWin("Tiest")
+		store.RecordWin("Tiest")
+
+		got := store.GetLeague()
+		want := poker.League{
+			{Name: "Chris", Wins: 3},
+			{Name: "Tiest", Wins: 2},
+			{Name: "Cleo", Wins: 1},
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got league %v want %v", got, want)
+		}
+	})
+
+	t.Run("a WinAdder adds many wins at once, even none", func(t *testing.T) {
+		store, ok := newStore(t).(poker.WinAdder)
+		if !ok {
+			t.Skip("the store can only record one win at a time")
+		}
+
+		store.RecordWin("Chris")
+		store.AddWins("Chris", 1_000_000_000)
+		store.AddWins("Cleo", 0)
+
+		got := store.GetLeague()
+		want := poker.League{
+			{Name: "Chris", Wins: 1_000_000_001},
+			{Name: "Cleo", Wins: 0},
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got league %v want %v", got, want)
+		}
+	})
+}
+
+func assertScore(t testing.TB, store poker.PlayerStore, name string, want int) {
+	t.Helper()
+	if got := store.GetPlayerScore(name); got != want {
+		t.Errorf("got score %d for %q want %d", got, name, want)
+	}
+}
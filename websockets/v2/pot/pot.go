@@ -0,0 +1,100 @@
+// Package pot works out who can win which chips at the end of a hand. When players go all-in for
+// different amounts the chips are split into a main pot, which everyone still in the hand can win,
+// and side pots which only the players who covered the bigger bets can win.
+package pot
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrNobodyLeft means there are chips in the pot but every player who put some in has folded.
+var ErrNobodyLeft = errors.New("everyone who bet has folded, so nobody can win the pot")
+
+// Contribution is everything a player put in the pot during a hand. Players who folded still
+// leave their chips behind, but can't win any of them.
+type Contribution struct {
+	Player string
+	Amount int
+	Folded bool
+}
+
+// Pot is a pile of chips and the players who can win it, in the order they were given to Calculate.
+type Pot struct {
+	Amount   int
+	Eligible []string
+}
+
+// Calculate splits the contributions into the main pot followed by any side pots. Each pot is
+// capped at the bet of its smallest all-in, so a player can only win from each opponent as much
+// as they put in themselves. An uncalled bet ends up in a last pot with only its bettor eligible,
+// which is their change.
+func Calculate(contributions []Contribution) ([]Pot, error) {
+	var levels []int
+	chips := 0
+	for _, c := range contributions {
+		if c.Amount < 0 {
+			return nil, fmt.Errorf("%s put in %d chips, contributions can't be negative", c.Player, c.Amount)
+		}
+		if !c.Folded && c.Amount > 0 {
+			levels = append(levels, c.Amount)
+		}
+		chips += c.Amount
+	}
+	if chips > 0 && len(levels) == 0 {
+		return nil, ErrNobodyLeft
+	}
+	slices.Sort(levels)
+	levels = slices.Compact(levels)
+
+	var pots []Pot
+	previous := 0
+	for _, level := range levels {
+		p := Pot{}
+		for _, c := range contributions {
+			p.Amount += min(c.Amount, level) - min(c.Amount, previous)
+			if !c.Folded && c.Amount >= level {
+				p.Eligible = append(p.Eligible, c.Player)
+			}
+		}
+		pots = append(pots, p)
+		previous = level
+	}
+
+	// chips folded players put in above the biggest live bet can still be won by whoever won the last pot
+	for _, c := range contributions {
+		if over := c.Amount - previous; over > 0 && len(pots) > 0 {
+			pots[len(pots)-1].Amount += over
+		}
+	}
+
+	return pots, nil
+}
+
+// Split shares amount between winners, who tie for the pot. Chips that don't divide evenly go one
+// each to the first winners, so pass them in seat order starting left of the button.
+func Split(amount int, winners []string) map[string]int {
+	shares := make(map[string]int, len(winners))
+	if len(winners) == 0 {
+		return shares
+	}
+
+	share, odd := amount/len(winners), amount%len(winners)
+	for i, winner := range winners {
+		shares[winner] += share
+		if i < odd {
+			shares[winner]++
+		}
+	}
+	return shares
+}
+
+// Total is how many chips are in pots.
+func Total(pots []Pot) int {
+	total := 0
+	for _, p := range pots {
+		total += p.Amount
+	}
+	return total
+}
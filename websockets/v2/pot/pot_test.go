@@ -0,0 +1,161 @@
+package pot_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/quii/learn-go-with-tests/websockets/v2/pot"
+)
+
+func TestCalculate(t *testing.T) {
+	cases := []struct {
+		name          string
+		contributions []pot.Contribution
+		want          []pot.Pot
+	}{
+		{
+			name:          "nobody bet",
+			contributions: []pot.Contribution{{Player: "Chris"}, {Player: "Cleo"}},
+			want:          nil,
+		},
+		{
+			name:          "everyone called",
+			contributions: []pot.Contribution{{"Chris", 100, false}, {"Cleo", 100, false}, {"Ruth", 100, false}},
+			want:          []pot.Pot{{Amount: 300, Eligible: []string{"Chris", "Cleo", "Ruth"}}},
+		},
+		{
+			name:          "one short all-in makes a side pot",
+			contributions: []pot.Contribution{{"Chris", 50, false}, {"Cleo", 100, false}, {"Ruth", 100, false}},
+			want: []pot.Pot{
+				{Amount: 150, Eligible: []string{"Chris", "Cleo", "Ruth"}},
+				{Amount: 100, Eligible: []string{"Cleo", "Ruth"}},
+			},
+		},
+		{
+			name:          "several all-ins of different sizes",
+			contributions: []pot.Contribution{{"Chris", 25, false}, {"Cleo", 300, false}, {"Ruth", 75, false}, {"Adil", 300, false}},
+			want: []pot.Pot{
+				{Amount: 100, Eligible: []string{"Chris", "Cleo", "Ruth", "Adil"}},
+				{Amount: 150, Eligible: []string{"Cleo", "Ruth", "Adil"}},
+				{Amount: 450, Eligible: []string{"Cleo", "Adil"}},
+			},
+		},
+		{
+			name:          "all-ins for the same amount share a level",
+			contributions: []pot.Contribution{{"Chris", 50, false}, {"Cleo", 50, false}, {"Ruth", 200, false}, {"Adil", 200, false}},
+			want: []pot.Pot{
+				{Amount: 200, Eligible: []string{"Chris", "Cleo", "Ruth", "Adil"}},
+				{Amount: 300, Eligible: []string{"Ruth", "Adil"}},
+			},
+		},
+		{
+			name:          "an uncalled bet is returned to the bettor",
+			contributions: []pot.Contribution{{"Chris", 40, false}, {"Cleo", 100, false}},
+			want: []pot.Pot{
+				{Amount: 80, Eligible: []string{"Chris", "Cleo"}},
+				{Amount: 60, Eligible: []string{"Cleo"}},
+			},
+		},
+		{
+			name:          "folded players' chips stay in but they can't win",
+			contributions: []pot.Contribution{{"Chris", 20, true}, {"Cleo", 100, false}, {"Ruth", 100, false}},
+			want:          []pot.Pot{{Amount: 220, Eligible: []string{"Cleo", "Ruth"}}},
+		},
+		{
+			name:          "a folded player's chips are spread across the pots they reached",
+			contributions: []pot.Contribution{{"Chris", 50, false}, {"Cleo", 80, true}, {"Ruth", 200, false}, {"Adil", 200, false}},
+			want: []pot.Pot{
+				{Amount: 200, Eligible: []string{"Chris", "Ruth", "Adil"}},
+				{Amount: 330, Eligible: []string{"Ruth", "Adil"}},
+			},
+		},
+		{
+			name:          "a folded player who bet more than anyone still in adds to the last pot",
+			contributions: []pot.Contribution{{"Chris", 30, false}, {"Cleo", 100, true}, {"Ruth", 60, false}},
+			want: []pot.Pot{
+				{Amount: 90, Eligible: []string{"Chris", "Ruth"}},
+				{Amount: 100, Eligible: []string{"Ruth"}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := pot.Calculate(c.contributions)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %+v want %+v", got, c.want)
+			}
+		})
+	}
+
+	t.Run("contributions can't be negative", func(t *testing.T) {
+		_, err := pot.Calculate([]pot.Contribution{{"Chris", -10, false}, {"Cleo", 10, false}})
+
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+
+	t.Run("someone has to be left to win the chips", func(t *testing.T) {
+		_, err := pot.Calculate([]pot.Contribution{{"Chris", 10, true}, {"Cleo", 10, true}})
+
+		if !errors.Is(err, pot.ErrNobodyLeft) {
+			t.Errorf("got error %v want %v", err, pot.ErrNobodyLeft)
+		}
+	})
+}
+
+func TestCalculateKeepsEveryChip(t *testing.T) {
+	assertion := func(amounts []uint16, folded []bool) bool {
+		var contributions []pot.Contribution
+		total, live := 0, false
+		for i, amount := range amounts {
+			c := pot.Contribution{Player: string(rune('A' + i%26)), Amount: int(amount), Folded: i < len(folded) && folded[i]}
+			contributions = append(contributions, c)
+			total += c.Amount
+			live = live || (!c.Folded && c.Amount > 0)
+		}
+
+		pots, err := pot.Calculate(contributions)
+		if !live {
+			return total == 0 || errors.Is(err, pot.ErrNobodyLeft)
+		}
+		return err == nil && pot.Total(pots) == total
+	}
+
+	if err := quick.Check(assertion, nil); err != nil {
+		t.Error("failed checks", err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		name    string
+		amount  int
+		winners []string
+		want    map[string]int
+	}{
+		{"one winner takes it all", 300, []string{"Chris"}, map[string]int{"Chris": 300}},
+		{"an even split", 300, []string{"Chris", "Cleo"}, map[string]int{"Chris": 150, "Cleo": 150}},
+		{"the odd chip goes to the first winner", 301, []string{"Chris", "Cleo"}, map[string]int{"Chris": 151, "Cleo": 150}},
+		{"odd chips go one each in seat order", 302, []string{"Chris", "Cleo", "Ruth"}, map[string]int{"Chris": 101, "Cleo": 101, "Ruth": 100}},
+		{"fewer chips than winners", 2, []string{"Chris", "Cleo", "Ruth"}, map[string]int{"Chris": 1, "Cleo": 1, "Ruth": 0}},
+		{"nobody to pay", 100, nil, map[string]int{}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := pot.Split(c.amount, c.winners)
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %v want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Package render draws poker data for people reading a terminal.
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+// LeagueTable writes league as a table with a column each for rank, name and wins, most wins
+// first. Players with the same number of wins share a rank, and the next rank skips the places
+// they took, so two players tied for 2nd are followed by the player in 4th.
+func LeagueTable(w io.Writer, league poker.League) error {
+	players := make(poker.League, len(league))
+	copy(players, league)
+
+	sort.SliceStable(players, func(i, j int) bool {
+		if players[i].Wins != players[j].Wins {
+			return players[i].Wins > players[j].Wins
+		}
+		return players[i].Name < players[j].Name
+	})
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "RANK\tNAME\tWINS")
+
+	rank := 0
+	for i, p := range players {
+		if i == 0 || p.Wins != players[i-1].Wins {
+			rank = i + 1
+		}
+		fmt.Fprintf(table, "%d\t%s\t%d\n", rank, p.Name, p.Wins)
+	}
+
+	return table.Flush()
+}
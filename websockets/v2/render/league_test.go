@@ -0,0 +1,71 @@
+package render_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/render"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestLeagueTable(t *testing.T) {
+	cases := []struct {
+		name   string
+		league poker.League
+	}{
+		{"empty", poker.League{}},
+		{"sorted by wins", poker.League{{Name: "Cleo", Wins: 10}, {Name: "Chris", Wins: 33}, {Name: "Pepper", Wins: 1}}},
+		{"long names", poker.League{{Name: "Bartholomew Montgomery-Fitzwilliam III", Wins: 1200}, {Name: "Al", Wins: 7}, {Name: "Zoë", Wins: 3}}},
+		{"ties share a rank", poker.League{{Name: "Dan", Wins: 2}, {Name: "Cleo", Wins: 5}, {Name: "Bob", Wins: 5}, {Name: "Ann", Wins: 9}, {Name: "Eve", Wins: 2}}},
+	}
+
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if err := render.LeagueTable(&buf, test.league); err != nil {
+				t.Fatal(err)
+			}
+
+			assertGolden(t, buf.Bytes())
+		})
+	}
+
+	t.Run("doesn't reorder the league it was given", func(t *testing.T) {
+		league := poker.League{{Name: "Cleo", Wins: 1}, {Name: "Chris", Wins: 2}}
+
+		render.LeagueTable(&bytes.Buffer{}, league)
+
+		if league[0].Name != "Cleo" {
+			t.Errorf("league was reordered to %v", league)
+		}
+	})
+}
+
+func assertGolden(t *testing.T, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", filepath.Base(t.Name())+".golden.txt")
+
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("could not read golden file, run the tests with -update to create it, %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run the tests with -update if the change is intended\ngot:\n%s", golden, got)
+	}
+}
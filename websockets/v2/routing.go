@@ -0,0 +1,142 @@
+package poker
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Middleware wraps a handler to add behaviour to it, such as compressing or caching its responses.
+type Middleware func(http.Handler) http.Handler
+
+// WithRoute wraps the route at path in middlewares. Paths are as the unversioned API has them, like
+// "/league", and the middlewares apply to every version of the route. They wrap one another in
+// order, the first outermost, and wrap whatever the route already does, like compressing the
+// league. They only see requests for methods the route supports. Calling WithRoute again for the
+// same path adds more middlewares inside those already given.
+//
+// NewPlayerServer fails if there is no route at path.
+func WithRoute(path string, middlewares ...Middleware) PlayerServerOption {
+	return func(p *PlayerServer) {
+		if p.routeMiddlewares == nil {
+			p.routeMiddlewares = map[string][]Middleware{}
+		}
+		p.routeMiddlewares[path] = append(p.routeMiddlewares[path], middlewares...)
+	}
+}
+
+// chain wraps next in middlewares, the first outermost.
+func chain(next http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+	return next
+}
+
+// route pairs a path with the handler serving it and the methods it supports.
+type route struct {
+	path    string
+	methods []string
+	handler http.Handler
+}
+
+// serve answers OPTIONS and HEAD for the route and rejects methods it doesn't support, so that
+// handlers only ever see the methods they were written for.
+func (r route) serve() http.Handler {
+	allow := r.allow()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodOptions:
+			w.Header().Set("Allow", allow)
+			w.WriteHeader(http.StatusNoContent)
+		case req.Method == http.MethodHead && r.supports(http.MethodGet):
+			get := req.Clone(req.Context())
+			get.Method = http.MethodGet
+			r.handler.ServeHTTP(headResponseWriter{w}, get)
+		case r.supports(req.Method):
+			r.handler.ServeHTTP(w, req)
+		default:
+			w.Header().Set("Allow", allow)
+			writeMessage(w, req, methodNotAllowedMsg, http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func (r route) supports(method string) bool {
+	for _, m := range r.methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (r route) allow() string {
+	allowed := append([]string(nil), r.methods...)
+	if r.supports(http.MethodGet) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	allowed = append(allowed, http.MethodOptions)
+	return strings.Join(allowed, ", ")
+}
+
+// headResponseWriter sends the headers a GET would but none of its body.
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// routingTable is a set of routes for one version of the API. Handlers are written
+// without knowledge of the version prefix, so the same table can be mounted more than once.
+type routingTable []route
+
+// wrapRoutes wraps each route in tables in the middlewares given for its path. It is an error
+// for there to be middlewares for a path that none of the tables have a route for.
+func wrapRoutes(middlewares map[string][]Middleware, tables ...routingTable) error {
+	unused := maps.Clone(middlewares)
+
+	for _, rt := range tables {
+		for i, r := range rt {
+			if m, ok := middlewares[r.path]; ok {
+				rt[i].handler = chain(r.handler, m...)
+				delete(unused, r.path)
+			}
+		}
+	}
+
+	if len(unused) > 0 {
+		return fmt.Errorf("problem adding middleware, there is no route for %s", strings.Join(slices.Sorted(maps.Keys(unused)), ", "))
+	}
+	return nil
+}
+
+// mount serves every route under prefix, e.g. /league becomes /v1/league.
+func (rt routingTable) mount(mux *http.ServeMux, prefix string) {
+	for _, r := range rt {
+		mux.Handle(prefix+r.path, http.StripPrefix(prefix, r.serve()))
+	}
+}
+
+// mountDeprecated serves every route at its unversioned path, telling clients that it will
+// go away at sunset and where the successor lives.
+func (rt routingTable) mountDeprecated(mux *http.ServeMux, successorPrefix string, sunset time.Time) {
+	for _, r := range rt {
+		mux.Handle(r.path, deprecated(successorPrefix, sunset, r.serve()))
+	}
+}
+
+func deprecated(successorPrefix string, sunset time.Time, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", sunset.Format(http.TimeFormat))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, r.URL.Path))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -7,8 +7,13 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/quii/learn-go-with-tests/http-server/idempotency"
+	"github.com/quii/learn-go-with-tests/http-server/playername"
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+	"github.com/quii/learn-go-with-tests/websockets/v2/stats"
 )
 
 // PlayerStore stores score information about players.
@@ -18,6 +23,14 @@ type PlayerStore interface {
 	GetLeague() League
 }
 
+// WinAdder is a PlayerStore which can record many wins for a player at once, rather than
+// calling RecordWin for each of them.
+type WinAdder interface {
+	PlayerStore
+	// AddWins adds wins to name's wins. Adding none still makes them a player in the league.
+	AddWins(name string, wins int)
+}
+
 // Player stores a name with a number of wins.
 type Player struct {
 	Name string
@@ -26,18 +39,54 @@ type Player struct {
 
 // PlayerServer is a HTTP interface for player information.
 type PlayerServer struct {
-	store PlayerStore
+	store    PlayerStore
+	notifier LeagueNotifier
 	http.Handler
-	template *template.Template
-	game     Game
+	template    *template.Template
+	game        Game
+	accounts    *accounts
+	history     WinHistory
+	gameHistory GameHistory
+	games       *gameSessions
+	idempotency *idempotency.Guard
+	language    Language
+	actionTimer *ActionTimer
+
+	resumeWindow time.Duration
+	clock        Clock
+
+	readinessChecks  map[string]HealthCheck
+	routeMiddlewares map[string][]Middleware
 }
 
 const jsonContentType = "application/json"
 const htmlTemplatePath = "game.html"
 
-// NewPlayerServer creates a PlayerServer with routing configured.
-func NewPlayerServer(store PlayerStore, game Game) (*PlayerServer, error) {
-	p := new(PlayerServer)
+// legacyAPISunset is when the unversioned API routes, such as /league, will be removed.
+var legacyAPISunset = time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// PlayerServerOption configures optional behaviour of a PlayerServer.
+type PlayerServerOption func(*PlayerServer)
+
+// WithIdempotencyKeys lets clients retry recording a win by sending an Idempotency-Key header.
+// The response to the first request with a key is kept in store for ttl and replayed to any
+// retries, so the win is only recorded once. Only POSTs to /players/ are guarded. Keys are kept
+// apart by the caller's session cookie, so a player can't replay somebody else's response.
+func WithIdempotencyKeys(store idempotency.KeyStore, ttl time.Duration) PlayerServerOption {
+	return func(p *PlayerServer) {
+		p.idempotency = idempotency.NewGuard(store, ttl, idempotency.WithCaller(sessionToken))
+	}
+}
+
+// NewPlayerServer creates a PlayerServer with routing configured. If store is not already a
+// LeagueNotifier it is wrapped in an ObservablePlayerStore so that /league/live works; share
+// the same ObservablePlayerStore with your Game so wins it records are pushed too.
+func NewPlayerServer(store PlayerStore, game Game, options ...PlayerServerOption) (*PlayerServer, error) {
+	p := &PlayerServer{language: English, resumeWindow: defaultResumeWindow, clock: realClock{}}
+
+	for _, option := range options {
+		option(p)
+	}
 
 	tmpl, err := template.ParseFiles(htmlTemplatePath)
 
@@ -46,38 +95,409 @@ func NewPlayerServer(store PlayerStore, game Game) (*PlayerServer, error) {
 	}
 
 	p.game = game
+	p.games = newGameSessions(p.newGame, p.actionTimer, p.resumeWindow, p.clock)
 	p.template = tmpl
+	notifier, ok := store.(LeagueNotifier)
+	if !ok {
+		observable := NewObservablePlayerStore(store)
+		store, notifier = observable, observable
+	}
 	p.store = store
+	p.notifier = notifier
+
+	v1, app, health := p.apiV1Routes(), p.appRoutes(), p.healthRoutes()
+	var accountRoutes routingTable
+	if p.accounts != nil {
+		accountRoutes = p.accounts.routes()
+	}
+	if err := wrapRoutes(p.routeMiddlewares, v1, app, health, accountRoutes); err != nil {
+		return nil, err
+	}
 
 	router := http.NewServeMux()
-	router.Handle("/league", http.HandlerFunc(p.leagueHandler))
-	router.Handle("/players/", http.HandlerFunc(p.playersHandler))
-	router.Handle("/game", http.HandlerFunc(p.playGame))
-	router.Handle("/ws", http.HandlerFunc(p.webSocket))
+
+	v1.mount(router, "/v1")
+	v1.mountDeprecated(router, "/v1", legacyAPISunset)
+
+	app.mount(router, "")
+	health.mount(router, "")
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeMessage(w, r, pageNotFoundMsg, http.StatusNotFound)
+	})
 
 	p.Handler = router
 
+	if p.accounts != nil {
+		login, err := template.ParseFiles(loginTemplatePath)
+
+		if err != nil {
+			return nil, fmt.Errorf("problem opening %s %v", loginTemplatePath, err)
+		}
+
+		p.accounts.login = login
+		accountRoutes.mount(router, "")
+		p.Handler = p.accounts.identify(router)
+	}
+
+	p.Handler = localised(p.language, p.Handler)
+
 	return p, nil
 }
 
+func (p *PlayerServer) apiV1Routes() routingTable {
+	return routingTable{
+		{"/league", []string{http.MethodGet}, chain(http.HandlerFunc(p.leagueHandler), gzipped)},
+		{"/league.csv", []string{http.MethodGet}, chain(http.HandlerFunc(p.leagueCSVHandler), gzipped)},
+		{"/players/", []string{http.MethodGet, http.MethodPost}, chain(http.HandlerFunc(p.playersHandler), p.idempotent)},
+	}
+}
+
+// idempotent replays responses to retried POSTs if the server was given idempotency keys.
+func (p *PlayerServer) idempotent(next http.Handler) http.Handler {
+	if p.idempotency == nil {
+		return next
+	}
+	return p.idempotency.Middleware(next)
+}
+
+// appRoutes are the unversioned routes used by the game's web page.
+func (p *PlayerServer) appRoutes() routingTable {
+	return routingTable{
+		{"/game", []string{http.MethodGet}, http.HandlerFunc(p.playGame)},
+		{"/game/", []string{http.MethodGet}, http.HandlerFunc(p.gameDetails)},
+		{"/ws", []string{http.MethodGet}, http.HandlerFunc(p.webSocket)},
+		{"/ws/", []string{http.MethodGet}, http.HandlerFunc(p.resumableGame)},
+		{"/league/live", []string{http.MethodGet}, http.HandlerFunc(p.liveLeague)},
+		{"/standings", []string{http.MethodGet}, http.HandlerFunc(p.standings)},
+	}
+}
+
 var wsUpgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Error:           upgradeError,
+}
+
+var gameWSUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	Subprotocols:    wsSubprotocols(),
+	Error:           upgradeError,
+}
+
+// upgradeError answers requests which can't be upgraded to a websocket like any other error.
+func upgradeError(w http.ResponseWriter, r *http.Request, status int, reason error) {
+	writeMessage(w, r, reason.Error(), status)
 }
 
 func (p *PlayerServer) webSocket(w http.ResponseWriter, r *http.Request) {
-	ws := newPlayerServerWS(w, r)
+	if !p.requireLogin(w, r) {
+		return
+	}
+
+	ws, err := newGameWS(w, r)
+	if err != nil {
+		return
+	}
 
 	numberOfPlayersMsg := ws.WaitForMsg()
 	numberOfPlayers, _ := strconv.Atoi(numberOfPlayersMsg)
 	p.game.Start(numberOfPlayers, ws)
 
-	winner := ws.WaitForMsg()
-	p.game.Finish(winner)
+	for {
+		msg, err := ws.readMsg()
+		if err != nil {
+			return
+		}
+
+		winner, err := winnerName(msg)
+		if err != nil {
+			ws.Write([]byte(translateError(languageOf(r.Context()), err)))
+			continue
+		}
+
+		p.game.Finish(winner)
+		return
+	}
+}
+
+// winnerName checks the name of a game's winner sent over a websocket, returning the name to
+// record their win under.
+func winnerName(msg string) (string, error) {
+	name, err := playername.Normalize(msg)
+	if err != nil {
+		return "", apperrors.Invalid("winner", err.Error(), nil)
+	}
+	return name, nil
+}
+
+// resumableGame plays the game with the ID in the path. If the player's connection drops
+// they can connect to the same path again to carry on, and are sent the game's GameState so
+// they don't have to wait for the next blind alert to know where they are. With accounts only
+// the player who started a game can reconnect to it. Connecting to /ws/{id}/spectate instead
+// watches the game.
+func (p *PlayerServer) resumableGame(w http.ResponseWriter, r *http.Request) {
+	if !p.requireLogin(w, r) {
+		return
+	}
+
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/ws/"), "/")
+	if id == "" {
+		writeError(w, r, apperrors.Invalid("game ID", "must not be empty", nil))
+		return
+	}
+
+	switch action {
+	case "":
+		p.playGameWS(w, r, id)
+	case "spectate":
+		p.spectateGameWS(w, r, id)
+	default:
+		writeMessage(w, r, pageNotFoundMsg, http.StatusNotFound)
+	}
+}
+
+func (p *PlayerServer) playGameWS(w http.ResponseWriter, r *http.Request, id string) {
+	owner, _ := loggedInPlayer(r)
+	session, err := p.games.join(id, owner)
+	if err != nil {
+		writeMessage(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	defer p.games.leave(id, session)
+
+	ws, err := newGameWS(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	resumed := session.connectPlayer(ws)
+	defer session.disconnectPlayer(ws)
+
+	if resumed {
+		if err := p.catchUp(ws, session); err != nil {
+			return
+		}
+		session.announce(RolePlayer)
+	} else {
+		session.announce(RolePlayer)
+		numberOfPlayers, table, err := p.askForPlayers(ws, r)
+		if err != nil {
+			return
+		}
+		session.game.Start(numberOfPlayers, session)
+		p.games.markStarted(id)
+		if table != nil {
+			session.seat(table)
+		}
+	}
+
+	for {
+		msg, err := ws.readMsg()
+		if err != nil {
+			// the connection dropped, so keep the game for when they reconnect
+			return
+		}
+
+		ok, err := session.play(msg)
+		if !ok {
+			ok, err = session.playStack(msg)
+		}
+		if ok {
+			if err != nil {
+				ws.Write([]byte(translateError(languageOf(r.Context()), err)))
+			}
+			continue
+		}
+
+		winner, err := winnerName(msg)
+		if err != nil {
+			ws.Write([]byte(translateError(languageOf(r.Context()), err)))
+			continue
+		}
+
+		session.game.Finish(winner)
+		p.games.end(id)
+		return
+	}
+}
+
+// askForPlayers reads the players starting a game, telling the player what's wrong with anything
+// that can't be read and waiting for them to try again, until they get it right or disconnect.
+func (p *PlayerServer) askForPlayers(ws *playerServerWS, r *http.Request) (numberOfPlayers int, table *Table, err error) {
+	for {
+		msg, err := ws.readMsg()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		numberOfPlayers, table, err := parsePlayers(msg)
+		if err == nil {
+			return numberOfPlayers, table, nil
+		}
+		ws.Write([]byte(translateError(languageOf(r.Context()), err)))
+	}
+}
+
+// catchUp sends someone joining a game which has already started its GameState and, if the
+// players are seated at a table, its Seating.
+func (p *PlayerServer) catchUp(ws *playerServerWS, session *gameSession) error {
+	if err := ws.writeGameState(gameStateOf(session.game)); err != nil {
+		return err
+	}
+	if seating, ok := session.seating(); ok {
+		return ws.writeSeating(seating)
+	}
+	return nil
+}
+
+// spectateGameWS sends someone watching a game its blind alerts and who else is watching.
+// Anything they send is ignored.
+func (p *PlayerServer) spectateGameWS(w http.ResponseWriter, r *http.Request, id string) {
+	session, ok := p.games.watch(id)
+	if !ok {
+		writeError(w, r, apperrors.NotFound("game", id, nil))
+		return
+	}
+	defer p.games.leave(id, session)
+
+	ws, err := newGameWS(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	started := session.addSpectator(ws)
+	defer session.removeSpectator(ws)
+
+	if started {
+		if err := p.catchUp(ws, session); err != nil {
+			return
+		}
+	}
+	session.announce(RoleSpectator)
+
+	for {
+		if _, err := ws.readMsg(); err != nil {
+			return
+		}
+	}
+}
+
+// gameDetails serves GET /game/{id}/presence and /game/{id}/standings, for each game played
+// over /ws/{id}.
+func (p *PlayerServer) gameDetails(w http.ResponseWriter, r *http.Request) {
+	id, detail, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/game/"), "/")
+	if detail != "presence" && detail != "standings" {
+		writeMessage(w, r, pageNotFoundMsg, http.StatusNotFound)
+		return
+	}
+
+	session, ok := p.games.find(id)
+	if !ok {
+		writeError(w, r, apperrors.NotFound("game", id, nil))
+		return
+	}
+
+	w.Header().Set("content-type", jsonContentType)
+	if detail == "presence" {
+		json.NewEncoder(w).Encode(session.presence(id))
+		return
+	}
+	json.NewEncoder(w).Encode(standingsOf(session.game))
+}
+
+// newGame makes the Game played over /ws/{id}. It is the server's own Game unless that can make
+// a new one for each.
+func (p *PlayerServer) newGame() Game {
+	if maker, ok := p.game.(GameMaker); ok {
+		return maker.NewGame()
+	}
+	return p.game
+}
+
+func gameStateOf(game Game) GameState {
+	if game, ok := game.(interface{ GameState() GameState }); ok {
+		return game.GameState()
+	}
+	return GameState{}
+}
+
+// standings serves the stacks of the players in the game played over /ws, if the Game keeps
+// track of them, biggest first. Games played over /ws/{id} have their own at
+// /game/{id}/standings.
+func (p *PlayerServer) standings(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", jsonContentType)
+	json.NewEncoder(w).Encode(standingsOf(p.game))
+}
+
+// standingsOf returns game's standings, or none if it doesn't keep track of stacks.
+func standingsOf(game Game) []Stack {
+	var standings []Stack
+	if keeper, ok := game.(StackKeeper); ok {
+		standings = keeper.Standings()
+	}
+	if standings == nil {
+		standings = []Stack{}
+	}
+	return standings
+}
+
+func (p *PlayerServer) liveLeague(w http.ResponseWriter, r *http.Request) {
+	ws, err := newPlayerServerWS(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	updates := make(chan League, 1)
+	unsubscribe := p.notifier.Subscribe(func(league League) {
+		// only the latest league matters, so replace one the client hasn't been sent yet
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- league
+	})
+	defer unsubscribe()
+
+	clientGone := make(chan struct{})
+	go func() {
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				close(clientGone)
+				return
+			}
+		}
+	}()
+
+	league := p.store.GetLeague()
+	for {
+		if err := ws.WriteJSON(league); err != nil {
+			return
+		}
+
+		select {
+		case league = <-updates:
+		case <-clientGone:
+			return
+		}
+	}
+}
+
+// gamePage is what the game's web page is rendered with.
+type gamePage struct {
+	Player string
 }
 
 func (p *PlayerServer) playGame(w http.ResponseWriter, r *http.Request) {
-	p.template.Execute(w, nil)
+	if !p.requireLogin(w, r) {
+		return
+	}
+
+	player, _ := loggedInPlayer(r)
+	p.template.Execute(w, gamePage{Player: player})
 }
 
 func (p *PlayerServer) leagueHandler(w http.ResponseWriter, r *http.Request) {
@@ -85,28 +505,94 @@ func (p *PlayerServer) leagueHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(p.store.GetLeague())
 }
 
+func (p *PlayerServer) leagueCSVHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "text/csv")
+	w.Header().Set("content-disposition", `attachment; filename="league.csv"`)
+	CSVCodec{}.Encode(w, p.store.GetLeague())
+}
+
 func (p *PlayerServer) playersHandler(w http.ResponseWriter, r *http.Request) {
 	player := strings.TrimPrefix(r.URL.Path, "/players/")
 
+	if name, isStats := strings.CutSuffix(player, "/stats"); isStats && r.Method == http.MethodGet {
+		p.showStats(w, r, name)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPost:
-		p.processWin(w, player)
+		if p.requireLogin(w, r) {
+			p.processWin(w, r, player)
+		}
 	case http.MethodGet:
-		p.showScore(w, player)
+		p.showScore(w, r, player)
 	}
 }
 
-func (p *PlayerServer) showScore(w http.ResponseWriter, player string) {
+func (p *PlayerServer) showScore(w http.ResponseWriter, r *http.Request, player string) {
+	// look up the name the player's wins would have been recorded under
+	if name, err := playername.Normalize(player); err == nil {
+		player = name
+	}
+
 	score := p.store.GetPlayerScore(player)
 
 	if score == 0 {
-		w.WriteHeader(http.StatusNotFound)
+		writeError(w, r, apperrors.NotFound("player", player, nil))
+		return
 	}
 
 	fmt.Fprint(w, score)
 }
 
-func (p *PlayerServer) processWin(w http.ResponseWriter, player string) {
-	p.store.RecordWin(player)
+func (p *PlayerServer) processWin(w http.ResponseWriter, r *http.Request, player string) {
+	if player == "" {
+		writeError(w, r, apperrors.Invalid("player", "a name is required to record a win", nil))
+		return
+	}
+
+	name, err := playername.Normalize(player)
+	if err != nil {
+		writeError(w, r, apperrors.Invalid("player", err.Error(), nil))
+		return
+	}
+
+	// with accounts, players can only record wins for themselves
+	if owner, ok := loggedInPlayer(r); ok {
+		if normalized, err := playername.Normalize(owner); err == nil {
+			owner = normalized
+		}
+		if owner != name {
+			writeMessage(w, r, ownWinsOnlyMsg, http.StatusForbidden)
+			return
+		}
+	}
+
+	p.store.RecordWin(name)
 	w.WriteHeader(http.StatusAccepted)
 }
+
+func (p *PlayerServer) showStats(w http.ResponseWriter, r *http.Request, player string) {
+	if p.history == nil {
+		writeError(w, r, apperrors.NotFound("stats for player", player, nil))
+		return
+	}
+
+	name, err := playername.Normalize(player)
+	if err != nil {
+		writeError(w, r, apperrors.Invalid("player", err.Error(), nil))
+		return
+	}
+
+	s := stats.For(name, p.history.Winners())
+	if p.gameHistory != nil {
+		var lengths []time.Duration
+		for _, game := range p.gameHistory.Games() {
+			lengths = append(lengths, game.Duration())
+		}
+		s.AverageGameLength = stats.AverageLength(lengths)
+	}
+
+	w.Header().Set("content-type", jsonContentType)
+	json.NewEncoder(w).Encode(s)
+}
@@ -1,25 +1,32 @@
 package poker_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/quii/learn-go-with-tests/concurrency/leaktest"
+	"github.com/quii/learn-go-with-tests/http-server/idempotency"
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
 	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"github.com/quii/learn-go-with-tests/websockets/v2/stats"
 )
 
 var (
-	dummyGame = &GameSpy{}
+	dummyGame = &poker.GameSpy{}
 	tenMS     = 10 * time.Millisecond
 )
 
-func mustMakePlayerServer(t *testing.T, store poker.PlayerStore, game poker.Game) *poker.PlayerServer {
+func mustMakePlayerServer(t testing.TB, store poker.PlayerStore, game poker.Game) *poker.PlayerServer {
 	server, err := poker.NewPlayerServer(store, game)
 	if err != nil {
 		t.Fatal("problem creating player server", err)
@@ -63,6 +70,11 @@ func TestGETPlayers(t *testing.T) {
 		server.ServeHTTP(response, request)
 
 		assertStatus(t, response, http.StatusNotFound)
+		assertErrorResponse(t, response, apperrors.Response{
+			Code:    "not_found",
+			Message: `player "Apollo" not found`,
+			Details: map[string]string{"resource": "player", "id": "Apollo"},
+		})
 	})
 }
 
@@ -83,6 +95,165 @@ func TestStoreWins(t *testing.T) {
 		assertStatus(t, response, http.StatusAccepted)
 		poker.AssertPlayerWin(t, &store, player)
 	})
+
+	t.Run("it rejects wins without a player name", func(t *testing.T) {
+		request := newPostWinRequest("")
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusBadRequest)
+		if len(store.WinCalls) != 1 {
+			t.Errorf("expected no more wins to be recorded, got %v", store.WinCalls)
+		}
+	})
+
+	t.Run("it records wins under the tidied up name", func(t *testing.T) {
+		store := poker.StubPlayerStore{}
+		server := mustMakePlayerServer(t, &store, dummyGame)
+
+		request := httptest.NewRequest(http.MethodPost, "/players/%C2%A0Chris%E2%80%83%20Smith%20", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusAccepted)
+		poker.AssertPlayerWin(t, &store, "Chris Smith")
+	})
+
+	rejected := []struct {
+		name   string
+		path   string
+		reason string
+	}{
+		{"a control character", "/players/Chris%09Smith", "cannot contain control characters"},
+		{"a name which is too long", "/players/" + strings.Repeat("a", 33), "must be at most 32 characters"},
+		{"a slash", "/players/Chris%2FSmith", "names cannot contain /"},
+		{"only spaces", "/players/%C2%A0%20", "must not be empty"},
+	}
+
+	for _, r := range rejected {
+		t.Run("it rejects wins for "+r.name, func(t *testing.T) {
+			store := poker.StubPlayerStore{}
+			server := mustMakePlayerServer(t, &store, dummyGame)
+
+			request := httptest.NewRequest(http.MethodPost, r.path, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, http.StatusBadRequest)
+			assertErrorMessage(t, response, "invalid player: "+r.reason)
+			if len(store.WinCalls) != 0 {
+				t.Errorf("expected no wins to be recorded, got %v", store.WinCalls)
+			}
+		})
+	}
+}
+
+func TestIdempotentWins(t *testing.T) {
+	newServer := func(t *testing.T) (*poker.PlayerServer, *poker.StubPlayerStore) {
+		store := &poker.StubPlayerStore{Scores: map[string]int{}}
+		server, err := poker.NewPlayerServer(store, dummyGame, poker.WithIdempotencyKeys(idempotency.NewMemoryStore(time.Now), time.Hour))
+		if err != nil {
+			t.Fatal("problem creating player server", err)
+		}
+		return server, store
+	}
+
+	postWin := func(server http.Handler, path, key string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(http.MethodPost, path, nil)
+		request.Header.Set(idempotency.Header, key)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		return response
+	}
+
+	t.Run("a retried win is only recorded once", func(t *testing.T) {
+		server, store := newServer(t)
+
+		first := postWin(server, "/v1/players/Pepper", "win-1")
+		retry := postWin(server, "/v1/players/Pepper", "win-1")
+
+		assertStatus(t, first, http.StatusAccepted)
+		assertStatus(t, retry, http.StatusAccepted)
+		poker.AssertPlayerWin(t, store, "Pepper")
+		if retry.Header().Get(idempotency.ReplayedHeader) != "true" {
+			t.Errorf("expected the retry to be a replay, got headers %v", retry.Header())
+		}
+	})
+
+	t.Run("each key records its own win", func(t *testing.T) {
+		server, store := newServer(t)
+
+		postWin(server, "/v1/players/Pepper", "win-1")
+		postWin(server, "/v1/players/Pepper", "win-2")
+
+		if len(store.WinCalls) != 2 {
+			t.Errorf("got %d wins recorded want 2", len(store.WinCalls))
+		}
+	})
+
+	t.Run("a key can't be reused to record a win for someone else", func(t *testing.T) {
+		server, store := newServer(t)
+
+		postWin(server, "/v1/players/Pepper", "win-1")
+		response := postWin(server, "/v1/players/Floyd", "win-1")
+
+		assertStatus(t, response, http.StatusUnprocessableEntity)
+		poker.AssertPlayerWin(t, store, "Pepper")
+	})
+}
+
+func TestIdempotentWinsWithAccounts(t *testing.T) {
+	users := poker.NewInMemoryUserStore()
+	for _, name := range []string{"Pepper", "Floyd"} {
+		if err := users.AddUser(mustMakeUser(t, name, "correct horse")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := &poker.StubPlayerStore{Scores: map[string]int{}}
+	server, err := poker.NewPlayerServer(store, dummyGame,
+		poker.WithAccounts(users, poker.WithPasswordHashing(fastHashing)),
+		poker.WithIdempotencyKeys(idempotency.NewMemoryStore(time.Now), time.Hour),
+	)
+	if err != nil {
+		t.Fatal("problem creating player server", err)
+	}
+
+	postWin := func(cookie *http.Cookie, key string) *httptest.ResponseRecorder {
+		request := newPostWinRequest("Pepper")
+		request.Header.Set(idempotency.Header, key)
+		if cookie != nil {
+			request.AddCookie(cookie)
+		}
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		return response
+	}
+
+	t.Run("a win refused before logging in can be retried once logged in", func(t *testing.T) {
+		refused := postWin(nil, "win-1")
+		assertStatus(t, refused, http.StatusUnauthorized)
+
+		retry := postWin(logIn(t, server, "Pepper", "correct horse"), "win-1")
+		assertStatus(t, retry, http.StatusAccepted)
+		poker.AssertPlayerWin(t, store, "Pepper")
+	})
+
+	t.Run("another player's key doesn't replay their response", func(t *testing.T) {
+		pepper := logIn(t, server, "Pepper", "correct horse")
+		floyd := logIn(t, server, "Floyd", "correct horse")
+
+		assertStatus(t, postWin(pepper, "win-2"), http.StatusAccepted)
+
+		response := postWin(floyd, "win-2")
+		assertStatus(t, response, http.StatusForbidden)
+		if response.Header().Get(idempotency.ReplayedHeader) != "" {
+			t.Errorf("expected Floyd's request to be handled, got headers %v", response.Header())
+		}
+	})
 }
 
 func TestLeague(t *testing.T) {
@@ -111,6 +282,356 @@ func TestLeague(t *testing.T) {
 	})
 }
 
+func TestLeagueCSV(t *testing.T) {
+	store := poker.StubPlayerStore{League: []poker.Player{{Name: "Cleo", Wins: 32}, {Name: "Chris", Wins: 20}}}
+	server := mustMakePlayerServer(t, &store, dummyGame)
+
+	request, _ := http.NewRequest(http.MethodGet, "/v1/league.csv", nil)
+	response := httptest.NewRecorder()
+
+	server.ServeHTTP(response, request)
+
+	assertStatus(t, response, http.StatusOK)
+	assertContentType(t, response, "text/csv")
+	assertResponseBody(t, response.Body.String(), "name,wins\nCleo,32\nChris,20\n")
+}
+
+func TestAPIVersions(t *testing.T) {
+	store := poker.StubPlayerStore{
+		Scores: map[string]int{"Pepper": 20},
+		League: []poker.Player{{Name: "Pepper", Wins: 20}},
+	}
+	server := mustMakePlayerServer(t, &store, dummyGame)
+
+	requests := []struct {
+		name string
+		path string
+	}{
+		{"player score", "/players/Pepper"},
+		{"league", "/league"},
+	}
+
+	for _, r := range requests {
+		t.Run(r.name+" is served under /v1 without deprecation headers", func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, "/v1"+r.path, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, http.StatusOK)
+			assertNoHeader(t, response, "Deprecation")
+			assertNoHeader(t, response, "Sunset")
+		})
+
+		t.Run(r.name+" is still served at its legacy path but marked deprecated", func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, r.path, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, http.StatusOK)
+			assertHeader(t, response, "Deprecation", "true")
+			assertHeader(t, response, "Link", fmt.Sprintf(`</v1%s>; rel="successor-version"`, r.path))
+
+			if _, err := http.ParseTime(response.Header().Get("Sunset")); err != nil {
+				t.Errorf("expected Sunset header to be a HTTP date, got %q", response.Header().Get("Sunset"))
+			}
+		})
+	}
+
+	t.Run("wins are recorded on POST under /v1", func(t *testing.T) {
+		store := poker.StubPlayerStore{}
+		server := mustMakePlayerServer(t, &store, dummyGame)
+
+		request, _ := http.NewRequest(http.MethodPost, "/v1/players/Pepper", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusAccepted)
+		poker.AssertPlayerWin(t, &store, "Pepper")
+	})
+}
+
+func TestMethods(t *testing.T) {
+	store := poker.StubPlayerStore{
+		Scores: map[string]int{"Pepper": 20},
+		League: []poker.Player{{Name: "Pepper", Wins: 20}},
+	}
+	server := mustMakePlayerServer(t, &store, dummyGame)
+
+	routes := []struct {
+		path  string
+		allow string
+	}{
+		{"/v1/league", "GET, HEAD, OPTIONS"},
+		{"/league", "GET, HEAD, OPTIONS"},
+		{"/v1/players/Pepper", "GET, POST, HEAD, OPTIONS"},
+		{"/players/Pepper", "GET, POST, HEAD, OPTIONS"},
+		{"/game", "GET, HEAD, OPTIONS"},
+		{"/ws", "GET, HEAD, OPTIONS"},
+		{"/league/live", "GET, HEAD, OPTIONS"},
+	}
+
+	for _, route := range routes {
+		t.Run("OPTIONS "+route.path+" lists the allowed methods", func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodOptions, route.path, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, http.StatusNoContent)
+			assertHeader(t, response, "Allow", route.allow)
+		})
+
+		t.Run("DELETE "+route.path+" is not allowed", func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodDelete, route.path, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, http.StatusMethodNotAllowed)
+			assertHeader(t, response, "Allow", route.allow)
+		})
+	}
+
+	heads := []struct {
+		path        string
+		status      int
+		contentType string
+	}{
+		{"/v1/league", http.StatusOK, "application/json"},
+		{"/v1/players/Pepper", http.StatusOK, ""},
+		{"/v1/players/Apollo", http.StatusNotFound, ""},
+	}
+
+	for _, head := range heads {
+		t.Run("HEAD "+head.path+" returns the GET headers without a body", func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodHead, head.path, nil)
+			response := httptest.NewRecorder()
+
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response, head.status)
+			if head.contentType != "" {
+				assertContentType(t, response, head.contentType)
+			}
+			if response.Body.Len() != 0 {
+				t.Errorf("expected no body but got %q", response.Body)
+			}
+		})
+	}
+
+	t.Run("HEAD does not record a win", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodHead, "/v1/players/Pepper", nil)
+		server.ServeHTTP(httptest.NewRecorder(), request)
+
+		if got := store.WinCallsFor("Pepper"); got != 0 {
+			t.Errorf("got %d wins for Pepper want 0", got)
+		}
+	})
+}
+
+func TestRouteMiddleware(t *testing.T) {
+	store := &poker.StubPlayerStore{
+		Scores: map[string]int{"Pepper": 20},
+		League: []poker.Player{{Name: "Pepper", Wins: 20}},
+	}
+
+	t.Run("middlewares wrap the route in order, the first outermost", func(t *testing.T) {
+		var calls []string
+		server := mustMakePlayerServerWith(t, store,
+			poker.WithRoute("/league", recordingMiddleware("first", &calls), recordingMiddleware("second", &calls)),
+			poker.WithRoute("/league", recordingMiddleware("third", &calls)),
+		)
+
+		server.ServeHTTP(httptest.NewRecorder(), newLeagueRequest())
+
+		want := []string{"first in", "second in", "third in", "third out", "second out", "first out"}
+		if !reflect.DeepEqual(calls, want) {
+			t.Errorf("got calls %v want %v", calls, want)
+		}
+	})
+
+	t.Run("middlewares apply to every version of the route and no other", func(t *testing.T) {
+		var calls []string
+		server := mustMakePlayerServerWith(t, store, poker.WithRoute("/league", recordingMiddleware("league", &calls)))
+
+		for _, path := range []string{"/league", "/v1/league", "/v1/players/Pepper", "/v1/league.csv"} {
+			request, _ := http.NewRequest(http.MethodGet, path, nil)
+			server.ServeHTTP(httptest.NewRecorder(), request)
+		}
+
+		want := []string{"league in", "league out", "league in", "league out"}
+		if !reflect.DeepEqual(calls, want) {
+			t.Errorf("got calls %v want %v", calls, want)
+		}
+	})
+
+	t.Run("middlewares wrap what the route already does", func(t *testing.T) {
+		identity := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.Header.Set("Accept-Encoding", "identity")
+				next.ServeHTTP(w, r)
+			})
+		}
+		server := mustMakePlayerServerWith(t, store, poker.WithRoute("/league", identity))
+
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newCompressedLeagueRequest("gzip"))
+
+		assertStatus(t, response, http.StatusOK)
+		assertNoHeader(t, response, "Content-Encoding")
+		assertLeague(t, getLeagueFromResponse(t, response.Body), store.League)
+	})
+
+	t.Run("middlewares only see methods the route supports", func(t *testing.T) {
+		var calls []string
+		server := mustMakePlayerServerWith(t, store, poker.WithRoute("/league", recordingMiddleware("league", &calls)))
+
+		for _, method := range []string{http.MethodOptions, http.MethodDelete} {
+			request, _ := http.NewRequest(method, "/league", nil)
+			server.ServeHTTP(httptest.NewRecorder(), request)
+		}
+
+		if len(calls) != 0 {
+			t.Errorf("got calls %v want none", calls)
+		}
+	})
+
+	t.Run("a route that doesn't exist is an error", func(t *testing.T) {
+		_, err := poker.NewPlayerServer(store, dummyGame, poker.WithRoute("/nope", recordingMiddleware("nope", new([]string))))
+
+		if err == nil || !strings.Contains(err.Error(), "/nope") {
+			t.Errorf("got error %v, want one about /nope", err)
+		}
+	})
+}
+
+// recordingMiddleware appends name to calls as a request goes in and the response comes out.
+func recordingMiddleware(name string, calls *[]string) poker.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name+" in")
+			next.ServeHTTP(w, r)
+			*calls = append(*calls, name+" out")
+		})
+	}
+}
+
+func mustMakePlayerServerWith(t testing.TB, store poker.PlayerStore, options ...poker.PlayerServerOption) *poker.PlayerServer {
+	t.Helper()
+	server, err := poker.NewPlayerServer(store, dummyGame, options...)
+	if err != nil {
+		t.Fatal("problem creating player server", err)
+	}
+	return server
+}
+
+func TestStats(t *testing.T) {
+	history, err := poker.NewWinLog(bytes.NewBufferString("Pepper\nPepper\nFloyd\nPepper\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := poker.NewPlayerServer(&poker.StubPlayerStore{}, dummyGame, poker.WithWinHistory(history))
+	if err != nil {
+		t.Fatal("problem creating player server", err)
+	}
+
+	t.Run("returns a player's stats as JSON", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/v1/players/Pepper/stats", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusOK)
+		assertContentType(t, response, "application/json")
+
+		var got stats.Stats
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode stats %q, %v", response.Body, err)
+		}
+
+		want := stats.Stats{Name: "Pepper", Games: 4, Wins: 3, WinRate: 0.75, CurrentStreak: 1, LongestStreak: 2}
+		if got != want {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("looks players up by the name their wins are recorded under", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/v1/players/%20Pepper%20/stats", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		var got stats.Stats
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode stats %q, %v", response.Body, err)
+		}
+		if got.Name != "Pepper" || got.Wins != 3 {
+			t.Errorf("got %+v want Pepper's stats", got)
+		}
+	})
+
+	t.Run("names which can't be recorded have no stats", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/v1/players/Pep%0Aper/stats", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+
+	t.Run("wins cannot be recorded for a name containing a slash", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodPost, "/v1/players/Pepper/stats", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusBadRequest)
+	})
+
+	t.Run("includes the average game length when games are recorded", func(t *testing.T) {
+		start := time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)
+		games, _ := poker.NewGameLog(&bytes.Buffer{})
+		assertNoError(t, games.RecordGame(poker.GameRecord{Winner: "Pepper", Players: 3, StartedAt: start, FinishedAt: start.Add(20 * time.Minute)}))
+		assertNoError(t, games.RecordGame(poker.GameRecord{Winner: "Floyd", Players: 4, StartedAt: start, FinishedAt: start.Add(40 * time.Minute)}))
+
+		server, err := poker.NewPlayerServer(&poker.StubPlayerStore{}, dummyGame, poker.WithWinHistory(history), poker.WithGameHistory(games))
+		if err != nil {
+			t.Fatal("problem creating player server", err)
+		}
+
+		request, _ := http.NewRequest(http.MethodGet, "/v1/players/Pepper/stats", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusOK)
+
+		var got stats.Stats
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("could not decode stats %q, %v", response.Body, err)
+		}
+
+		if got.AverageGameLength != 30*time.Minute {
+			t.Errorf("got average game length %v want 30m", got.AverageGameLength)
+		}
+	})
+
+	t.Run("stats are not found without a win history", func(t *testing.T) {
+		server := mustMakePlayerServer(t, &poker.StubPlayerStore{}, dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/v1/players/Pepper/stats", nil)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response, http.StatusNotFound)
+	})
+}
+
 func TestGame(t *testing.T) {
 	t.Run("GET /game returns 200", func(t *testing.T) {
 		server := mustMakePlayerServer(t, &poker.StubPlayerStore{}, dummyGame)
@@ -124,10 +645,13 @@ func TestGame(t *testing.T) {
 	})
 
 	t.Run("start a game with 3 players, send some blind alerts down WS and declare Ruth the winner", func(t *testing.T) {
+		leaktest.Check(t)
+
 		wantedBlindAlert := "Blind is 100"
 		winner := "Ruth"
 
-		game := &GameSpy{BlindAlert: []byte(wantedBlindAlert)}
+		game := &poker.GameSpy{}
+		game.BlindAlert = []byte(wantedBlindAlert)
 		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
 		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
 
@@ -141,6 +665,483 @@ func TestGame(t *testing.T) {
 		assertFinishCalledWith(t, game, winner)
 		within(t, tenMS, func() { assertWebsocketGotMsg(t, ws, wantedBlindAlert) })
 	})
+
+	t.Run("a winner who can't be recorded is turned away, in the client's language", func(t *testing.T) {
+		leaktest.Check(t)
+
+		game := &poker.GameSpy{}
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+		for _, path := range []string{"/ws", "/ws/table-9"} {
+			t.Run(path, func(t *testing.T) {
+				ws, _, err := websocket.DefaultDialer.Dial(wsURL+path, http.Header{"Accept-Language": {"fr"}})
+				assertNoError(t, err)
+				defer ws.Close()
+
+				writeWSMessage(t, ws, "3")
+				writeWSMessage(t, ws, "Ruth\nChris 1")
+
+				for {
+					got := readServerMessage(t, ws)
+					if got.Alert == "" {
+						continue // the GameSpy's blind alert, or someone joining
+					}
+					if want := "gagnant invalide : ne doit pas contenir de caractères de contrôle"; got.Alert != want {
+						t.Errorf("got alert %q want %q", got.Alert, want)
+					}
+					break
+				}
+
+				writeWSMessage(t, ws, "  Ruth ")
+				assertFinishCalledWith(t, game, "Ruth")
+			})
+		}
+	})
+
+	t.Run("clients asking for protobuf get binary blind alerts", func(t *testing.T) {
+		leaktest.Check(t)
+
+		game := &poker.GameSpy{}
+		game.BlindAlert = []byte("Blind is now 100\n")
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+
+		dialer := websocket.Dialer{Subprotocols: []string{poker.ProtobufSubprotocol}}
+		ws, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+		if err != nil {
+			t.Fatalf("could not open a ws connection %v", err)
+		}
+		defer ws.Close()
+
+		if ws.Subprotocol() != poker.ProtobufSubprotocol {
+			t.Fatalf("got subprotocol %q, want %q", ws.Subprotocol(), poker.ProtobufSubprotocol)
+		}
+
+		writeWSMessage(t, ws, "3")
+
+		within(t, time.Second, func() {
+			messageType, data, err := ws.ReadMessage()
+			if err != nil {
+				t.Errorf("could not read message, %v", err)
+				return
+			}
+			if messageType != websocket.BinaryMessage {
+				t.Errorf("got message type %d, want binary", messageType)
+			}
+
+			got, err := poker.ProtobufEncoding{}.Decode(data)
+			if err != nil || got.Alert != "Blind is now 100" {
+				t.Errorf("got %+v, %v, want the blind alert", got, err)
+			}
+		})
+	})
+}
+
+func TestResumableGame(t *testing.T) {
+	t.Run("a player who reconnects is sent the game state and can finish the game", func(t *testing.T) {
+		leaktest.Check(t)
+
+		clock := &fakeClock{now: time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)}
+		alerts := make(chan io.Writer, 11)
+		alerter := poker.BlindAlerterFunc(func(_ time.Duration, _ int, to io.Writer) { alerts <- to })
+		store := &poker.StubPlayerStore{}
+		game := poker.NewTexasHoldem(alerter, store, poker.WithClock(clock.Now))
+
+		server := httptest.NewServer(mustMakePlayerServer(t, store, game))
+		defer server.Close()
+		gameURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/table-1"
+
+		first := mustDialWS(t, gameURL)
+		writeWSMessage(t, first, "3")
+		alertsTo := <-alerts
+
+		first.Close()
+		clock.advance(9 * time.Minute)
+
+		second := mustDialWS(t, gameURL)
+		defer second.Close()
+
+		var got poker.GameState
+		within(t, time.Second, func() {
+			if err := second.ReadJSON(&got); err != nil {
+				t.Errorf("could not read game state, %v", err)
+			}
+		})
+		assertGameState(t, got, poker.GameState{Players: 3, Blind: 200, Elapsed: 9 * time.Minute})
+		assertPresence(t, second, poker.Presence{Event: poker.PresenceJoin, Role: poker.RolePlayer})
+
+		fmt.Fprint(alertsTo, "Blind is now 300")
+		within(t, time.Second, func() { assertWebsocketGotMsg(t, second, "Blind is now 300") })
+
+		writeWSMessage(t, second, "Ruth")
+		if !retryUntil(time.Second, func() bool { return store.WinCallsFor("Ruth") == 1 }) {
+			t.Error("expected Ruth's win to be recorded")
+		}
+	})
+
+	t.Run("games played at the same time keep their own state", func(t *testing.T) {
+		leaktest.Check(t)
+
+		clock := &fakeClock{now: time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)}
+		started := make(chan int, 22)
+		alerter := poker.BlindAlerterFunc(func(_ time.Duration, amount int, _ io.Writer) { started <- amount })
+		game := poker.NewTexasHoldem(alerter, dummyPlayerStore, poker.WithClock(clock.Now))
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/"
+
+		for id, players := range map[string]string{"first": "3", "second": "7"} {
+			ws := mustDialWS(t, wsURL+id)
+			defer ws.Close()
+			writeWSMessage(t, ws, players)
+			<-started
+		}
+
+		for id, want := range map[string]int{"first": 3, "second": 7} {
+			// the game may not be marked as started just yet, in which case there's no state to send
+			var got poker.GameState
+			retryUntil(time.Second, func() bool {
+				spectator := mustDialWS(t, wsURL+id+"/spectate")
+				defer spectator.Close()
+				if msg := readServerMessage(t, spectator); msg.GameState != nil {
+					got = *msg.GameState
+					return true
+				}
+				return false
+			})
+			assertGameState(t, got, poker.GameState{Players: want, Blind: 100})
+		}
+	})
+
+	t.Run("a dropped connection does not finish the game", func(t *testing.T) {
+		leaktest.Check(t)
+
+		game := &poker.GameSpy{}
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws/table-2")
+		writeWSMessage(t, ws, "4")
+		assertGameStartedWith(t, game, 4)
+		ws.Close()
+
+		time.Sleep(tenMS)
+		assertGameNotFinished(t, game)
+	})
+
+	t.Run("a game is kept for its player to come back, then forgotten", func(t *testing.T) {
+		leaktest.Check(t)
+
+		clock := &fakeClock{now: time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)}
+		server := httptest.NewServer(mustMakePlayerServerWith(t, dummyPlayerStore, poker.WithResumeWindow(time.Minute, clock)))
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/"
+
+		started := mustDialWS(t, wsURL+"started")
+		writeWSMessage(t, started, "3")
+		unstarted := mustDialWS(t, wsURL+"unstarted")
+		assertPresence(t, unstarted, poker.Presence{Event: poker.PresenceJoin, Role: poker.RolePlayer})
+		started.Close()
+		unstarted.Close()
+
+		forgotten := func(id string) bool {
+			response, err := http.Get(server.URL + "/game/" + id + "/presence")
+			if err != nil {
+				t.Fatal(err)
+			}
+			response.Body.Close()
+			return response.StatusCode == http.StatusNotFound
+		}
+
+		if !retryUntil(time.Second, func() bool { return forgotten("unstarted") }) {
+			t.Error("expected a game which never started to be forgotten once its player left")
+		}
+		if !retryUntil(time.Second, func() bool { return !forgotten("started") && clockHasTimers(clock) }) {
+			t.Fatal("expected a started game to be kept for its player to come back")
+		}
+
+		clock.advance(time.Minute)
+		if !forgotten("started") {
+			t.Error("expected the game to be forgotten once the resume window was over")
+		}
+	})
+
+	t.Run("only the player who started a game can play it", func(t *testing.T) {
+		leaktest.Check(t)
+
+		users := poker.NewInMemoryUserStore()
+		for _, name := range []string{"Chris", "Cleo"} {
+			assertNoError(t, users.AddUser(mustMakeUser(t, name, "correct horse")))
+		}
+		server := httptest.NewServer(mustMakePlayerServerWith(t, dummyPlayerStore,
+			poker.WithAccounts(users, poker.WithPasswordHashing(fastHashing))))
+		defer server.Close()
+		gameURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/chris-table"
+
+		dialAs := func(name string) (*websocket.Conn, *http.Response, error) {
+			cookie := logIn(t, server.Config.Handler, name, "correct horse")
+			return websocket.DefaultDialer.Dial(gameURL, http.Header{"Cookie": {cookie.String()}})
+		}
+
+		chris, _, err := dialAs("Chris")
+		assertNoError(t, err)
+		defer chris.Close()
+		writeWSMessage(t, chris, "3")
+
+		_, response, err := dialAs("Cleo")
+		if err == nil || response.StatusCode != http.StatusForbidden {
+			t.Errorf("got %v, want a 403", err)
+		}
+	})
+
+	t.Run("a request which isn't a websocket is turned away without joining the game", func(t *testing.T) {
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, dummyGame))
+		defer server.Close()
+
+		response, err := http.Get(server.URL + "/ws/table-5")
+		assertNoError(t, err)
+		response.Body.Close()
+		if response.StatusCode != http.StatusBadRequest {
+			t.Errorf("got status %d want %d", response.StatusCode, http.StatusBadRequest)
+		}
+
+		response, err = http.Get(server.URL + "/game/table-5/presence")
+		assertNoError(t, err)
+		response.Body.Close()
+		if response.StatusCode != http.StatusNotFound {
+			t.Errorf("got status %d, want the game to have been forgotten", response.StatusCode)
+		}
+	})
+}
+
+// clockHasTimers reports whether anything is waiting for clock to move on.
+func clockHasTimers(clock *fakeClock) bool {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	for _, timer := range clock.timers {
+		if !timer.done {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGamePresence(t *testing.T) {
+	leaktest.Check(t)
+
+	game := &poker.GameSpy{}
+	game.BlindAlert = []byte("Blind is now 100\n")
+	server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/table-3"
+	join := func(role string, spectators int) poker.Presence {
+		return poker.Presence{Event: poker.PresenceJoin, Role: role, Spectators: spectators}
+	}
+	leave := func(role string, spectators int) poker.Presence {
+		return poker.Presence{Event: poker.PresenceLeave, Role: role, Spectators: spectators}
+	}
+
+	player := mustDialWS(t, wsURL)
+	defer player.Close()
+	assertPresence(t, player, join(poker.RolePlayer, 0))
+
+	writeWSMessage(t, player, "3")
+	assertGameStartedWith(t, game, 3)
+	within(t, time.Second, func() { assertWebsocketGotMsg(t, player, "Blind is now 100\n") })
+
+	first := mustDialWS(t, wsURL+"/spectate")
+	defer first.Close()
+	assertNextMessageIsGameState(t, first)
+	assertPresence(t, first, join(poker.RoleSpectator, 1))
+	assertPresence(t, player, join(poker.RoleSpectator, 1))
+
+	second := mustDialWS(t, wsURL+"/spectate")
+	assertNextMessageIsGameState(t, second)
+	for _, ws := range []*websocket.Conn{player, first, second} {
+		assertPresence(t, ws, join(poker.RoleSpectator, 2))
+	}
+
+	assertGamePresence(t, server.URL+"/game/table-3/presence", poker.GamePresence{Game: "table-3", PlayerConnected: true, Spectators: 2})
+
+	second.Close()
+	for _, ws := range []*websocket.Conn{player, first} {
+		assertPresence(t, ws, leave(poker.RoleSpectator, 1))
+	}
+
+	player.Close()
+	assertPresence(t, first, leave(poker.RolePlayer, 1))
+
+	assertGamePresence(t, server.URL+"/game/table-3/presence", poker.GamePresence{Game: "table-3", PlayerConnected: false, Spectators: 1})
+
+	t.Run("spectating or asking about a game nobody is playing is a 404", func(t *testing.T) {
+		_, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/nope/spectate", nil)
+		if err == nil || response.StatusCode != http.StatusNotFound {
+			t.Errorf("got %v, want a 404", err)
+		}
+
+		request, _ := http.NewRequest(http.MethodGet, "/game/nope/presence", nil)
+		recorder := httptest.NewRecorder()
+		mustMakePlayerServer(t, dummyPlayerStore, game).ServeHTTP(recorder, request)
+		assertStatus(t, recorder, http.StatusNotFound)
+	})
+}
+
+func TestGameSeating(t *testing.T) {
+	leaktest.Check(t)
+
+	game := &poker.GameSpy{}
+	game.BlindAlert = []byte("Blind is now 100\n")
+	server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/table-4"
+	seating := func(dealer int, players ...string) poker.Seating {
+		s := poker.Seating{Dealer: dealer}
+		for i, player := range players {
+			s.Seats = append(s.Seats, poker.Seat{Number: i + 1, Player: player})
+		}
+		return s
+	}
+
+	player := mustDialWS(t, wsURL)
+	defer player.Close()
+	assertPresence(t, player, poker.Presence{Event: poker.PresenceJoin, Role: poker.RolePlayer})
+
+	writeWSMessage(t, player, "pies")
+	if got := readServerMessage(t, player); got.Alert != poker.BadPlayerInputErrMsg {
+		t.Errorf("got %+v, want to be asked for a number", got)
+	}
+	writeWSMessage(t, player, "Chris, Chris")
+	if got := readServerMessage(t, player); got.Alert != `player "Chris" conflicts with an existing one` {
+		t.Errorf("got %+v, want to be told Chris is named twice", got)
+	}
+	assertGameNotStarted(t, game)
+
+	writeWSMessage(t, player, "Chris, Cleo, Ruth")
+	assertGameStartedWith(t, game, 3)
+	within(t, time.Second, func() { assertWebsocketGotMsg(t, player, "Blind is now 100\n") })
+	assertSeatingSent(t, player, seating(1, "Chris", "Cleo", "Ruth"))
+
+	spectator := mustDialWS(t, wsURL+"/spectate")
+	defer spectator.Close()
+	assertNextMessageIsGameState(t, spectator)
+	assertSeatingSent(t, spectator, seating(1, "Chris", "Cleo", "Ruth"))
+	assertPresence(t, spectator, poker.Presence{Event: poker.PresenceJoin, Role: poker.RoleSpectator, Spectators: 1})
+	assertPresence(t, player, poker.Presence{Event: poker.PresenceJoin, Role: poker.RoleSpectator, Spectators: 1})
+
+	writeWSMessage(t, player, poker.NextHandCommand)
+	for _, ws := range []*websocket.Conn{player, spectator} {
+		assertSeatingSent(t, ws, seating(2, "Chris", "Cleo", "Ruth"))
+	}
+
+	writeWSMessage(t, player, "Lloyd is out")
+	if got := readServerMessage(t, player); got.Alert != `player "Lloyd" not found` {
+		t.Errorf("got %+v, want to be told Lloyd isn't at the table", got)
+	}
+
+	writeWSMessage(t, player, "Chris")
+	assertFinishCalledWith(t, game, "Chris")
+}
+
+func assertSeatingSent(t testing.TB, ws *websocket.Conn, want poker.Seating) {
+	t.Helper()
+	if got := readServerMessage(t, ws); got.Seating == nil || !reflect.DeepEqual(*got.Seating, want) {
+		t.Errorf("got %+v, want seating %+v", got, want)
+	}
+}
+
+func readServerMessage(t testing.TB, ws *websocket.Conn) poker.ServerMessage {
+	t.Helper()
+	var msg poker.ServerMessage
+
+	within(t, time.Second, func() {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			t.Errorf("could not read message, %v", err)
+			return
+		}
+		msg, _ = poker.TextEncoding{}.Decode(data)
+	})
+
+	return msg
+}
+
+func assertPresence(t testing.TB, ws *websocket.Conn, want poker.Presence) {
+	t.Helper()
+	got := readServerMessage(t, ws)
+	if got.Presence == nil || *got.Presence != want {
+		t.Errorf("got %+v, want presence %+v", got, want)
+	}
+}
+
+func assertNextMessageIsGameState(t testing.TB, ws *websocket.Conn) {
+	t.Helper()
+	if got := readServerMessage(t, ws); got.GameState == nil {
+		t.Errorf("got %+v, want a game state", got)
+	}
+}
+
+func assertGamePresence(t testing.TB, url string, want poker.GamePresence) {
+	t.Helper()
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	var got poker.GamePresence
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatalf("could not decode presence, %v", err)
+	}
+	if got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
+
+func TestLiveLeague(t *testing.T) {
+	leaktest.Check(t)
+
+	t.Run("every connected client is sent the league when a win is recorded", func(t *testing.T) {
+		store := poker.NewInMemoryPlayerStore()
+		store.RecordWin("Chris")
+
+		server := httptest.NewServer(mustMakePlayerServer(t, store, dummyGame))
+		defer server.Close()
+
+		liveURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/league/live"
+		alice := mustDialWS(t, liveURL)
+		defer alice.Close()
+		bob := mustDialWS(t, liveURL)
+		defer bob.Close()
+
+		initial := []poker.Player{{Name: "Chris", Wins: 1}}
+		within(t, time.Second, func() { assertWebsocketGotLeague(t, alice, initial) })
+		within(t, time.Second, func() { assertWebsocketGotLeague(t, bob, initial) })
+
+		response, err := http.Post(server.URL+"/v1/players/Pepper", "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+
+		updated := []poker.Player{{Name: "Chris", Wins: 1}, {Name: "Pepper", Wins: 1}}
+		within(t, time.Second, func() { assertWebsocketGotLeague(t, alice, updated) })
+		within(t, time.Second, func() { assertWebsocketGotLeague(t, bob, updated) })
+	})
+}
+
+func assertWebsocketGotLeague(t *testing.T, ws *websocket.Conn, want []poker.Player) {
+	t.Helper()
+	var got []poker.Player
+	if err := ws.ReadJSON(&got); err != nil {
+		t.Errorf("could not read league from websocket, %v", err)
+		return
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Name < got[j].Name })
+	assertLeague(t, got, want)
 }
 
 func assertWebsocketGotMsg(t *testing.T, ws *websocket.Conn, want string) {
@@ -191,6 +1192,20 @@ func assertContentType(t testing.TB, response *httptest.ResponseRecorder, want s
 	}
 }
 
+func assertHeader(t testing.TB, response *httptest.ResponseRecorder, key, want string) {
+	t.Helper()
+	if got := response.Header().Get(key); got != want {
+		t.Errorf("got %s header %q, want %q", key, got, want)
+	}
+}
+
+func assertNoHeader(t testing.TB, response *httptest.ResponseRecorder, key string) {
+	t.Helper()
+	if got := response.Header().Get(key); got != "" {
+		t.Errorf("did not expect a %s header but got %q", key, got)
+	}
+}
+
 func getLeagueFromResponse(t *testing.T, body io.Reader) []poker.Player {
 	t.Helper()
 	league, err := poker.NewLeague(body)
@@ -209,7 +1224,7 @@ func assertLeague(t *testing.T, got, want []poker.Player) {
 	}
 }
 
-func assertStatus(t *testing.T, got *httptest.ResponseRecorder, want int) {
+func assertStatus(t testing.TB, got *httptest.ResponseRecorder, want int) {
 	t.Helper()
 	if got.Code != want {
 		t.Errorf("did not get correct status, got %d, want %d", got.Code, want)
@@ -0,0 +1,116 @@
+// Package simulate plays a whole game of poker against a fake clock, so the blind schedule for a
+// number of players can be previewed in an instant rather than over an evening.
+package simulate
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+// Event is an alert the players would have seen, and how far into the game it came.
+type Event struct {
+	At      time.Duration
+	Message string
+}
+
+// Timeline is everything that happened in a simulated game, in order.
+type Timeline []Event
+
+// Run starts a game of TexasHoldem for numberOfPlayers and skips the clock forward to each blind
+// alert in turn, until every one has been delivered.
+func Run(numberOfPlayers int) Timeline {
+	clock := &fakeClock{now: time.Date(2026, time.January, 1, 19, 0, 0, 0, time.UTC)}
+	recorder := &timelineRecorder{clock: clock, start: clock.now}
+
+	// alerts as poker.Alerter does, but on the fake clock
+	alerter := poker.BlindAlerterFunc(func(duration time.Duration, amount int, to io.Writer) {
+		clock.afterFunc(duration, func() {
+			fmt.Fprintf(to, "Blind is now %d\n", amount)
+		})
+	})
+
+	game := poker.NewTexasHoldem(alerter, poker.NewInMemoryPlayerStore(), poker.WithClock(clock.Now))
+	game.Start(numberOfPlayers, recorder)
+	clock.run()
+
+	return recorder.timeline
+}
+
+// WriteTo writes the timeline as a table of when each alert came and what it said.
+func (t Timeline) WriteTo(w io.Writer) (int64, error) {
+	var buf strings.Builder
+
+	table := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "TIME\tALERT")
+	for _, event := range t {
+		fmt.Fprintf(table, "%s\t%s\n", formatElapsed(event.At), event.Message)
+	}
+	table.Flush()
+
+	n, err := io.WriteString(w, buf.String())
+	return int64(n), err
+}
+
+// formatElapsed shows how far into a game something happened as hours and minutes, like 1:05.
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// timelineRecorder is where the game sends its alerts, noting when each one arrives.
+type timelineRecorder struct {
+	clock    *fakeClock
+	start    time.Time
+	timeline Timeline
+}
+
+func (r *timelineRecorder) Write(p []byte) (int, error) {
+	r.timeline = append(r.timeline, Event{
+		At:      r.clock.Now().Sub(r.start),
+		Message: strings.TrimSpace(string(p)),
+	})
+	return len(p), nil
+}
+
+// fakeClock only moves when run, jumping straight to each timer as it becomes due.
+type fakeClock struct {
+	now    time.Time
+	timers []timer
+}
+
+type timer struct {
+	due time.Time
+	f   func()
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) afterFunc(d time.Duration, f func()) {
+	c.timers = append(c.timers, timer{due: c.now.Add(d), f: f})
+}
+
+// run fires every timer in the order they are due, including any they set themselves. Timers due
+// at the same time fire in the order they were set.
+func (c *fakeClock) run() {
+	for len(c.timers) > 0 {
+		next := 0
+		for i, t := range c.timers {
+			if t.due.Before(c.timers[next].due) {
+				next = i
+			}
+		}
+
+		t := c.timers[next]
+		c.timers = slices.Delete(c.timers, next, next+1)
+
+		c.now = t.due
+		t.f()
+	}
+}
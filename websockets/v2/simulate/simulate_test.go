@@ -0,0 +1,74 @@
+package simulate_test
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/websockets/v2/simulate"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestRun(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		players int
+	}{
+		{"2 players", 2},
+		{"5 players", 5},
+		{"10 players", 10},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if _, err := simulate.Run(test.players).WriteTo(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			assertGolden(t, buf.Bytes())
+		})
+	}
+
+	t.Run("blinds go up as the game goes on", func(t *testing.T) {
+		timeline := simulate.Run(5)
+
+		if len(timeline) != 11 {
+			t.Fatalf("got %d alerts, want 11", len(timeline))
+		}
+
+		first, last := timeline[0], timeline[len(timeline)-1]
+		if first != (simulate.Event{At: 0, Message: "Blind is now 100"}) {
+			t.Errorf("first alert was %+v", first)
+		}
+		if last != (simulate.Event{At: 100 * time.Minute, Message: "Blind is now 8000"}) {
+			t.Errorf("last alert was %+v", last)
+		}
+	})
+}
+
+func assertGolden(t *testing.T, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", filepath.Base(t.Name())+".golden.txt")
+
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("could not read golden file, run the tests with -update to create it, %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run the tests with -update if the change is intended\ngot:\n%s", golden, got)
+	}
+}
@@ -0,0 +1,203 @@
+package poker
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+)
+
+// MaxChips is the most chips a player can have, or have bought in for, in a game.
+const MaxChips = 1_000_000_000
+
+// Stack is how many chips a player has bought and how many they have now.
+type Stack struct {
+	Player string `json:"player"`
+	// BuyIn is every chip the player has paid for before any add-on, rebuys included.
+	BuyIn  int `json:"buyIn"`
+	Rebuys int `json:"rebuys"`
+	AddOn  int `json:"addOn"`
+	Chips  int `json:"chips"`
+}
+
+// StackKeeper is a Game which keeps track of the players' stacks. Players seated at a Table can
+// change them with commands like "Chris rebuys 500".
+type StackKeeper interface {
+	// BuyIn gives player their first stack of chips.
+	BuyIn(player string, chips int) error
+	// Rebuy adds chips to the stack of a player who has bought in.
+	Rebuy(player string, chips int) error
+	// AddOn adds chips to the stack of a player who has bought in. Each player can add on once.
+	AddOn(player string, chips int) error
+	// UpdateStack sets how many chips player has now, usually at the end of a hand.
+	UpdateStack(player string, chips int) error
+	// Standings returns every player's stack, the biggest first.
+	Standings() []Stack
+}
+
+// Commands changing a player's stack follow their name and come before the number of chips, as
+// in "Chris buys in 1000".
+var stackCommands = []struct {
+	verb   string
+	change func(keeper StackKeeper, player string, chips int) error
+}{
+	{" buys in ", StackKeeper.BuyIn},
+	{" rebuys ", StackKeeper.Rebuy},
+	{" adds on ", StackKeeper.AddOn},
+	{" has ", StackKeeper.UpdateStack},
+}
+
+// playStack carries out a command changing the stack of a player seated at table, reporting
+// whether it was one. Nothing is a command unless game is a StackKeeper.
+func playStack(game Game, table *Table, command string) (ok bool, err error) {
+	keeper, isKeeper := game.(StackKeeper)
+	if !isKeeper {
+		return false, nil
+	}
+
+	for _, c := range stackCommands {
+		player, amount, found := strings.Cut(command, c.verb)
+		if !found {
+			continue
+		}
+
+		if !table.seated(player) {
+			return true, apperrors.NotFound("player", player, nil)
+		}
+		chips, err := strconv.Atoi(amount)
+		if err != nil {
+			return true, apperrors.Invalid("chips", "must be a whole number", nil)
+		}
+		return true, c.change(keeper, player, chips)
+	}
+	return false, nil
+}
+
+// WithRebuyPeriod only lets players rebuy for period after a game starts. Without it they can
+// rebuy at any time.
+func WithRebuyPeriod(period time.Duration) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.rebuyPeriod = period
+	}
+}
+
+// BuyIn gives player their first stack of chips in the game being played.
+func (p *TexasHoldem) BuyIn(player string, chips int) error {
+	return p.changeStack(player, func(stack *Stack, exists bool) error {
+		if exists {
+			return apperrors.Conflict("buy-in", player, nil)
+		}
+		if err := checkChips(chips, 0); err != nil {
+			return err
+		}
+		*stack = Stack{Player: player, BuyIn: chips, Chips: chips}
+		return nil
+	})
+}
+
+// Rebuy adds chips to player's stack, as long as the game's rebuy period isn't over.
+func (p *TexasHoldem) Rebuy(player string, chips int) error {
+	return p.changeStack(player, func(stack *Stack, exists bool) error {
+		if !exists {
+			return apperrors.NotFound("buy-in", player, nil)
+		}
+		if err := checkChips(chips, stack.BuyIn, stack.Chips); err != nil {
+			return err
+		}
+		if p.rebuyPeriod > 0 && p.now().Sub(p.startedAt) > p.rebuyPeriod {
+			return apperrors.Invalid("rebuy", "the rebuy period is over", nil)
+		}
+		stack.BuyIn += chips
+		stack.Rebuys++
+		stack.Chips += chips
+		return nil
+	})
+}
+
+// AddOn adds chips to player's stack. Each player can only add on once a game.
+func (p *TexasHoldem) AddOn(player string, chips int) error {
+	return p.changeStack(player, func(stack *Stack, exists bool) error {
+		if !exists {
+			return apperrors.NotFound("buy-in", player, nil)
+		}
+		if stack.AddOn > 0 {
+			return apperrors.Conflict("add-on", player, nil)
+		}
+		if err := checkChips(chips, stack.Chips); err != nil {
+			return err
+		}
+		stack.AddOn = chips
+		stack.Chips += chips
+		return nil
+	})
+}
+
+// UpdateStack sets how many chips player has now. A player with none left still has a stack,
+// so they can rebuy.
+func (p *TexasHoldem) UpdateStack(player string, chips int) error {
+	return p.changeStack(player, func(stack *Stack, exists bool) error {
+		if !exists {
+			return apperrors.NotFound("buy-in", player, nil)
+		}
+		if chips < 0 {
+			return apperrors.Invalid("chips", "must not be negative", nil)
+		}
+		if chips > MaxChips {
+			return apperrors.Invalid("chips", fmt.Sprintf(atMostFormat, MaxChips), nil)
+		}
+		stack.Chips = chips
+		return nil
+	})
+}
+
+// checkChips rejects adding chips to totals unless it's more than none and keeps every one of
+// them within MaxChips.
+func checkChips(chips int, totals ...int) error {
+	if chips <= 0 {
+		return apperrors.Invalid("chips", "must be more than 0", nil)
+	}
+	for _, total := range totals {
+		if chips > MaxChips-total {
+			return apperrors.Invalid("chips", fmt.Sprintf(atMostFormat, MaxChips-total), nil)
+		}
+	}
+	return nil
+}
+
+// Standings returns the stack of every player who has bought in to the game being played, the
+// biggest first, and players with as many chips as each other in order of name.
+func (p *TexasHoldem) Standings() []Stack {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	standings := make([]Stack, 0, len(p.stacks))
+	for _, stack := range p.stacks {
+		standings = append(standings, stack)
+	}
+	slices.SortFunc(standings, func(a, b Stack) int {
+		return cmp.Or(cmp.Compare(b.Chips, a.Chips), cmp.Compare(a.Player, b.Player))
+	})
+	return standings
+}
+
+// changeStack calls change with player's stack, or a new one if they don't have one yet, and
+// keeps it unless change returns an error.
+func (p *TexasHoldem) changeStack(player string, change func(stack *Stack, exists bool) error) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.schedule == nil {
+		return apperrors.Invalid("game", "has not started", nil)
+	}
+
+	stack, exists := p.stacks[player]
+	if err := change(&stack, exists); err != nil {
+		return err
+	}
+	p.stacks[player] = stack
+	return nil
+}
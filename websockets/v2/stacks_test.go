@@ -0,0 +1,263 @@
+package poker_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestStacks(t *testing.T) {
+	startGame := func(t *testing.T, options ...poker.TexasHoldemOption) (*fakeClock, *poker.TexasHoldem) {
+		t.Helper()
+		clock := &fakeClock{now: time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)}
+		game := poker.NewTexasHoldem(dummyBlindAlerter, &poker.StubPlayerStore{}, append(options, poker.WithClock(clock.Now))...)
+		game.Start(3, io.Discard)
+		return clock, game
+	}
+
+	t.Run("keeps track of buy-ins, rebuys, add-ons and stacks", func(t *testing.T) {
+		_, game := startGame(t)
+
+		assertNoError(t, game.BuyIn("Chris", 1000))
+		assertNoError(t, game.BuyIn("Cleo", 1000))
+		assertNoError(t, game.UpdateStack("Cleo", 0))
+		assertNoError(t, game.Rebuy("Cleo", 1000))
+		assertNoError(t, game.AddOn("Cleo", 500))
+		assertNoError(t, game.UpdateStack("Chris", 2000))
+
+		assertStandings(t, game.Standings(), []poker.Stack{
+			{Player: "Chris", BuyIn: 1000, Chips: 2000},
+			{Player: "Cleo", BuyIn: 2000, Rebuys: 1, AddOn: 500, Chips: 1500},
+		})
+	})
+
+	t.Run("players with as many chips as each other are in order of name", func(t *testing.T) {
+		_, game := startGame(t)
+
+		assertNoError(t, game.BuyIn("Ruth", 1000))
+		assertNoError(t, game.BuyIn("Chris", 1000))
+
+		assertStandings(t, game.Standings(), []poker.Stack{
+			{Player: "Chris", BuyIn: 1000, Chips: 1000},
+			{Player: "Ruth", BuyIn: 1000, Chips: 1000},
+		})
+	})
+
+	t.Run("rebuys are only allowed during the rebuy period", func(t *testing.T) {
+		clock, game := startGame(t, poker.WithRebuyPeriod(time.Hour))
+		assertNoError(t, game.BuyIn("Chris", 1000))
+
+		clock.advance(time.Hour)
+		assertNoError(t, game.Rebuy("Chris", 1000))
+
+		clock.advance(time.Second)
+		err := game.Rebuy("Chris", 1000)
+
+		assertInvalid(t, err)
+		assertStandings(t, game.Standings(), []poker.Stack{{Player: "Chris", BuyIn: 2000, Rebuys: 1, Chips: 2000}})
+	})
+
+	t.Run("changes which can't be made leave the stacks as they were", func(t *testing.T) {
+		_, game := startGame(t)
+		assertNoError(t, game.BuyIn("Chris", 1000))
+		assertNoError(t, game.AddOn("Chris", 500))
+
+		cases := map[string]struct {
+			change func() error
+			is     func(error) bool
+		}{
+			"buying in twice":           {func() error { return game.BuyIn("Chris", 1000) }, apperrors.IsConflict},
+			"adding on twice":           {func() error { return game.AddOn("Chris", 500) }, apperrors.IsConflict},
+			"rebuying without a stack":  {func() error { return game.Rebuy("Cleo", 1000) }, apperrors.IsNotFound},
+			"adding on without a stack": {func() error { return game.AddOn("Cleo", 500) }, apperrors.IsNotFound},
+			"updating a missing stack":  {func() error { return game.UpdateStack("Cleo", 500) }, apperrors.IsNotFound},
+			"buying in for nothing":     {func() error { return game.BuyIn("Cleo", 0) }, apperrors.IsInvalid},
+			"rebuying for nothing":      {func() error { return game.Rebuy("Chris", 0) }, apperrors.IsInvalid},
+			"a negative stack":          {func() error { return game.UpdateStack("Chris", -1) }, apperrors.IsInvalid},
+			"buying in for too much":    {func() error { return game.BuyIn("Cleo", poker.MaxChips+1) }, apperrors.IsInvalid},
+			"rebuying for too much":     {func() error { return game.Rebuy("Chris", poker.MaxChips) }, apperrors.IsInvalid},
+			"too big a stack":           {func() error { return game.UpdateStack("Chris", poker.MaxChips+1) }, apperrors.IsInvalid},
+		}
+
+		for name, c := range cases {
+			t.Run(name, func(t *testing.T) {
+				if err := c.change(); !c.is(err) {
+					t.Errorf("got error %v", err)
+				}
+				assertStandings(t, game.Standings(), []poker.Stack{{Player: "Chris", BuyIn: 1000, AddOn: 500, Chips: 1500}})
+			})
+		}
+	})
+
+	t.Run("stacks can be topped up to the most chips, but no further", func(t *testing.T) {
+		_, game := startGame(t)
+		assertNoError(t, game.BuyIn("Chris", poker.MaxChips-1000))
+		assertNoError(t, game.AddOn("Chris", 1000))
+
+		err := game.Rebuy("Chris", 1)
+
+		assertInvalid(t, err)
+		if want := "invalid chips: must be at most 0"; err.Error() != want {
+			t.Errorf("got error %q want %q", err, want)
+		}
+		assertStandings(t, game.Standings(), []poker.Stack{{Player: "Chris", BuyIn: poker.MaxChips - 1000, AddOn: 1000, Chips: poker.MaxChips}})
+	})
+
+	t.Run("stacks last until the game finishes", func(t *testing.T) {
+		game := poker.NewTexasHoldem(dummyBlindAlerter, &poker.StubPlayerStore{})
+
+		assertInvalid(t, game.BuyIn("Chris", 1000))
+
+		game.Start(2, io.Discard)
+		assertNoError(t, game.BuyIn("Chris", 1000))
+		game.Finish("Chris")
+
+		assertStandings(t, game.Standings(), []poker.Stack{})
+		assertInvalid(t, game.BuyIn("Chris", 1000))
+	})
+}
+
+func TestStandings(t *testing.T) {
+	t.Run("stack commands at a table reach the game", func(t *testing.T) {
+		game := &poker.GameSpy{}
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws/stacks")
+		defer ws.Close()
+		assertPresence(t, ws, poker.Presence{Event: poker.PresenceJoin, Role: poker.RolePlayer})
+
+		writeWSMessage(t, ws, "Chris, Cleo")
+		readServerMessage(t, ws) // the GameSpy's empty blind alert
+		readServerMessage(t, ws) // the seating
+
+		writeWSMessage(t, ws, "Lloyd buys in 1000")
+		assertAlertSent(t, ws, `player "Lloyd" not found`)
+
+		writeWSMessage(t, ws, "Chris buys in 1000")
+		writeWSMessage(t, ws, "Cleo rebuys 500")
+		writeWSMessage(t, ws, "Chris")
+
+		if winner := game.WaitForFinish(t); winner != "Chris" {
+			t.Fatalf("got winner %q want Chris", winner)
+		}
+		assertStackChanges(t, game,
+			poker.StackChange{Change: "buys in", Player: "Chris", Chips: 1000},
+			poker.StackChange{Change: "rebuys", Player: "Cleo", Chips: 500},
+		)
+	})
+
+	t.Run("serves the standings of the game being played", func(t *testing.T) {
+		game := poker.NewTexasHoldem(dummyBlindAlerter, &poker.StubPlayerStore{})
+		server := mustMakePlayerServer(t, dummyPlayerStore, game)
+
+		game.Start(2, io.Discard)
+		assertNoError(t, game.BuyIn("Chris", 1000))
+		assertNoError(t, game.BuyIn("Cleo", 1000))
+		assertNoError(t, game.UpdateStack("Cleo", 1500))
+
+		assertStandings(t, getStandings(t, server), []poker.Stack{
+			{Player: "Cleo", BuyIn: 1000, Chips: 1500},
+			{Player: "Chris", BuyIn: 1000, Chips: 1000},
+		})
+	})
+
+	t.Run("serves the standings of each game played over /ws/{id}", func(t *testing.T) {
+		game := poker.NewTexasHoldem(dummyBlindAlerter, &poker.StubPlayerStore{})
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/"
+		for _, id := range []string{"table-1", "table-2"} {
+			ws := mustDialWS(t, wsURL+id)
+			defer ws.Close()
+			assertPresence(t, ws, poker.Presence{Event: poker.PresenceJoin, Role: poker.RolePlayer})
+			writeWSMessage(t, ws, "Chris, Cleo")
+			readServerMessage(t, ws) // the seating
+
+			if id == "table-1" {
+				writeWSMessage(t, ws, "Chris buys in 1000")
+			}
+		}
+
+		want := []poker.Stack{{Player: "Chris", BuyIn: 1000, Chips: 1000}}
+		if !retryUntil(time.Second, func() bool { return reflect.DeepEqual(getGameStandings(t, server.URL, "table-1"), want) }) {
+			assertStandings(t, getGameStandings(t, server.URL, "table-1"), want)
+		}
+		assertStandings(t, getGameStandings(t, server.URL, "table-2"), []poker.Stack{})
+		assertStandings(t, getStandings(t, server.Config.Handler), []poker.Stack{})
+
+		response, err := http.Get(server.URL + "/game/nope/standings")
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusNotFound {
+			t.Errorf("got status %d for a game that doesn't exist, want 404", response.StatusCode)
+		}
+	})
+
+	t.Run("serves no standings when the game doesn't keep stacks", func(t *testing.T) {
+		server := mustMakePlayerServer(t, dummyPlayerStore, struct{ poker.Game }{dummyGame})
+
+		assertStandings(t, getStandings(t, server), []poker.Stack{})
+	})
+}
+
+func getStandings(t testing.TB, server http.Handler) []poker.Stack {
+	t.Helper()
+
+	request, _ := http.NewRequest(http.MethodGet, "/standings", nil)
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, request)
+
+	assertStatus(t, response, http.StatusOK)
+	assertContentType(t, response, "application/json")
+
+	var standings []poker.Stack
+	if err := json.NewDecoder(response.Body).Decode(&standings); err != nil {
+		t.Fatalf("could not decode standings from %q, %v", response.Body, err)
+	}
+	return standings
+}
+
+func getGameStandings(t testing.TB, serverURL, id string) []poker.Stack {
+	t.Helper()
+
+	response, err := http.Get(serverURL + "/game/" + id + "/standings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d getting the standings of %s", response.StatusCode, id)
+	}
+	var standings []poker.Stack
+	if err := json.NewDecoder(response.Body).Decode(&standings); err != nil {
+		t.Fatalf("could not decode standings, %v", err)
+	}
+	return standings
+}
+
+func assertStandings(t testing.TB, got, want []poker.Stack) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got standings %+v want %+v", got, want)
+	}
+}
+
+func assertInvalid(t testing.TB, err error) {
+	t.Helper()
+	if !apperrors.IsInvalid(err) {
+		t.Errorf("got error %v, want an invalid error", err)
+	}
+}
@@ -0,0 +1,70 @@
+// Package stats works out how well a player is doing from the history of who won each game.
+package stats
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stats summarises one player's record.
+type Stats struct {
+	Name          string  `json:"name"`
+	Games         int     `json:"games"`
+	Wins          int     `json:"wins"`
+	WinRate       float64 `json:"winRate"`
+	CurrentStreak int     `json:"currentStreak"`
+	LongestStreak int     `json:"longestStreak"`
+
+	// AverageGameLength is how long games have lasted on average, when that is known.
+	AverageGameLength time.Duration `json:"averageGameLength,omitempty"`
+}
+
+// For calculates name's Stats from winners, the winner of every game in the order they were
+// played. A streak is a run of games in a row won by name; the current streak is the one
+// still going at the end of the history.
+func For(name string, winners []string) Stats {
+	s := Stats{Name: name, Games: len(winners)}
+
+	streak := 0
+	for _, winner := range winners {
+		if winner != name {
+			streak = 0
+			continue
+		}
+
+		s.Wins++
+		streak++
+		s.LongestStreak = max(s.LongestStreak, streak)
+	}
+
+	s.CurrentStreak = streak
+	if s.Games > 0 {
+		s.WinRate = float64(s.Wins) / float64(s.Games)
+	}
+
+	return s
+}
+
+func (s Stats) String() string {
+	summary := fmt.Sprintf("%s has won %d of %d games (%.0f%%), current streak %d, longest streak %d",
+		s.Name, s.Wins, s.Games, s.WinRate*100, s.CurrentStreak, s.LongestStreak)
+
+	if s.AverageGameLength > 0 {
+		summary += fmt.Sprintf(", games last %v on average", s.AverageGameLength.Round(time.Second))
+	}
+
+	return summary
+}
+
+// AverageLength returns the mean of lengths, or 0 if there are none.
+func AverageLength(lengths []time.Duration) time.Duration {
+	if len(lengths) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, length := range lengths {
+		total += length
+	}
+	return total / time.Duration(len(lengths))
+}
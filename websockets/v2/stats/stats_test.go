@@ -0,0 +1,98 @@
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/websockets/v2/stats"
+)
+
+func TestFor(t *testing.T) {
+	cases := []struct {
+		name    string
+		winners []string
+		want    stats.Stats
+	}{
+		{
+			name:    "no games played",
+			winners: nil,
+			want:    stats.Stats{Name: "Chris"},
+		},
+		{
+			name:    "never won",
+			winners: []string{"Cleo", "Ruth"},
+			want:    stats.Stats{Name: "Chris", Games: 2},
+		},
+		{
+			name:    "won every game",
+			winners: []string{"Chris", "Chris", "Chris"},
+			want:    stats.Stats{Name: "Chris", Games: 3, Wins: 3, WinRate: 1, CurrentStreak: 3, LongestStreak: 3},
+		},
+		{
+			name:    "an old streak is longer than the current one",
+			winners: []string{"Chris", "Chris", "Chris", "Cleo", "Chris"},
+			want:    stats.Stats{Name: "Chris", Games: 5, Wins: 4, WinRate: 0.8, CurrentStreak: 1, LongestStreak: 3},
+		},
+		{
+			name:    "the last game was lost",
+			winners: []string{"Chris", "Chris", "Cleo", "Chris", "Ruth"},
+			want:    stats.Stats{Name: "Chris", Games: 5, Wins: 3, WinRate: 0.6, CurrentStreak: 0, LongestStreak: 2},
+		},
+		{
+			name:    "names are case sensitive",
+			winners: []string{"chris", "Chris"},
+			want:    stats.Stats{Name: "Chris", Games: 2, Wins: 1, WinRate: 0.5, CurrentStreak: 1, LongestStreak: 1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := stats.For("Chris", c.winners)
+
+			if got != c.want {
+				t.Errorf("got %+v want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	got := stats.For("Chris", []string{"Chris", "Cleo", "Chris", "Chris"}).String()
+	want := "Chris has won 3 of 4 games (75%), current streak 2, longest streak 2"
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestStringWithAverageGameLength(t *testing.T) {
+	s := stats.For("Chris", []string{"Chris", "Cleo", "Chris", "Chris"})
+	s.AverageGameLength = 42 * time.Minute
+
+	got := s.String()
+	want := "Chris has won 3 of 4 games (75%), current streak 2, longest streak 2, games last 42m0s on average"
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestAverageLength(t *testing.T) {
+	cases := []struct {
+		name    string
+		lengths []time.Duration
+		want    time.Duration
+	}{
+		{"no games", nil, 0},
+		{"one game", []time.Duration{time.Hour}, time.Hour},
+		{"several games", []time.Duration{10 * time.Minute, 20 * time.Minute, 60 * time.Minute}, 30 * time.Minute},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := stats.AverageLength(c.lengths); got != c.want {
+				t.Errorf("got %v want %v", got, c.want)
+			}
+		})
+	}
+}
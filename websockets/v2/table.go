@@ -0,0 +1,207 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+)
+
+// MaxSeats is how many players fit around a Table.
+const MaxSeats = 10
+
+const minPlayers = 2
+
+// Commands players can give during a game at a Table, as well as declaring the winner.
+const (
+	// NextHandCommand moves the dealer button on to the next player.
+	NextHandCommand = "next hand"
+
+	// eliminatedSuffix follows the name of a player who has been knocked out, as in "Chris is out".
+	eliminatedSuffix = " is out"
+
+	// actedSuffix follows the name of the player whose turn it was once they have acted, as in
+	// "Chris acts", passing the action on to the next player.
+	actedSuffix = " acts"
+)
+
+// Seat is a numbered place at a Table, and who is sitting in it.
+type Seat struct {
+	Number int    `json:"number"`
+	Player string `json:"player"`
+}
+
+// Seating is who is sitting where and which seat has the dealer button. It is sent to everyone
+// watching a game whenever it changes.
+type Seating struct {
+	Seats  []Seat `json:"seats"`
+	Dealer int    `json:"dealer"`
+}
+
+// Table seats the players of a game in the order they were declared, and keeps track of the
+// dealer button as it moves around them and of whose turn it is to act.
+type Table struct {
+	// seats holds the player in each seat, seat 1 first. Eliminated players leave an empty seat.
+	seats  []string
+	button int
+	toAct  int
+	// turns counts how many times the action has moved, so it's easy to tell if it has
+	turns int
+}
+
+// NewTable seats players in order from seat 1, which gets the dealer button for the first hand.
+func NewTable(players []string) (*Table, error) {
+	if len(players) < minPlayers {
+		return nil, apperrors.Invalid("players", fmt.Sprintf("need at least %d", minPlayers), nil)
+	}
+	if len(players) > MaxSeats {
+		return nil, apperrors.Invalid("players", fmt.Sprintf("no more than %d fit at a table", MaxSeats), nil)
+	}
+
+	for i, player := range players {
+		if player == "" {
+			return nil, apperrors.Invalid("name", "must not be empty", nil)
+		}
+		if slices.Contains(players[:i], player) {
+			return nil, apperrors.Conflict("player", player, nil)
+		}
+	}
+
+	table := &Table{seats: slices.Clone(players)}
+	table.toAct = table.nextPlayer(table.button)
+	return table, nil
+}
+
+// Seating returns who is sitting where. Empty seats are left out.
+func (t *Table) Seating() Seating {
+	seating := Seating{Dealer: t.button + 1}
+	for i, player := range t.seats {
+		if player != "" {
+			seating.Seats = append(seating.Seats, Seat{Number: i + 1, Player: player})
+		}
+	}
+	return seating
+}
+
+// NextHand moves the dealer button to the next player clockwise, skipping empty seats, and
+// returns the new dealer's seat.
+// The first player to act in the new hand is the one after the dealer.
+func (t *Table) NextHand() Seat {
+	t.button = t.nextPlayer(t.button)
+	t.moveAction(t.nextPlayer(t.button))
+	return Seat{Number: t.button + 1, Player: t.seats[t.button]}
+}
+
+// ToAct returns the seat of the player whose turn it is to act.
+func (t *Table) ToAct() Seat {
+	return Seat{Number: t.toAct + 1, Player: t.seats[t.toAct]}
+}
+
+// Act passes the action on from player, whose turn it was, to the next player clockwise.
+func (t *Table) Act(player string) error {
+	if !t.seated(player) {
+		return apperrors.NotFound("player", player, nil)
+	}
+	if t.seats[t.toAct] != player {
+		return apperrors.Invalid("player", "it is not their turn", nil)
+	}
+
+	t.moveAction(t.nextPlayer(t.toAct))
+	return nil
+}
+
+// seated reports whether player is sitting at the table.
+func (t *Table) seated(player string) bool {
+	return player != "" && slices.Contains(t.seats, player)
+}
+
+// players is how many players are still at the table.
+func (t *Table) players() int {
+	n := 0
+	for _, player := range t.seats {
+		if player != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// nextPlayer returns the first occupied seat clockwise from seat, or seat if nobody else is left.
+func (t *Table) nextPlayer(seat int) int {
+	for i := 1; i <= len(t.seats); i++ {
+		next := (seat + i) % len(t.seats)
+		if t.seats[next] != "" {
+			return next
+		}
+	}
+	return seat
+}
+
+func (t *Table) moveAction(seat int) {
+	t.toAct = seat
+	t.turns++
+}
+
+// Eliminate empties the seat of a player who has been knocked out. If they had the dealer
+// button it stays at their empty seat until the next hand, when it moves on as usual. If it was
+// their turn to act, it passes to the next player.
+func (t *Table) Eliminate(player string) error {
+	seat := slices.Index(t.seats, player)
+	if player == "" || seat == -1 {
+		return apperrors.NotFound("player", player, nil)
+	}
+
+	t.seats[seat] = ""
+	if seat == t.toAct {
+		t.moveAction(t.nextPlayer(seat))
+	}
+	return nil
+}
+
+// errBadPlayerInput is returned by parsePlayers for something that's neither a number nor a
+// list of names.
+var errBadPlayerInput = errors.New(BadPlayerInputErrMsg)
+
+// parsePlayers reads what a player entered to start a game: either the number of players, or
+// their names separated by commas, which seats them at a Table.
+func parsePlayers(input string) (numberOfPlayers int, table *Table, err error) {
+	if numberOfPlayers, err := strconv.Atoi(strings.TrimSpace(input)); err == nil {
+		return numberOfPlayers, nil, nil
+	}
+
+	if !strings.Contains(input, ",") {
+		return 0, nil, errBadPlayerInput
+	}
+
+	var players []string
+	for _, name := range strings.Split(input, ",") {
+		players = append(players, strings.TrimSpace(name))
+	}
+
+	table, err = NewTable(players)
+	if err != nil {
+		return 0, nil, err
+	}
+	return len(players), table, nil
+}
+
+// play carries out a command given during a game at the table, reporting whether it was one.
+func (t *Table) play(command string) (ok bool, err error) {
+	if command == NextHandCommand {
+		t.NextHand()
+		return true, nil
+	}
+
+	if player, found := strings.CutSuffix(command, eliminatedSuffix); found {
+		return true, t.Eliminate(player)
+	}
+
+	if player, found := strings.CutSuffix(command, actedSuffix); found {
+		return true, t.Act(player)
+	}
+
+	return false, nil
+}
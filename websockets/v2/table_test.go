@@ -0,0 +1,158 @@
+package poker_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestTable(t *testing.T) {
+	seat := func(number int, player string) poker.Seat {
+		return poker.Seat{Number: number, Player: player}
+	}
+
+	t.Run("players are seated in order with the button on seat 1", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo", "Ruth")
+
+		assertSeating(t, table, poker.Seating{
+			Seats:  []poker.Seat{seat(1, "Chris"), seat(2, "Cleo"), seat(3, "Ruth")},
+			Dealer: 1,
+		})
+	})
+
+	t.Run("the button moves one seat each hand, back round to seat 1", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo", "Ruth")
+
+		for _, want := range []poker.Seat{seat(2, "Cleo"), seat(3, "Ruth"), seat(1, "Chris")} {
+			if got := table.NextHand(); got != want {
+				t.Errorf("got dealer %+v want %+v", got, want)
+			}
+		}
+	})
+
+	t.Run("eliminated players' seats are skipped", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo", "Ruth", "Lloyd")
+		assertNoError(t, table.Eliminate("Cleo"))
+
+		assertSeating(t, table, poker.Seating{
+			Seats:  []poker.Seat{seat(1, "Chris"), seat(3, "Ruth"), seat(4, "Lloyd")},
+			Dealer: 1,
+		})
+
+		if got := table.NextHand(); got != seat(3, "Ruth") {
+			t.Errorf("got dealer %+v want Ruth in seat 3", got)
+		}
+	})
+
+	t.Run("when the dealer is eliminated the button moves on from their empty seat", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo", "Ruth", "Lloyd")
+		table.NextHand()
+		assertNoError(t, table.Eliminate("Cleo"))
+		assertNoError(t, table.Eliminate("Ruth"))
+
+		if got := table.NextHand(); got != seat(4, "Lloyd") {
+			t.Errorf("got dealer %+v want Lloyd in seat 4", got)
+		}
+		if got := table.NextHand(); got != seat(1, "Chris") {
+			t.Errorf("got dealer %+v want Chris in seat 1", got)
+		}
+	})
+
+	t.Run("the last player left keeps the button", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo")
+		assertNoError(t, table.Eliminate("Chris"))
+
+		for range 3 {
+			if got := table.NextHand(); got != seat(2, "Cleo") {
+				t.Errorf("got dealer %+v want Cleo in seat 2", got)
+			}
+		}
+	})
+
+	t.Run("only seated players can be eliminated", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo")
+		assertNoError(t, table.Eliminate("Chris"))
+
+		for _, player := range []string{"Chris", "Ruth", ""} {
+			if err := table.Eliminate(player); !apperrors.IsNotFound(err) {
+				t.Errorf("eliminating %q got %v, want a not found error", player, err)
+			}
+		}
+	})
+
+	t.Run("the player after the dealer acts first, then the action goes round", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo", "Ruth")
+
+		for _, want := range []poker.Seat{seat(2, "Cleo"), seat(3, "Ruth"), seat(1, "Chris"), seat(2, "Cleo")} {
+			got := table.ToAct()
+			if got != want {
+				t.Fatalf("got %+v to act, want %+v", got, want)
+			}
+			assertNoError(t, table.Act(got.Player))
+		}
+	})
+
+	t.Run("each hand starts with the player after the new dealer", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo", "Ruth")
+		assertNoError(t, table.Act("Cleo"))
+
+		table.NextHand()
+
+		if got := table.ToAct(); got != seat(3, "Ruth") {
+			t.Errorf("got %+v to act, want Ruth in seat 3", got)
+		}
+	})
+
+	t.Run("only the player whose turn it is can act", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo", "Ruth")
+
+		if err := table.Act("Ruth"); !apperrors.IsInvalid(err) {
+			t.Errorf("got %v, want an invalid error for acting out of turn", err)
+		}
+		if err := table.Act("Lloyd"); !apperrors.IsNotFound(err) {
+			t.Errorf("got %v, want a not found error for someone not at the table", err)
+		}
+	})
+
+	t.Run("eliminating the player to act passes the action on", func(t *testing.T) {
+		table := mustMakeTable(t, "Chris", "Cleo", "Ruth")
+		assertNoError(t, table.Eliminate("Cleo"))
+
+		if got := table.ToAct(); got != seat(3, "Ruth") {
+			t.Errorf("got %+v to act, want Ruth in seat 3", got)
+		}
+	})
+
+	t.Run("tables need between 2 and MaxSeats players with different names", func(t *testing.T) {
+		tooMany := make([]string, poker.MaxSeats+1)
+		for i := range tooMany {
+			tooMany[i] = string(rune('A' + i))
+		}
+
+		for _, players := range [][]string{{"Chris"}, tooMany, {"Chris", ""}} {
+			if _, err := poker.NewTable(players); !apperrors.IsInvalid(err) {
+				t.Errorf("seating %q got %v, want an invalid error", players, err)
+			}
+		}
+
+		if _, err := poker.NewTable([]string{"Chris", "Cleo", "Chris"}); !apperrors.IsConflict(err) {
+			t.Errorf("got %v, want a conflict for seating Chris twice", err)
+		}
+	})
+}
+
+func mustMakeTable(t testing.TB, players ...string) *poker.Table {
+	t.Helper()
+	table, err := poker.NewTable(players)
+	assertNoError(t, err)
+	return table
+}
+
+func assertSeating(t testing.TB, table *poker.Table, want poker.Seating) {
+	t.Helper()
+	if got := table.Seating(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got seating %+v want %+v", got, want)
+	}
+}
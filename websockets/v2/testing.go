@@ -1,45 +1,126 @@
 package poker
 
 import (
+	"cmp"
 	"fmt"
 	"io"
+	"reflect"
+	"slices"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/quii/learn-go-with-tests/mocking/mock"
 )
 
-// StubPlayerStore implements PlayerStore for testing purposes.
+// StubPlayerStore implements PlayerStore for testing purposes. It is safe to use from the
+// concurrent handlers of a server under test; read WinCalls directly only once they are done.
 type StubPlayerStore struct {
 	Scores   map[string]int
 	WinCalls []string
 	League   []Player
+
+	mu          sync.Mutex
+	leagueCalls int
 }
 
 // GetPlayerScore returns a score from Scores.
 func (s *StubPlayerStore) GetPlayerScore(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	score := s.Scores[name]
 	return score
 }
 
-// RecordWin will record a win to WinCalls.
+// RecordWin will record a win to WinCalls, and add it to the player's score in Scores.
 func (s *StubPlayerStore) RecordWin(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.WinCalls = append(s.WinCalls, name)
+
+	if s.Scores == nil {
+		s.Scores = map[string]int{}
+	}
+	s.Scores[name]++
 }
 
-// GetLeague returns League.
+// GetLeague returns League if it was set, and otherwise the players in Scores, most wins first.
 func (s *StubPlayerStore) GetLeague() League {
-	return s.League
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leagueCalls++
+	return s.league()
+}
+
+func (s *StubPlayerStore) league() League {
+	if s.League != nil {
+		return s.League
+	}
+
+	var league League
+	for name, wins := range s.Scores {
+		league = append(league, Player{Name: name, Wins: wins})
+	}
+	slices.SortFunc(league, func(a, b Player) int {
+		return cmp.Or(cmp.Compare(b.Wins, a.Wins), cmp.Compare(a.Name, b.Name))
+	})
+	return league
+}
+
+// WinCallsFor returns how many times RecordWin was called for name.
+func (s *StubPlayerStore) WinCallsFor(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calls := 0
+	for _, winner := range s.WinCalls {
+		if winner == name {
+			calls++
+		}
+	}
+	return calls
+}
+
+func (s *StubPlayerStore) winCalls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.WinCalls...)
 }
 
 // AssertPlayerWin allows you to spy on the store's calls to RecordWin.
 func AssertPlayerWin(t testing.TB, store *StubPlayerStore, winner string) {
 	t.Helper()
 
-	if len(store.WinCalls) != 1 {
-		t.Fatalf("got %d calls to RecordWin want %d", len(store.WinCalls), 1)
+	winCalls := store.winCalls()
+
+	if len(winCalls) != 1 {
+		t.Fatalf("got %d calls to RecordWin want %d", len(winCalls), 1)
 	}
 
-	if store.WinCalls[0] != winner {
-		t.Errorf("did not store correct winner got %q want %q", store.WinCalls[0], winner)
+	if winCalls[0] != winner {
+		t.Errorf("did not store correct winner got %q want %q", winCalls[0], winner)
+	}
+}
+
+// AssertLeagueServed checks the store was asked for its League and that body is that League as JSON.
+func AssertLeagueServed(t testing.TB, store *StubPlayerStore, body io.Reader) {
+	t.Helper()
+
+	store.mu.Lock()
+	leagueCalls, want := store.leagueCalls, store.league()
+	store.mu.Unlock()
+
+	if leagueCalls == 0 {
+		t.Fatal("expected the League to be fetched from the store but it wasn't")
+	}
+
+	got, err := NewLeague(body)
+	if err != nil {
+		t.Fatalf("could not read League that was served, %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("served League %v want %v", got, want)
 	}
 }
 
@@ -62,3 +143,178 @@ type SpyBlindAlerter struct {
 func (s *SpyBlindAlerter) ScheduleAlertAt(at time.Duration, amount int, to io.Writer) {
 	s.Alerts = append(s.Alerts, ScheduledAlert{at, amount})
 }
+
+// spyTimeout is how long GameSpy waits for a game to start or finish before failing the test.
+const spyTimeout = time.Second
+
+// GameSpy is a Game which records how it was played on a mock.Recorder, so tests can also look at
+// its Calls. The Recorder is kept to itself, as without a test it can't make assertions. It is
+// safe to use from the concurrent handlers of a server under test, and WaitForStart and
+// WaitForFinish block until the game has got that far, so tests don't have to poll it. The zero
+// value is ready to use.
+type GameSpy struct {
+	recorder mock.Recorder
+
+	// BlindAlert is written to the alerts destination every time the game is started.
+	BlindAlert []byte
+	// Stacks are the Standings, and StackErr is returned from every change to a stack.
+	Stacks   []Stack
+	StackErr error
+
+	mu       sync.Mutex
+	started  chan struct{}
+	finished chan struct{}
+}
+
+// StackChange is a change to a player's stack made through a GameSpy. Change is what the
+// command to make it says, such as "rebuys".
+type StackChange struct {
+	Change string
+	Player string
+	Chips  int
+}
+
+// stackChanges are what the commands calling each of a StackKeeper's methods say.
+var stackChanges = map[string]string{
+	"BuyIn":       "buys in",
+	"Rebuy":       "rebuys",
+	"AddOn":       "adds on",
+	"UpdateStack": "has",
+}
+
+// Start records the number of players and sends BlindAlert.
+func (g *GameSpy) Start(numberOfPlayers int, alertsDestination io.Writer) {
+	g.mu.Lock()
+	g.recorder.Record("Start", numberOfPlayers)
+	if len(g.recorder.CallsTo("Start")) == 1 {
+		close(g.signals().started)
+	}
+	g.mu.Unlock()
+
+	alertsDestination.Write(g.BlindAlert)
+}
+
+// Finish records the winner.
+func (g *GameSpy) Finish(winner string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.recorder.Record("Finish", winner)
+	if len(g.recorder.CallsTo("Finish")) == 1 {
+		close(g.signals().finished)
+	}
+}
+
+// BuyIn records the buy-in.
+func (g *GameSpy) BuyIn(player string, chips int) error {
+	return g.changeStack("BuyIn", player, chips)
+}
+
+// Rebuy records the rebuy.
+func (g *GameSpy) Rebuy(player string, chips int) error {
+	return g.changeStack("Rebuy", player, chips)
+}
+
+// AddOn records the add-on.
+func (g *GameSpy) AddOn(player string, chips int) error {
+	return g.changeStack("AddOn", player, chips)
+}
+
+// UpdateStack records the new stack.
+func (g *GameSpy) UpdateStack(player string, chips int) error {
+	return g.changeStack("UpdateStack", player, chips)
+}
+
+// Standings returns Stacks.
+func (g *GameSpy) Standings() []Stack {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return slices.Clone(g.Stacks)
+}
+
+func (g *GameSpy) changeStack(method, player string, chips int) error {
+	g.recorder.Record(method, player, chips)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.StackErr
+}
+
+// StackChanges returns every change made to a stack so far, in order.
+func (g *GameSpy) StackChanges() []StackChange {
+	var changes []StackChange
+	for _, call := range g.recorder.Calls() {
+		if change, ok := stackChanges[call.Method]; ok {
+			changes = append(changes, StackChange{Change: change, Player: call.Args[0].(string), Chips: call.Args[1].(int)})
+		}
+	}
+	return changes
+}
+
+// Calls returns every call made to the game so far, in order.
+func (g *GameSpy) Calls() []mock.Call {
+	return g.recorder.Calls()
+}
+
+// StartCalls returns the number of players of every game started so far.
+func (g *GameSpy) StartCalls() []int {
+	var players []int
+	for _, call := range g.recorder.CallsTo("Start") {
+		players = append(players, call.Args[0].(int))
+	}
+	return players
+}
+
+// FinishCalls returns the winner of every game finished so far.
+func (g *GameSpy) FinishCalls() []string {
+	var winners []string
+	for _, call := range g.recorder.CallsTo("Finish") {
+		winners = append(winners, call.Args[0].(string))
+	}
+	return winners
+}
+
+// WaitForStart waits for the game to be started, returning the number of players it was
+// started with. It stops the test if the game isn't started within a second.
+func (g *GameSpy) WaitForStart(t testing.TB) int {
+	t.Helper()
+
+	g.mu.Lock()
+	started := g.signals().started
+	g.mu.Unlock()
+
+	select {
+	case <-started:
+		return g.StartCalls()[0]
+	case <-time.After(spyTimeout):
+		t.Fatalf("game was not started within %v", spyTimeout)
+		return 0
+	}
+}
+
+// WaitForFinish waits for the game to be finished, returning the winner. It stops the test if
+// the game isn't finished within a second.
+func (g *GameSpy) WaitForFinish(t testing.TB) string {
+	t.Helper()
+
+	g.mu.Lock()
+	finished := g.signals().finished
+	g.mu.Unlock()
+
+	select {
+	case <-finished:
+		return g.FinishCalls()[0]
+	case <-time.After(spyTimeout):
+		t.Fatalf("game was not finished within %v", spyTimeout)
+		return ""
+	}
+}
+
+// signals makes the channels closed on the first Start and Finish. It must be called with mu held.
+func (g *GameSpy) signals() *GameSpy {
+	if g.started == nil {
+		g.started = make(chan struct{})
+		g.finished = make(chan struct{})
+	}
+	return g
+}
@@ -0,0 +1,129 @@
+package poker_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/mocking/mock"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestStubPlayerStore(t *testing.T) {
+	t.Run("is safe for concurrent use by a server", func(t *testing.T) {
+		store := &poker.StubPlayerStore{
+			Scores: map[string]int{"Pepper": 3},
+			League: []poker.Player{{Name: "Pepper", Wins: 3}},
+		}
+		server := mustMakePlayerServer(t, store, dummyGame)
+
+		const requests = 50
+		var wg sync.WaitGroup
+		wg.Add(requests * 3)
+
+		for i := 0; i < requests; i++ {
+			go func() {
+				defer wg.Done()
+				server.ServeHTTP(httptest.NewRecorder(), newPostWinRequest("Pepper"))
+			}()
+			go func() {
+				defer wg.Done()
+				server.ServeHTTP(httptest.NewRecorder(), newGetScoreRequest("Pepper"))
+			}()
+			go func() {
+				defer wg.Done()
+				server.ServeHTTP(httptest.NewRecorder(), newLeagueRequest())
+			}()
+		}
+		wg.Wait()
+
+		if got := store.WinCallsFor("Pepper"); got != requests {
+			t.Errorf("got %d wins for Pepper want %d", got, requests)
+		}
+	})
+
+	t.Run("WinCallsFor counts wins per player", func(t *testing.T) {
+		store := &poker.StubPlayerStore{}
+		store.RecordWin("Chris")
+		store.RecordWin("Cleo")
+		store.RecordWin("Chris")
+
+		if got := store.WinCallsFor("Chris"); got != 2 {
+			t.Errorf("got %d wins for Chris want 2", got)
+		}
+		if got := store.WinCallsFor("Ruth"); got != 0 {
+			t.Errorf("got %d wins for Ruth want 0", got)
+		}
+	})
+
+	t.Run("AssertLeagueServed checks the league response", func(t *testing.T) {
+		store := &poker.StubPlayerStore{League: []poker.Player{{Name: "Cleo", Wins: 32}}}
+		server := mustMakePlayerServer(t, store, dummyGame)
+
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newLeagueRequest())
+
+		assertStatus(t, response, http.StatusOK)
+		poker.AssertLeagueServed(t, store, response.Body)
+	})
+}
+
+func TestGameSpy(t *testing.T) {
+	t.Run("waits for a game played on another goroutine", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		go func() {
+			game.Start(4, io.Discard)
+			game.Finish("Cleo")
+		}()
+
+		if got := game.WaitForStart(t); got != 4 {
+			t.Errorf("got %d players want 4", got)
+		}
+		if got := game.WaitForFinish(t); got != "Cleo" {
+			t.Errorf("got winner %q want Cleo", got)
+		}
+	})
+
+	t.Run("records every game, waiting for the first", func(t *testing.T) {
+		game := &poker.GameSpy{}
+		game.Start(3, io.Discard)
+		game.Start(5, io.Discard)
+
+		if got := game.WaitForStart(t); got != 3 {
+			t.Errorf("got %d players want 3", got)
+		}
+		if got := game.StartCalls(); !reflect.DeepEqual(got, []int{3, 5}) {
+			t.Errorf("got start calls %v want [3 5]", got)
+		}
+		if got := game.FinishCalls(); len(got) != 0 {
+			t.Errorf("got finish calls %v want none", got)
+		}
+	})
+
+	t.Run("sends its blind alert when started", func(t *testing.T) {
+		game := &poker.GameSpy{BlindAlert: []byte("Blind is now 100\n")}
+		out := &bytes.Buffer{}
+
+		game.Start(2, out)
+
+		assertMessagesSentToUser(t, out, "Blind is now 100\n")
+	})
+
+	t.Run("lists every call made to it", func(t *testing.T) {
+		game := &poker.GameSpy{}
+
+		game.Start(2, io.Discard)
+		game.BuyIn("Chris", 1000)
+		game.Finish("Chris")
+
+		want := []mock.Call{{Method: "Start", Args: []any{2}}, {Method: "BuyIn", Args: []any{"Chris", 1000}}, {Method: "Finish", Args: []any{"Chris"}}}
+		if got := game.Calls(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got calls %v want %v", got, want)
+		}
+	})
+}
@@ -2,36 +2,160 @@ package poker
 
 import (
 	"io"
+	"log"
+	"sync"
 	"time"
 )
 
-// TexasHoldem manages a game of poker.
+// TexasHoldem manages a game of poker. It runs one table, so GameState describes the game
+// most recently started. NewGame makes another TexasHoldem to play a game at the same time.
 type TexasHoldem struct {
-	alerter BlindAlerter
-	store   PlayerStore
+	alerter     BlindAlerter
+	store       PlayerStore
+	games       GameStore
+	webhooks    *Webhooks
+	now         func() time.Time
+	rebuyPeriod time.Duration
+	onError     func(err error)
+
+	lock      sync.RWMutex
+	players   int
+	startedAt time.Time
+	schedule  []ScheduledAlert
+	stacks    map[string]Stack
+}
+
+// TexasHoldemOption configures optional behaviour of a TexasHoldem.
+type TexasHoldemOption func(*TexasHoldem)
+
+// WithClock replaces time.Now as the way TexasHoldem tells how long a game has been going, and
+// when games start and finish.
+func WithClock(now func() time.Time) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.now = now
+	}
+}
+
+// WithGameErrorHandler is told when a finished game couldn't be recorded in the GameStore,
+// instead of it being written to the standard logger.
+func WithGameErrorHandler(onError func(err error)) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.onError = onError
+	}
 }
 
 // NewTexasHoldem returns a new game.
-func NewTexasHoldem(alerter BlindAlerter, store PlayerStore) *TexasHoldem {
-	return &TexasHoldem{
+func NewTexasHoldem(alerter BlindAlerter, store PlayerStore, options ...TexasHoldemOption) *TexasHoldem {
+	game := &TexasHoldem{
 		alerter: alerter,
 		store:   store,
+		now:     time.Now,
+		onError: func(err error) {
+			log.Printf("problem recording game, %v", err)
+		},
+	}
+
+	for _, option := range options {
+		option(game)
+	}
+
+	return game
+}
+
+// NewGame returns a TexasHoldem with the same alerter, stores, webhooks and options, which
+// keeps the state of the game it plays to itself.
+func (p *TexasHoldem) NewGame() Game {
+	return &TexasHoldem{
+		alerter:     p.alerter,
+		store:       p.store,
+		games:       p.games,
+		webhooks:    p.webhooks,
+		now:         p.now,
+		rebuyPeriod: p.rebuyPeriod,
+		onError:     p.onError,
 	}
 }
 
+// GameState is a snapshot of a game in progress.
+type GameState struct {
+	Players int           `json:"players"`
+	Blind   int           `json:"blind"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
 // Start will schedule blind alerts dependant on the number of players.
 func (p *TexasHoldem) Start(numberOfPlayers int, alertsDestination io.Writer) {
+	schedule := blindSchedule(numberOfPlayers)
+
+	p.lock.Lock()
+	p.players = numberOfPlayers
+	p.startedAt = p.now()
+	p.schedule = schedule
+	p.stacks = map[string]Stack{}
+	p.lock.Unlock()
+
+	for _, alert := range schedule {
+		p.alerter.ScheduleAlertAt(alert.At, alert.Amount, alertsDestination)
+	}
+}
+
+// Finish ends the game, recording the winner. If there is a GameStore the game is recorded
+// there too, unless it was never started. The win counts even if the game can't be recorded, and
+// the error goes to the error handler. Any webhooks are then told who won, along with the league as it now stands.
+func (p *TexasHoldem) Finish(winner string) {
+	p.store.RecordWin(winner)
+
+	p.lock.Lock()
+	game := GameRecord{Winner: winner, Players: p.players, StartedAt: p.startedAt, FinishedAt: p.now()}
+	p.players, p.startedAt, p.schedule, p.stacks = 0, time.Time{}, nil, nil
+	p.lock.Unlock()
+
+	if p.games != nil && !game.StartedAt.IsZero() {
+		if err := p.games.RecordGame(game); err != nil {
+			p.onError(err)
+		}
+	}
+
+	if p.webhooks != nil {
+		event := GameFinished{Winner: winner, League: p.store.GetLeague()}
+		if !game.StartedAt.IsZero() {
+			event.Duration = game.Duration()
+		}
+		p.webhooks.Notify(event)
+	}
+}
+
+// GameState returns how many players are in the current game, how long it has been going and
+// what the blind is now. It is the zero GameState if no game is being played.
+func (p *TexasHoldem) GameState() GameState {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.schedule == nil {
+		return GameState{}
+	}
+
+	state := GameState{Players: p.players, Elapsed: p.now().Sub(p.startedAt)}
+	for _, alert := range p.schedule {
+		if alert.At > state.Elapsed {
+			break
+		}
+		state.Blind = alert.Amount
+	}
+
+	return state
+}
+
+func blindSchedule(numberOfPlayers int) []ScheduledAlert {
 	blindIncrement := time.Duration(5+numberOfPlayers) * time.Minute
 
 	blinds := []int{100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000}
+	schedule := make([]ScheduledAlert, len(blinds))
 	blindTime := 0 * time.Second
-	for _, blind := range blinds {
-		p.alerter.ScheduleAlertAt(blindTime, blind, alertsDestination)
+	for i, blind := range blinds {
+		schedule[i] = ScheduledAlert{At: blindTime, Amount: blind}
 		blindTime = blindTime + blindIncrement
 	}
-}
 
-// Finish ends the game, recording the winner.
-func (p *TexasHoldem) Finish(winner string) {
-	p.store.RecordWin(winner)
+	return schedule
 }
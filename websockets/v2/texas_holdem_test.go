@@ -1,8 +1,11 @@
 package poker_test
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"testing"
 	"time"
 
@@ -51,6 +54,28 @@ func TestGame_Start(t *testing.T) {
 
 }
 
+func TestGame_GameState(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)}
+	game := poker.NewTexasHoldem(dummyBlindAlerter, &poker.StubPlayerStore{}, poker.WithClock(clock.Now))
+
+	assertGameState(t, game.GameState(), poker.GameState{})
+
+	game.Start(3, io.Discard)
+	assertGameState(t, game.GameState(), poker.GameState{Players: 3, Blind: 100})
+
+	clock.advance(7*time.Minute + 59*time.Second)
+	assertGameState(t, game.GameState(), poker.GameState{Players: 3, Blind: 100, Elapsed: 7*time.Minute + 59*time.Second})
+
+	clock.advance(time.Second)
+	assertGameState(t, game.GameState(), poker.GameState{Players: 3, Blind: 200, Elapsed: 8 * time.Minute})
+
+	clock.advance(10 * time.Hour)
+	assertGameState(t, game.GameState(), poker.GameState{Players: 3, Blind: 8000, Elapsed: 10*time.Hour + 8*time.Minute})
+
+	game.Finish("Ruth")
+	assertGameState(t, game.GameState(), poker.GameState{})
+}
+
 func TestGame_Finish(t *testing.T) {
 	store := &poker.StubPlayerStore{}
 	game := poker.NewTexasHoldem(dummyBlindAlerter, store)
@@ -60,6 +85,76 @@ func TestGame_Finish(t *testing.T) {
 	poker.AssertPlayerWin(t, store, winner)
 }
 
+func TestGame_RecordsGames(t *testing.T) {
+	t.Run("records when a finished game started and finished", func(t *testing.T) {
+		start := time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)
+		clock := &fakeClock{now: start}
+		games, _ := poker.NewGameLog(&bytes.Buffer{})
+		game := poker.NewTexasHoldem(dummyBlindAlerter, dummyPlayerStore, poker.WithClock(clock.Now), poker.WithGameStore(games))
+
+		game.Start(5, io.Discard)
+		clock.advance(45 * time.Minute)
+		game.Finish("Ruth")
+
+		want := []poker.GameRecord{{Winner: "Ruth", Players: 5, StartedAt: start, FinishedAt: start.Add(45 * time.Minute)}}
+		assertGames(t, games.Games(), want)
+
+		if got := games.Games()[0].Duration(); got != 45*time.Minute {
+			t.Errorf("got duration %v want 45m", got)
+		}
+	})
+
+	t.Run("a game that was never started isn't recorded, but the win is", func(t *testing.T) {
+		store := &poker.StubPlayerStore{}
+		games, _ := poker.NewGameLog(&bytes.Buffer{})
+		game := poker.NewTexasHoldem(dummyBlindAlerter, store, poker.WithGameStore(games))
+
+		game.Finish("Ruth")
+
+		poker.AssertPlayerWin(t, store, "Ruth")
+		assertGames(t, games.Games(), nil)
+	})
+
+	t.Run("a game that can't be recorded is reported, but the win still counts", func(t *testing.T) {
+		store := &poker.StubPlayerStore{}
+		var reported error
+		game := poker.NewTexasHoldem(dummyBlindAlerter, store,
+			poker.WithGameStore(failingGameStore{}),
+			poker.WithGameErrorHandler(func(err error) { reported = err }),
+		)
+
+		game.Start(5, io.Discard)
+		game.Finish("Ruth")
+
+		poker.AssertPlayerWin(t, store, "Ruth")
+		if reported == nil {
+			t.Error("expected the error recording the game to be reported")
+		}
+	})
+
+	t.Run("games made from it report errors the same way", func(t *testing.T) {
+		var reported error
+		game := poker.NewTexasHoldem(dummyBlindAlerter, &poker.StubPlayerStore{},
+			poker.WithGameStore(failingGameStore{}),
+			poker.WithGameErrorHandler(func(err error) { reported = err }),
+		).NewGame()
+
+		game.Start(5, io.Discard)
+		game.Finish("Ruth")
+
+		if reported == nil {
+			t.Error("expected the error recording the game to be reported")
+		}
+	})
+}
+
+// failingGameStore is a GameStore with no history which can't record any more games.
+type failingGameStore struct{}
+
+func (failingGameStore) Games() []poker.GameRecord { return nil }
+
+func (failingGameStore) RecordGame(poker.GameRecord) error { return errors.New("disk full") }
+
 func checkSchedulingCases(cases []poker.ScheduledAlert, t *testing.T, blindAlerter *poker.SpyBlindAlerter) {
 	for i, want := range cases {
 		t.Run(fmt.Sprint(want), func(t *testing.T) {
@@ -73,3 +168,71 @@ func checkSchedulingCases(cases []poker.ScheduledAlert, t *testing.T, blindAlert
 		})
 	}
 }
+
+func assertGameState(t testing.TB, got, want poker.GameState) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got game state %+v want %+v", got, want)
+	}
+}
+
+// fakeClock only moves when it is advanced, running any functions that have become due on the way.
+type fakeClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at   time.Time
+	f    func()
+	done bool
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) AfterFunc(d time.Duration, fn func()) func() bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	timer := &fakeTimer{at: f.now.Add(d), f: fn}
+	f.timers = append(f.timers, timer)
+
+	return func() bool {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		stopped := !timer.done
+		timer.done = true
+		return stopped
+	}
+}
+
+func (f *fakeClock) advance(d time.Duration) {
+	f.lock.Lock()
+	until := f.now.Add(d)
+	f.lock.Unlock()
+
+	for {
+		f.lock.Lock()
+		var next *fakeTimer
+		for _, timer := range f.timers {
+			if !timer.done && !timer.at.After(until) && (next == nil || timer.at.Before(next.at)) {
+				next = timer
+			}
+		}
+		if next == nil {
+			f.now = until
+			f.lock.Unlock()
+			return
+		}
+		f.now, next.done = next.at, true
+		f.lock.Unlock()
+
+		// run without the lock, as the function may well start timers of its own
+		next.f()
+	}
+}
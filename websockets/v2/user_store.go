@@ -0,0 +1,215 @@
+package poker
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+)
+
+const (
+	passwordSaltLength     = 16
+	passwordHashLength     = 32
+	passwordHashIterations = 600_000
+	minPasswordLength      = 8
+)
+
+// User is someone with an account who can log in to play.
+type User struct {
+	Name         string
+	Salt         []byte
+	PasswordHash []byte
+	// Iterations is how many rounds of PBKDF2 made PasswordHash. Users saved before it was
+	// recorded have 0, which means the default of 600,000.
+	Iterations int `json:",omitempty"`
+}
+
+// UserOption changes how NewUser makes a User.
+type UserOption func(*User)
+
+// WithHashIterations makes NewUser hash the password with n rounds of PBKDF2 rather than 600,000.
+// Fewer rounds are quicker to check but quicker to guess too, so it is meant for tests.
+func WithHashIterations(n int) UserOption {
+	return func(u *User) {
+		u.Iterations = n
+	}
+}
+
+// NewUser creates a User called name, storing a salted hash of password rather than the password itself.
+func NewUser(name, password string, options ...UserOption) (User, error) {
+	if name == "" {
+		return User{}, apperrors.Invalid("name", "must not be empty", nil)
+	}
+
+	if len(password) < minPasswordLength {
+		return User{}, apperrors.Invalid("password", fmt.Sprintf("must be at least %d characters", minPasswordLength), nil)
+	}
+
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return User{}, fmt.Errorf("problem generating salt, %v", err)
+	}
+
+	user := User{Name: name, Salt: salt}
+	for _, option := range options {
+		option(&user)
+	}
+
+	hash, err := hashPassword(password, salt, user.iterations())
+	if err != nil {
+		return User{}, err
+	}
+	user.PasswordHash = hash
+
+	return user, nil
+}
+
+// PasswordMatches reports whether password is the one the user registered with.
+func (u User) PasswordMatches(password string) bool {
+	hash, err := hashPassword(password, u.Salt, u.iterations())
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(hash, u.PasswordHash) == 1
+}
+
+func (u User) iterations() int {
+	if u.Iterations == 0 {
+		return passwordHashIterations
+	}
+	return u.Iterations
+}
+
+// dummyUser has a password nobody can match. Checking it takes as long as checking a real
+// user's, so a login for someone who doesn't exist can't be told apart by how long it takes.
+func dummyUser(options ...UserOption) User {
+	user := User{Salt: make([]byte, passwordSaltLength), PasswordHash: make([]byte, passwordHashLength)}
+	for _, option := range options {
+		option(&user)
+	}
+	return user
+}
+
+func hashPassword(password string, salt []byte, iterations int) ([]byte, error) {
+	hash, err := pbkdf2.Key(sha256.New, password, salt, iterations, passwordHashLength)
+	if err != nil {
+		return nil, fmt.Errorf("problem hashing password, %v", err)
+	}
+	return hash, nil
+}
+
+// UserStore stores the accounts of people who play.
+type UserStore interface {
+	// AddUser stores user, returning a Conflict error if the name is taken.
+	AddUser(user User) error
+	// GetUser returns the user called name, or a NotFound error.
+	GetUser(name string) (User, error)
+}
+
+// InMemoryUserStore keeps users in memory, so they are forgotten when the program stops.
+type InMemoryUserStore struct {
+	lock  sync.RWMutex
+	users map[string]User
+}
+
+// NewInMemoryUserStore creates an empty InMemoryUserStore.
+func NewInMemoryUserStore() *InMemoryUserStore {
+	return &InMemoryUserStore{users: map[string]User{}}
+}
+
+// AddUser stores user if nobody else has the name.
+func (i *InMemoryUserStore) AddUser(user User) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if _, exists := i.users[user.Name]; exists {
+		return apperrors.Conflict("user", user.Name, nil)
+	}
+	i.users[user.Name] = user
+	return nil
+}
+
+// GetUser finds the user called name.
+func (i *InMemoryUserStore) GetUser(name string) (User, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	user, ok := i.users[name]
+	if !ok {
+		return User{}, apperrors.NotFound("user", name, nil)
+	}
+	return user, nil
+}
+
+// FileSystemUserStore keeps users in a JSON file, rewriting it whenever one is added.
+type FileSystemUserStore struct {
+	database io.Writer
+	memory   *InMemoryUserStore
+	lock     sync.Mutex
+}
+
+// NewFileSystemUserStore creates a FileSystemUserStore from file, which may be empty.
+func NewFileSystemUserStore(file *os.File) (*FileSystemUserStore, error) {
+	store := &FileSystemUserStore{database: &Tape{file}, memory: NewInMemoryUserStore()}
+
+	file.Seek(0, io.SeekStart)
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("problem getting file info from file %s, %v", file.Name(), err)
+	}
+
+	if info.Size() == 0 {
+		return store, nil
+	}
+
+	var users []User
+	if err := json.NewDecoder(file).Decode(&users); err != nil {
+		return nil, fmt.Errorf("problem loading user store from file %s, %v", file.Name(), err)
+	}
+
+	for _, user := range users {
+		store.memory.users[user.Name] = user
+	}
+
+	return store, nil
+}
+
+// AddUser stores user if nobody else has the name, and writes every user to the file.
+func (f *FileSystemUserStore) AddUser(user User) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if err := f.memory.AddUser(user); err != nil {
+		return err
+	}
+
+	users := make([]User, 0, len(f.memory.users))
+	for _, u := range f.memory.users {
+		users = append(users, u)
+	}
+
+	// Tape rewrites the file on every Write, so encode the users before writing them in one go
+	encoded, err := json.Marshal(users)
+	if err != nil {
+		return fmt.Errorf("problem encoding users, %v", err)
+	}
+
+	if _, err := f.database.Write(encoded); err != nil {
+		return fmt.Errorf("problem writing users, %v", err)
+	}
+
+	return nil
+}
+
+// GetUser finds the user called name.
+func (f *FileSystemUserStore) GetUser(name string) (User, error) {
+	return f.memory.GetUser(name)
+}
@@ -0,0 +1,139 @@
+package poker_test
+
+import (
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestNewUser(t *testing.T) {
+	t.Run("stores a salted hash rather than the password", func(t *testing.T) {
+		first := mustMakeUser(t, "Chris", "correct horse")
+		second := mustMakeUser(t, "Cleo", "correct horse")
+
+		if string(first.PasswordHash) == "correct horse" {
+			t.Error("password was stored in plain text")
+		}
+		if string(first.PasswordHash) == string(second.PasswordHash) {
+			t.Error("expected the same password to hash differently for different users")
+		}
+	})
+
+	t.Run("keeps how many rounds the password was hashed with", func(t *testing.T) {
+		user := mustMakeUser(t, "Chris", "correct horse")
+
+		if user.Iterations != 1_000 {
+			t.Errorf("got %d rounds want 1000", user.Iterations)
+		}
+	})
+
+	t.Run("only the registered password matches", func(t *testing.T) {
+		user := mustMakeUser(t, "Chris", "correct horse")
+
+		if !user.PasswordMatches("correct horse") {
+			t.Error("expected the registered password to match")
+		}
+		if user.PasswordMatches("battery staple") {
+			t.Error("did not expect a different password to match")
+		}
+	})
+
+	invalid := []struct {
+		name, user, password string
+	}{
+		{"empty names", "", "correct horse"},
+		{"short passwords", "Chris", "hunter2"},
+	}
+
+	for _, c := range invalid {
+		t.Run("rejects "+c.name, func(t *testing.T) {
+			_, err := poker.NewUser(c.user, c.password)
+
+			if !apperrors.IsInvalid(err) {
+				t.Errorf("got error %v want an invalid error", err)
+			}
+		})
+	}
+}
+
+func TestUserStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) poker.UserStore{
+		"in memory": func(t *testing.T) poker.UserStore {
+			return poker.NewInMemoryUserStore()
+		},
+		"file system": func(t *testing.T) poker.UserStore {
+			database, cleanDatabase := createTempFile(t, "")
+			t.Cleanup(cleanDatabase)
+
+			store, err := poker.NewFileSystemUserStore(database)
+			assertNoError(t, err)
+			return store
+		},
+	}
+
+	user := mustMakeUser(t, "Chris", "correct horse")
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			t.Run("users can be added and got", func(t *testing.T) {
+				store := newStore(t)
+				assertNoError(t, store.AddUser(user))
+
+				got, err := store.GetUser("Chris")
+				assertNoError(t, err)
+
+				if !got.PasswordMatches("correct horse") {
+					t.Error("stored user's password does not match")
+				}
+			})
+
+			t.Run("names are unique", func(t *testing.T) {
+				store := newStore(t)
+				assertNoError(t, store.AddUser(user))
+
+				if err := store.AddUser(user); !apperrors.IsConflict(err) {
+					t.Errorf("got error %v want a conflict", err)
+				}
+			})
+
+			t.Run("unknown users are not found", func(t *testing.T) {
+				_, err := newStore(t).GetUser("Apollo")
+
+				if !apperrors.IsNotFound(err) {
+					t.Errorf("got error %v want not found", err)
+				}
+			})
+		})
+	}
+
+	t.Run("file system users are still there when reopened", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, "")
+		defer cleanDatabase()
+
+		store, err := poker.NewFileSystemUserStore(database)
+		assertNoError(t, err)
+		assertNoError(t, store.AddUser(user))
+
+		reopened, err := poker.NewFileSystemUserStore(database)
+		assertNoError(t, err)
+
+		got, err := reopened.GetUser("Chris")
+		assertNoError(t, err)
+		if !got.PasswordMatches("correct horse") {
+			t.Error("reloaded user's password does not match")
+		}
+	})
+}
+
+// fastHashing makes passwords quick to hash, so tests don't spend their time on it.
+var fastHashing = poker.WithHashIterations(1_000)
+
+func mustMakeUser(t testing.TB, name, password string) poker.User {
+	t.Helper()
+	user, err := poker.NewUser(name, password, fastHashing)
+	if err != nil {
+		t.Fatalf("could not make user %q, %v", name, err)
+	}
+	return user
+}
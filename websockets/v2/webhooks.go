@@ -0,0 +1,198 @@
+package poker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+)
+
+// GameFinished is sent to webhooks, as JSON, whenever a game finishes.
+type GameFinished struct {
+	Winner   string        `json:"winner"`
+	Duration time.Duration `json:"duration"`
+	League   League        `json:"league"`
+}
+
+// Defaults for delivering to webhooks, which can be changed WithWebhookRetries.
+const (
+	defaultWebhookAttempts = 4
+	defaultWebhookBackoff  = 500 * time.Millisecond
+)
+
+// Webhooks tells other services when games finish, by POSTing a GameFinished to every URL
+// registered with it. Deliveries that fail are retried, waiting twice as long each time.
+type Webhooks struct {
+	client   *http.Client
+	sleep    func(time.Duration)
+	attempts int
+	backoff  time.Duration
+	onError  func(url string, err error)
+
+	lock       sync.RWMutex
+	urls       []string
+	deliveries sync.WaitGroup
+}
+
+// WebhookOption configures optional behaviour of Webhooks.
+type WebhookOption func(*Webhooks)
+
+// WithWebhookClient replaces the client webhooks are sent with, so they can time out sooner.
+func WithWebhookClient(client *http.Client) WebhookOption {
+	return func(w *Webhooks) {
+		w.client = client
+	}
+}
+
+// WithWebhookSleeper replaces time.Sleep as the way Webhooks waits before trying again.
+func WithWebhookSleeper(sleep func(time.Duration)) WebhookOption {
+	return func(w *Webhooks) {
+		w.sleep = sleep
+	}
+}
+
+// WithWebhookRetries sets how many times a webhook is tried before giving up, and how long to
+// wait before the first retry.
+func WithWebhookRetries(attempts int, backoff time.Duration) WebhookOption {
+	return func(w *Webhooks) {
+		w.attempts = attempts
+		w.backoff = backoff
+	}
+}
+
+// WithWebhookErrorHandler is told about every webhook which couldn't be delivered. Otherwise
+// failed deliveries go unnoticed, as nobody is waiting for them.
+func WithWebhookErrorHandler(onError func(url string, err error)) WebhookOption {
+	return func(w *Webhooks) {
+		w.onError = onError
+	}
+}
+
+// NewWebhooks creates Webhooks with no URLs registered.
+func NewWebhooks(options ...WebhookOption) *Webhooks {
+	w := &Webhooks{
+		client:   &http.Client{Timeout: 5 * time.Second},
+		sleep:    time.Sleep,
+		attempts: defaultWebhookAttempts,
+		backoff:  defaultWebhookBackoff,
+		onError:  func(string, error) {},
+	}
+
+	for _, option := range options {
+		option(w)
+	}
+
+	return w
+}
+
+// WithWebhooks sends webhooks whenever TexasHoldem finishes a game.
+func WithWebhooks(webhooks *Webhooks) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.webhooks = webhooks
+	}
+}
+
+// Register adds a URL to send webhooks to.
+func (w *Webhooks) Register(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return apperrors.Invalid("url", "must be an http or https URL", err)
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	w.urls = append(w.urls, rawURL)
+	return nil
+}
+
+// Notify sends event to every registered URL in the background, so a slow or failing service
+// doesn't hold up the game. Wait blocks until the deliveries are done.
+func (w *Webhooks) Notify(event GameFinished) {
+	w.lock.RLock()
+	urls := slices.Clone(w.urls)
+	w.lock.RUnlock()
+
+	for _, u := range urls {
+		w.deliveries.Add(1)
+		go func() {
+			defer w.deliveries.Done()
+			if err := w.Deliver(context.Background(), u, event); err != nil {
+				w.onError(u, err)
+			}
+		}()
+	}
+}
+
+// Wait blocks until every webhook sent by Notify has been delivered or given up on, or ctx is done.
+func (w *Webhooks) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		w.deliveries.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// errPermanent marks a failed delivery which trying again won't fix.
+var errPermanent = errors.New("not retrying")
+
+// Deliver POSTs event to url, trying again after a server error or when the service can't be
+// reached, up to the number of attempts Webhooks was configured with.
+func (w *Webhooks) Deliver(ctx context.Context, url string, event GameFinished) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("problem encoding webhook, %w", err)
+	}
+
+	backoff := w.backoff
+	for attempt := 1; ; attempt++ {
+		err = w.post(ctx, url, body)
+		if err == nil || errors.Is(err, errPermanent) || attempt >= w.attempts || ctx.Err() != nil {
+			break
+		}
+
+		w.sleep(backoff)
+		backoff *= 2
+	}
+
+	if err != nil {
+		return fmt.Errorf("problem delivering webhook to %s, %w", url, err)
+	}
+	return nil
+}
+
+func (w *Webhooks) post(ctx context.Context, url string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %w", errPermanent, err)
+	}
+	request.Header.Set("content-type", jsonContentType)
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	switch {
+	case response.StatusCode >= http.StatusInternalServerError, response.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("got status %d", response.StatusCode)
+	case response.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("%w: got status %d", errPermanent, response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package poker_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quii/learn-go-with-tests/mocking/mock"
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestWebhooks(t *testing.T) {
+	event := poker.GameFinished{Winner: "Cleo", Duration: time.Hour, League: poker.League{{Name: "Cleo", Wins: 3}}}
+
+	t.Run("every registered URL is told when a game finishes", func(t *testing.T) {
+		first, second := newWebhookReceiver(t), newWebhookReceiver(t)
+
+		webhooks := poker.NewWebhooks()
+		assertNoError(t, webhooks.Register(first.URL))
+		assertNoError(t, webhooks.Register(second.URL))
+
+		store := &poker.StubPlayerStore{League: poker.League{{Name: "Cleo", Wins: 3}, {Name: "Chris", Wins: 1}}}
+		clock := &fakeClock{now: time.Date(2026, time.March, 1, 20, 0, 0, 0, time.UTC)}
+		game := poker.NewTexasHoldem(dummyBlindAlerter, store, poker.WithClock(clock.Now), poker.WithWebhooks(webhooks))
+
+		game.Start(3, dummyStdOut)
+		clock.advance(45 * time.Minute)
+		game.Finish("Cleo")
+		assertNoError(t, webhooks.Wait(context.Background()))
+
+		want := poker.GameFinished{Winner: "Cleo", Duration: 45 * time.Minute, League: store.League}
+		assertWebhooksReceived(t, first, want)
+		assertWebhooksReceived(t, second, want)
+	})
+
+	t.Run("retries server errors, waiting longer each time", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+		sleeper := &mock.SpyTime{}
+		webhooks := poker.NewWebhooks(poker.WithWebhookSleeper(sleeper.Sleep), poker.WithWebhookRetries(3, time.Second))
+
+		assertNoError(t, webhooks.Deliver(context.Background(), receiver.URL, event))
+
+		mock.AssertSleeps(t, sleeper, time.Second, 2*time.Second)
+		assertWebhooksReceived(t, receiver, event)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		sleeper := &mock.SpyTime{}
+		webhooks := poker.NewWebhooks(poker.WithWebhookSleeper(sleeper.Sleep), poker.WithWebhookRetries(2, time.Second))
+
+		err := webhooks.Deliver(context.Background(), receiver.URL, event)
+
+		assertError(t, err)
+		mock.AssertSleeps(t, sleeper, time.Second)
+		assertAttempts(t, receiver, 2)
+	})
+
+	t.Run("doesn't retry when the request is rejected", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusNotFound)
+		sleeper := &mock.SpyTime{}
+		webhooks := poker.NewWebhooks(poker.WithWebhookSleeper(sleeper.Sleep))
+
+		err := webhooks.Deliver(context.Background(), receiver.URL, event)
+
+		assertError(t, err)
+		mock.AssertSleeps(t, sleeper)
+		assertAttempts(t, receiver, 1)
+	})
+
+	t.Run("retries when the service can't be reached", func(t *testing.T) {
+		receiver := newWebhookReceiver(t)
+		receiver.Close()
+		sleeper := &mock.SpyTime{}
+		webhooks := poker.NewWebhooks(poker.WithWebhookSleeper(sleeper.Sleep), poker.WithWebhookRetries(3, time.Second))
+
+		assertError(t, webhooks.Deliver(context.Background(), receiver.URL, event))
+		mock.AssertSleeps(t, sleeper, time.Second, 2*time.Second)
+	})
+
+	t.Run("failed deliveries are reported to the error handler", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusBadRequest)
+
+		var lock sync.Mutex
+		var failed []string
+		webhooks := poker.NewWebhooks(poker.WithWebhookErrorHandler(func(url string, err error) {
+			lock.Lock()
+			defer lock.Unlock()
+			failed = append(failed, url)
+		}))
+		assertNoError(t, webhooks.Register(receiver.URL))
+
+		webhooks.Notify(event)
+		assertNoError(t, webhooks.Wait(context.Background()))
+
+		if !reflect.DeepEqual(failed, []string{receiver.URL}) {
+			t.Errorf("got failures for %v, want %v", failed, []string{receiver.URL})
+		}
+	})
+
+	t.Run("only http and https URLs can be registered", func(t *testing.T) {
+		webhooks := poker.NewWebhooks()
+
+		for _, url := range []string{"", "localhost:8080", "ftp://example.com", "https://"} {
+			if err := webhooks.Register(url); !apperrors.IsInvalid(err) {
+				t.Errorf("registering %q got %v, want an invalid error", url, err)
+			}
+		}
+	})
+}
+
+// webhookReceiver is a service webhooks are sent to. It answers with each of its statuses in
+// turn, then 200 OK, keeping the events it accepted.
+type webhookReceiver struct {
+	*httptest.Server
+	statuses []int
+	attempts atomic.Int32
+
+	lock     sync.Mutex
+	received []poker.GameFinished
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	t.Helper()
+
+	receiver := &webhookReceiver{statuses: statuses}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(receiver.serveHTTP))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+func (r *webhookReceiver) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	attempt := int(r.attempts.Add(1))
+	if attempt <= len(r.statuses) {
+		w.WriteHeader(r.statuses[attempt-1])
+		return
+	}
+
+	if req.Method != http.MethodPost || req.Header.Get("content-type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var event poker.GameFinished
+	if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.received = append(r.received, event)
+}
+
+func assertWebhooksReceived(t testing.TB, receiver *webhookReceiver, want ...poker.GameFinished) {
+	t.Helper()
+
+	receiver.lock.Lock()
+	defer receiver.lock.Unlock()
+	if !reflect.DeepEqual(receiver.received, want) {
+		t.Errorf("received %+v want %+v", receiver.received, want)
+	}
+}
+
+func assertAttempts(t testing.TB, receiver *webhookReceiver, want int) {
+	t.Helper()
+
+	if got := int(receiver.attempts.Load()); got != want {
+		t.Errorf("got %d attempts want %d", got, want)
+	}
+}
+
+func assertError(t testing.TB, err error) {
+	t.Helper()
+
+	if err == nil {
+		t.Error("wanted an error but didn't get one")
+	}
+}
@@ -0,0 +1,143 @@
+package poker
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+)
+
+// WinHistory is the winner of every game, in the order they were played.
+type WinHistory interface {
+	Winners() []string
+}
+
+// WithWinHistory serves each player's stats, worked out from history, at /players/{name}/stats.
+func WithWinHistory(history WinHistory) PlayerServerOption {
+	return func(p *PlayerServer) {
+		p.history = history
+	}
+}
+
+// WinLog is a WinHistory kept by appending each winner to a file, one per line.
+type WinLog struct {
+	lock    sync.RWMutex
+	out     io.Writer
+	winners []string
+}
+
+// NewWinLog reads the winners already in rw, then appends new ones to it.
+func NewWinLog(rw io.ReadWriter) (*WinLog, error) {
+	log := &WinLog{out: rw}
+
+	scanner := bufio.NewScanner(rw)
+	for scanner.Scan() {
+		if winner := scanner.Text(); winner != "" {
+			log.winners = append(log.winners, winner)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("problem reading win log, %v", err)
+	}
+
+	return log, nil
+}
+
+// WinLogFromFile opens, or creates, the win log at path.
+func WinLogFromFile(path string) (*WinLog, func(), error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
+	}
+
+	log, err := NewWinLog(file)
+
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return log, func() { file.Close() }, nil
+}
+
+// Record appends winner to the log. Winners with line breaks in their names are rejected, as
+// they'd be read back as more than one winner.
+func (w *WinLog) Record(winner string) error {
+	if strings.ContainsAny(winner, "\r\n") {
+		return apperrors.Invalid("winner", "cannot contain line breaks", nil)
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if _, err := fmt.Fprintln(w.out, winner); err != nil {
+		return fmt.Errorf("problem recording win for %s, %v", winner, err)
+	}
+
+	w.winners = append(w.winners, winner)
+	return nil
+}
+
+// Winners returns every winner so far, oldest first.
+func (w *WinLog) Winners() []string {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return append([]string(nil), w.winners...)
+}
+
+// HistoryPlayerStore is a PlayerStore which also keeps a WinLog of every win it records.
+type HistoryPlayerStore struct {
+	PlayerStore
+	log     *WinLog
+	onError func(name string, err error)
+}
+
+// HistoryOption configures optional behaviour of a HistoryPlayerStore.
+type HistoryOption func(*HistoryPlayerStore)
+
+// WithHistoryErrorHandler is told about every win which couldn't be logged, instead of it being
+// written to the standard logger.
+func WithHistoryErrorHandler(onError func(name string, err error)) HistoryOption {
+	return func(h *HistoryPlayerStore) {
+		h.onError = onError
+	}
+}
+
+// NewHistoryPlayerStore records wins in winLog as well as in store.
+func NewHistoryPlayerStore(store PlayerStore, winLog *WinLog, options ...HistoryOption) *HistoryPlayerStore {
+	h := &HistoryPlayerStore{
+		PlayerStore: store,
+		log:         winLog,
+		onError: func(name string, err error) {
+			log.Printf("problem logging win for %q, %v", name, err)
+		},
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
+}
+
+// RecordWin records the win in the wrapped store and then in the log. The store's scores stay
+// the source of truth, so if the log can't be written to the win still counts, and the error
+// goes to the error handler.
+func (h *HistoryPlayerStore) RecordWin(name string) {
+	h.PlayerStore.RecordWin(name)
+	if err := h.log.Record(name); err != nil {
+		h.onError(name, err)
+	}
+}
+
+// Winners returns every winner logged so far, oldest first.
+func (h *HistoryPlayerStore) Winners() []string {
+	return h.log.Winners()
+}
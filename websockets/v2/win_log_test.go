@@ -0,0 +1,98 @@
+package poker_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/quii/learn-go-with-tests/q-and-a/error-types/apperrors"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+)
+
+func TestWinLog(t *testing.T) {
+	t.Run("reads the winners already logged and appends new ones", func(t *testing.T) {
+		file := bytes.NewBufferString("Chris\nCleo\n")
+
+		log, err := poker.NewWinLog(file)
+		assertNoError(t, err)
+		assertNoError(t, log.Record("Ruth"))
+
+		assertWinners(t, log.Winners(), []string{"Chris", "Cleo", "Ruth"})
+
+		if got := file.String(); got != "Ruth\n" {
+			t.Errorf("expected only the new winner to be appended, got %q", got)
+		}
+	})
+
+	t.Run("rejects winners with line breaks, which would read back as two", func(t *testing.T) {
+		file := &bytes.Buffer{}
+
+		log, err := poker.NewWinLog(file)
+		assertNoError(t, err)
+
+		for _, winner := range []string{"Chris\nCleo", "Chris\r"} {
+			if err := log.Record(winner); !apperrors.IsInvalid(err) {
+				t.Errorf("recording %q got error %v want an invalid error", winner, err)
+			}
+		}
+
+		assertWinners(t, log.Winners(), nil)
+		if file.Len() != 0 {
+			t.Errorf("expected nothing to be logged, got %q", file.String())
+		}
+	})
+
+	t.Run("winners survive reopening a log file", func(t *testing.T) {
+		path := t.TempDir() + "/wins.log"
+
+		log, closeLog, err := poker.WinLogFromFile(path)
+		assertNoError(t, err)
+		assertNoError(t, log.Record("Chris"))
+		assertNoError(t, log.Record("Cleo"))
+		closeLog()
+
+		reopened, closeLog, err := poker.WinLogFromFile(path)
+		assertNoError(t, err)
+		defer closeLog()
+
+		assertWinners(t, reopened.Winners(), []string{"Chris", "Cleo"})
+	})
+
+	t.Run("HistoryPlayerStore records wins in the store and the log", func(t *testing.T) {
+		log, err := poker.NewWinLog(&bytes.Buffer{})
+		assertNoError(t, err)
+
+		stub := &poker.StubPlayerStore{}
+		store := poker.NewHistoryPlayerStore(stub, log)
+
+		store.RecordWin("Pepper")
+
+		poker.AssertPlayerWin(t, stub, "Pepper")
+		assertWinners(t, store.Winners(), []string{"Pepper"})
+	})
+
+	t.Run("HistoryPlayerStore still records wins it can't log, and reports why", func(t *testing.T) {
+		log, err := poker.NewWinLog(&bytes.Buffer{})
+		assertNoError(t, err)
+
+		var reported error
+		stub := &poker.StubPlayerStore{}
+		store := poker.NewHistoryPlayerStore(stub, log, poker.WithHistoryErrorHandler(func(name string, err error) {
+			reported = err
+		}))
+
+		store.RecordWin("Pepper\nSalt")
+
+		poker.AssertPlayerWin(t, stub, "Pepper\nSalt")
+		if !apperrors.IsInvalid(reported) {
+			t.Errorf("got error %v want an invalid error", reported)
+		}
+	})
+}
+
+func assertWinners(t testing.TB, got, want []string) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got winners %v want %v", got, want)
+	}
+}
@@ -0,0 +1,363 @@
+package poker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Encoding is how the messages sent to a player during a game are put on the wire. A client
+// picks one by asking for its Subprotocol when it opens the websocket; clients that don't ask
+// for one get TextEncoding. Messages from the player are always plain text.
+type Encoding interface {
+	Subprotocol() string
+	MessageType() int
+	EncodeAlert(text string) ([]byte, error)
+	EncodeGameState(state GameState) ([]byte, error)
+	EncodePresence(presence Presence) ([]byte, error)
+	EncodeSeating(seating Seating) ([]byte, error)
+}
+
+// ServerMessage is a decoded message sent to a player, holding an Alert, a GameState, a Presence
+// or a Seating.
+type ServerMessage struct {
+	Alert     string
+	GameState *GameState
+	Presence  *Presence
+	Seating   *Seating
+}
+
+// TextEncoding is the original protocol. Blind alerts are sent as they were written, and the
+// GameState of a resumed game, Presence events and Seating are sent as JSON.
+type TextEncoding struct{}
+
+// Subprotocol is empty, as TextEncoding is what clients get when they don't ask for a subprotocol.
+func (TextEncoding) Subprotocol() string { return "" }
+
+// MessageType is websocket.TextMessage.
+func (TextEncoding) MessageType() int { return websocket.TextMessage }
+
+// EncodeAlert returns text unchanged.
+func (TextEncoding) EncodeAlert(text string) ([]byte, error) {
+	return []byte(text), nil
+}
+
+// EncodeGameState returns state as JSON.
+func (TextEncoding) EncodeGameState(state GameState) ([]byte, error) {
+	return json.Marshal(state)
+}
+
+// EncodePresence returns presence as JSON.
+func (TextEncoding) EncodePresence(presence Presence) ([]byte, error) {
+	return json.Marshal(presence)
+}
+
+// EncodeSeating returns seating as JSON.
+func (TextEncoding) EncodeSeating(seating Seating) ([]byte, error) {
+	return json.Marshal(seating)
+}
+
+// Decode reads a message written by TextEncoding. A JSON object with a presence field is a
+// Presence, one with a seats field is a Seating, any other JSON object is a GameState and
+// anything else is an alert.
+func (TextEncoding) Decode(data []byte) (ServerMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return ServerMessage{Alert: string(data)}, nil
+	}
+
+	if _, ok := fields["presence"]; ok {
+		var presence Presence
+		err := json.Unmarshal(data, &presence)
+		return ServerMessage{Presence: &presence}, err
+	}
+
+	if _, ok := fields["seats"]; ok {
+		var seating Seating
+		err := json.Unmarshal(data, &seating)
+		return ServerMessage{Seating: &seating}, err
+	}
+
+	var state GameState
+	err := json.Unmarshal(data, &state)
+	return ServerMessage{GameState: &state}, err
+}
+
+// ProtobufSubprotocol is the websocket subprotocol for ProtobufEncoding.
+const ProtobufSubprotocol = "poker.protobuf"
+
+// ProtobufEncoding sends every message as a binary ServerMessage, described in game_messages.proto.
+type ProtobufEncoding struct{}
+
+// Field numbers from game_messages.proto.
+const (
+	serverMessageBlindAlert protowire.Number = 1
+	serverMessageGameState  protowire.Number = 2
+	serverMessagePresence   protowire.Number = 3
+	serverMessageSeating    protowire.Number = 4
+
+	blindAlertText protowire.Number = 1
+
+	gameStatePlayers      protowire.Number = 1
+	gameStateBlind        protowire.Number = 2
+	gameStateElapsedNanos protowire.Number = 3
+
+	presenceEvent      protowire.Number = 1
+	presenceRole       protowire.Number = 2
+	presenceSpectators protowire.Number = 3
+
+	seatingSeats  protowire.Number = 1
+	seatingDealer protowire.Number = 2
+
+	seatNumber protowire.Number = 1
+	seatPlayer protowire.Number = 2
+)
+
+// Subprotocol is ProtobufSubprotocol.
+func (ProtobufEncoding) Subprotocol() string { return ProtobufSubprotocol }
+
+// MessageType is websocket.BinaryMessage.
+func (ProtobufEncoding) MessageType() int { return websocket.BinaryMessage }
+
+// EncodeAlert returns a ServerMessage holding a BlindAlert. The trailing newline the alerter
+// writes is dropped, as a message doesn't need one.
+func (ProtobufEncoding) EncodeAlert(text string) ([]byte, error) {
+	alert := appendString(nil, blindAlertText, strings.TrimSuffix(text, "\n"))
+	return appendMessage(nil, serverMessageBlindAlert, alert), nil
+}
+
+// EncodeGameState returns a ServerMessage holding a GameState.
+func (ProtobufEncoding) EncodeGameState(state GameState) ([]byte, error) {
+	var b []byte
+	b = appendVarint(b, gameStatePlayers, int64(state.Players))
+	b = appendVarint(b, gameStateBlind, int64(state.Blind))
+	b = appendVarint(b, gameStateElapsedNanos, int64(state.Elapsed))
+
+	return appendMessage(nil, serverMessageGameState, b), nil
+}
+
+// EncodePresence returns a ServerMessage holding a Presence.
+func (ProtobufEncoding) EncodePresence(presence Presence) ([]byte, error) {
+	var b []byte
+	b = appendString(b, presenceEvent, presence.Event)
+	b = appendString(b, presenceRole, presence.Role)
+	b = appendVarint(b, presenceSpectators, int64(presence.Spectators))
+
+	return appendMessage(nil, serverMessagePresence, b), nil
+}
+
+// EncodeSeating returns a ServerMessage holding a Seating.
+func (ProtobufEncoding) EncodeSeating(seating Seating) ([]byte, error) {
+	var b []byte
+	for _, seat := range seating.Seats {
+		var s []byte
+		s = appendVarint(s, seatNumber, int64(seat.Number))
+		s = appendString(s, seatPlayer, seat.Player)
+		b = appendMessage(b, seatingSeats, s)
+	}
+	b = appendVarint(b, seatingDealer, int64(seating.Dealer))
+
+	return appendMessage(nil, serverMessageSeating, b), nil
+}
+
+// Decode reads a ServerMessage. Fields it doesn't know are skipped, so newer servers can add them.
+func (ProtobufEncoding) Decode(data []byte) (ServerMessage, error) {
+	var msg ServerMessage
+
+	err := eachField(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == serverMessageBlindAlert && typ == protowire.BytesType:
+			return eachField(value, func(num protowire.Number, typ protowire.Type, value []byte) error {
+				if num == blindAlertText && typ == protowire.BytesType {
+					msg.Alert = string(value)
+				}
+				return nil
+			})
+		case num == serverMessageGameState && typ == protowire.BytesType:
+			state, err := decodeGameState(value)
+			msg.GameState = &state
+			return err
+		case num == serverMessagePresence && typ == protowire.BytesType:
+			presence, err := decodePresence(value)
+			msg.Presence = &presence
+			return err
+		case num == serverMessageSeating && typ == protowire.BytesType:
+			seating, err := decodeSeating(value)
+			msg.Seating = &seating
+			return err
+		}
+		return nil
+	})
+
+	if err != nil {
+		return ServerMessage{}, fmt.Errorf("problem decoding server message, %w", err)
+	}
+	return msg, nil
+}
+
+func decodeGameState(data []byte) (GameState, error) {
+	var state GameState
+
+	err := eachField(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.VarintType {
+			return nil
+		}
+
+		v, n := protowire.ConsumeVarint(value)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		switch num {
+		case gameStatePlayers:
+			state.Players = int(v)
+		case gameStateBlind:
+			state.Blind = int(v)
+		case gameStateElapsedNanos:
+			state.Elapsed = time.Duration(v)
+		}
+		return nil
+	})
+
+	return state, err
+}
+
+func decodePresence(data []byte) (Presence, error) {
+	var presence Presence
+
+	err := eachField(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == presenceEvent && typ == protowire.BytesType:
+			presence.Event = string(value)
+		case num == presenceRole && typ == protowire.BytesType:
+			presence.Role = string(value)
+		case num == presenceSpectators && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(value)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			presence.Spectators = int(v)
+		}
+		return nil
+	})
+
+	return presence, err
+}
+
+func decodeSeating(data []byte) (Seating, error) {
+	var seating Seating
+
+	err := eachField(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == seatingSeats && typ == protowire.BytesType:
+			seat, err := decodeSeat(value)
+			seating.Seats = append(seating.Seats, seat)
+			return err
+		case num == seatingDealer && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(value)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			seating.Dealer = int(v)
+		}
+		return nil
+	})
+
+	return seating, err
+}
+
+func decodeSeat(data []byte) (Seat, error) {
+	var seat Seat
+
+	err := eachField(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		switch {
+		case num == seatNumber && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(value)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			seat.Number = int(v)
+		case num == seatPlayer && typ == protowire.BytesType:
+			seat.Player = string(value)
+		}
+		return nil
+	})
+
+	return seat, err
+}
+
+// eachField calls fn with every field in the protobuf message data. For length delimited fields
+// value is the field's contents; otherwise it is the encoded value, still to be consumed.
+func eachField(data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		size := protowire.ConsumeFieldValue(num, typ, data)
+		if size < 0 {
+			return protowire.ParseError(size)
+		}
+
+		value := data[:size]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(value)
+		}
+
+		if err := fn(num, typ, value); err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+	return nil
+}
+
+func appendMessage(b []byte, num protowire.Number, message []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, message)
+}
+
+// appendString leaves out empty strings, as proto3 does.
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+// appendVarint leaves out zero values, as proto3 does.
+func appendVarint(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+var encodings = []Encoding{ProtobufEncoding{}}
+
+// wsSubprotocols are the subprotocols the server offers when upgrading a connection.
+func wsSubprotocols() []string {
+	var protocols []string
+	for _, e := range encodings {
+		protocols = append(protocols, e.Subprotocol())
+	}
+	return protocols
+}
+
+// encodingFor returns the Encoding for the subprotocol a connection agreed on.
+func encodingFor(subprotocol string) Encoding {
+	for _, e := range encodings {
+		if e.Subprotocol() == subprotocol {
+			return e
+		}
+	}
+	return TextEncoding{}
+}
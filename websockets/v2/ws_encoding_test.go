@@ -0,0 +1,123 @@
+package poker_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	poker "github.com/quii/learn-go-with-tests/websockets/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type decodingEncoding interface {
+	poker.Encoding
+	Decode(data []byte) (poker.ServerMessage, error)
+}
+
+func TestEncodings(t *testing.T) {
+	encodings := []struct {
+		name        string
+		encoding    decodingEncoding
+		subprotocol string
+		messageType int
+	}{
+		{"text", poker.TextEncoding{}, "", websocket.TextMessage},
+		{"protobuf", poker.ProtobufEncoding{}, poker.ProtobufSubprotocol, websocket.BinaryMessage},
+	}
+
+	for _, e := range encodings {
+		t.Run(e.name, func(t *testing.T) {
+			if got := e.encoding.Subprotocol(); got != e.subprotocol {
+				t.Errorf("got subprotocol %q want %q", got, e.subprotocol)
+			}
+			if got := e.encoding.MessageType(); got != e.messageType {
+				t.Errorf("got message type %d want %d", got, e.messageType)
+			}
+
+			t.Run("blind alerts", func(t *testing.T) {
+				data, err := e.encoding.EncodeAlert("Blind is now 100\n")
+				assertNoError(t, err)
+
+				got, err := e.encoding.Decode(data)
+				assertNoError(t, err)
+
+				if strings.TrimSuffix(got.Alert, "\n") != "Blind is now 100" || got.GameState != nil {
+					t.Errorf("got %+v, want the alert back", got)
+				}
+			})
+
+			t.Run("presence", func(t *testing.T) {
+				presence := poker.Presence{Event: poker.PresenceLeave, Role: poker.RoleSpectator, Spectators: 3}
+
+				data, err := e.encoding.EncodePresence(presence)
+				assertNoError(t, err)
+
+				got, err := e.encoding.Decode(data)
+				assertNoError(t, err)
+
+				if !reflect.DeepEqual(got, poker.ServerMessage{Presence: &presence}) {
+					t.Errorf("got %+v, want presence %+v", got, presence)
+				}
+			})
+
+			t.Run("seating", func(t *testing.T) {
+				seating := poker.Seating{
+					Seats:  []poker.Seat{{Number: 1, Player: "Chris"}, {Number: 3, Player: "Cleo"}},
+					Dealer: 3,
+				}
+
+				data, err := e.encoding.EncodeSeating(seating)
+				assertNoError(t, err)
+
+				got, err := e.encoding.Decode(data)
+				assertNoError(t, err)
+
+				if !reflect.DeepEqual(got, poker.ServerMessage{Seating: &seating}) {
+					t.Errorf("got %+v, want seating %+v", got, seating)
+				}
+			})
+
+			t.Run("game state", func(t *testing.T) {
+				for _, state := range []poker.GameState{
+					{},
+					{Players: 5, Blind: 400, Elapsed: 23*time.Minute + time.Second},
+				} {
+					data, err := e.encoding.EncodeGameState(state)
+					assertNoError(t, err)
+
+					got, err := e.encoding.Decode(data)
+					assertNoError(t, err)
+
+					if !reflect.DeepEqual(got, poker.ServerMessage{GameState: &state}) {
+						t.Errorf("got %+v, want game state %+v", got, state)
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestProtobufEncoding(t *testing.T) {
+	t.Run("skips fields it doesn't know about", func(t *testing.T) {
+		data, _ := poker.ProtobufEncoding{}.EncodeAlert("Blind is now 200")
+		data = protowire.AppendTag(data, 15, protowire.VarintType)
+		data = protowire.AppendVarint(data, 42)
+
+		got, err := poker.ProtobufEncoding{}.Decode(data)
+		assertNoError(t, err)
+
+		if got.Alert != "Blind is now 200" {
+			t.Errorf("got alert %q", got.Alert)
+		}
+	})
+
+	t.Run("returns an error for a truncated message", func(t *testing.T) {
+		data, _ := poker.ProtobufEncoding{}.EncodeAlert("Blind is now 200")
+
+		if _, err := (poker.ProtobufEncoding{}).Decode(data[:len(data)-3]); err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}